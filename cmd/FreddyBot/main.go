@@ -4,32 +4,72 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/api"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/container"
 	"github.com/leirbagxis/FreddyBot/internal/database"
 	"github.com/leirbagxis/FreddyBot/internal/telegram"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
-// Send any text message to the bot after the bot has been started
+// shutdownTimeout limita o tempo total para drenar filas e fechar conexões.
+const shutdownTimeout = 30 * time.Second
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	// 1. Infraestrutura e container único, compartilhado pelo bot e pela API
 	db := database.InitDB()
+	cache.GetRedisClient()
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
+	app := container.NewAppContainer(db, telegram.NewBot())
+	app.Start(ctx)
 
-	webhookHandler, tb := telegram.StartBot(db)
+	// 2. Bot (fila de posts, agendamentos e recebimento de updates)
+	bot, err := telegram.StartBot(ctx, app)
+	if err != nil {
+		logger.Error("APP", "Erro ao iniciar bot: %v", err)
+		_ = app.Shutdown(context.Background())
+		os.Exit(1)
+	}
 
+	// 3. API REST (e webhook)
+	apiDone := make(chan error, 1)
 	go func() {
-		if err := api.StartApi(db, webhookHandler, tb); err != nil {
-			logger.Error("APP", "Erro ao iniciar API: %v", err)
-			stop()
-		}
+		apiDone <- api.StartApi(ctx, app, bot.WebhookHandler)
 	}()
 
-	<-ctx.Done()
+	var apiErr error
+	apiStopped := false
+	select {
+	case <-ctx.Done():
+	case apiErr = <-apiDone:
+		apiStopped = true
+		stop()
+	}
 	logger.Info("APP", "🧹 Encerrando app com segurança...")
 
+	// Encerramento na ordem inversa: API primeiro (para de aceitar webhooks),
+	// depois o bot e as filas, por último Redis e banco.
+	if !apiStopped {
+		apiErr = <-apiDone
+	}
+	if apiErr != nil {
+		logger.Error("APP", "Erro na API: %v", apiErr)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := bot.Shutdown(shutdownCtx); err != nil {
+		logger.Error("APP", "Erro ao encerrar bot: %v", err)
+	}
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		logger.Error("APP", "Erro ao encerrar container: %v", err)
+	}
+	logger.Info("APP", "👋 App encerrado")
 }
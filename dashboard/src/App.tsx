@@ -1,11 +1,12 @@
 import { useState, useEffect, useCallback, memo } from 'react';
-import { DashboardData, Button, TelegramUser, AdminDashboardData, Channel, AuditResult } from './types';
+import { DashboardData, Button, TelegramUser, AdminDashboardData, Channel, AuditResult, CaptionRotation, CaptionOverflow, TranslateMode, AuthorSignature, ReactionMode, VoteSettings } from './types';
 import {
   login, fetchDashboardData, fetchUserChannels, fetchAdminDashboard,
   updateMessagePermission, updateButtonsPermission,
   createButton, deleteButton, updateButton, updateLayoutButtons,
   updateDefaultCaption, updateNewPackCaption, updateReactions, 
-  updateReactionPosition, updateDynamicLinks, transferChannel, fetchUserInfo,
+  updateReactionPosition, updateReactionMode, updateVoteSettings, updateDynamicLinks, updateProcessEdits, updateCaptionRotation, updateCaptionOverflow, updateTranslation, updateSignatureFooter, createAuthorSignature, updateAuthorSignature, deleteAuthorSignature, resetCaptionVariantStats,
+  transferChannel, fetchUserInfo,
   sendAdminNotice, NoticeButton, NoticeRequest, NoticeTarget, disconnectChannel, fetchAuditCheckBot
 } from './api';
 import { PermissionsCard } from './components/PermissionsCard';
@@ -13,6 +14,11 @@ import { ButtonGrid } from './components/ButtonGrid';
 import { CaptionCard } from './components/CaptionCard';
 import { NewPackCaptionCard } from './components/NewPackCaptionCard';
 import { ReactionsCard } from './components/ReactionsCard';
+import { CaptionPoolCard } from './components/CaptionPoolCard';
+import { TranslationCard } from './components/TranslationCard';
+import { SignatureCard } from './components/SignatureCard';
+import { VoteStatsCard } from './components/VoteStatsCard';
+import { VoteSettingsCard } from './components/VoteSettingsCard';
 import { AdminDashboard } from './components/AdminDashboard';
 import { DashboardInicioTab } from './components/DashboardInicioTab';
 import { TabBar, Tab } from './components/TabBar';
@@ -315,6 +321,153 @@ const DashboardContent = memo(function DashboardContent() {
     }
   }, [toast, data]);
 
+  const handleProcessEdits = useCallback(async (value: boolean) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, processEdits: value } };
+    });
+
+    try {
+      await updateProcessEdits(cid, value);
+      toast(`Reprocessar edições ${value ? 'ativado' : 'desativado'}`, value ? 'success' : 'info');
+    } catch {
+      setData(data);
+      toast(`Erro ao atualizar configuração`, 'error');
+    }
+  }, [toast, data]);
+
+  const handleCaptionRotation = useCallback(async (mode: CaptionRotation) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, captionRotation: mode } };
+    });
+
+    try {
+      await updateCaptionRotation(cid, mode);
+      toast(`Pool de legendas ${mode ? 'ativado' : 'desativado'}`, mode ? 'success' : 'info');
+    } catch {
+      setData(data);
+      toast(`Erro ao atualizar rotação`, 'error');
+    }
+  }, [toast, data]);
+
+  const handleCaptionOverflow = useCallback(async (policy: CaptionOverflow) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, captionOverflow: policy } };
+    });
+
+    try {
+      await updateCaptionOverflow(cid, policy);
+      toast(`Política de legendas longas atualizada`, 'success');
+    } catch {
+      setData(data);
+      toast(`Erro ao atualizar política`, 'error');
+    }
+  }, [toast, data]);
+
+  const handleTranslation = useCallback(async (language: string, mode: TranslateMode) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, translateLanguage: language, translateMode: mode } };
+    });
+
+    try {
+      await updateTranslation(cid, language, mode);
+      toast(`Tradução automática ${language ? 'atualizada' : 'desativada'}`, language ? 'success' : 'info');
+    } catch {
+      setData(data);
+      toast(`Erro ao atualizar tradução`, 'error');
+    }
+  }, [toast, data]);
+
+  const handleSignatureFooter = useCallback(async (enabled: boolean, format: string) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, signatureFooter: enabled, signatureFormat: format } };
+    });
+
+    try {
+      await updateSignatureFooter(cid, enabled, format);
+      toast(`Rodapé de assinatura ${enabled ? 'ativado' : 'desativado'}`, enabled ? 'success' : 'info');
+    } catch {
+      setData(data);
+      toast(`Erro ao atualizar rodapé de assinatura`, 'error');
+    }
+  }, [toast, data]);
+
+  const handleSaveSignature = useCallback(async (signature: string, label: string, signatureId?: string) => {
+    const cid = parseInt(String(channelId), 10);
+    try {
+      const resp = signatureId
+        ? await updateAuthorSignature(cid, signatureId, signature, label)
+        : await createAuthorSignature(cid, signature, label);
+      const saved: AuthorSignature = resp?.data || resp;
+      if (!saved?.signatureId) throw new Error("ID not returned from API");
+
+      setData(p => {
+        if (!p) return p;
+        const current = p.channel.authorSignatures || [];
+        const authorSignatures = signatureId
+          ? current.map(s => s.signatureId === signatureId ? saved : s)
+          : [...current, saved];
+        return { ...p, channel: { ...p.channel, authorSignatures } };
+      });
+      toast(`Apelido de "${signature}" salvo`, 'success');
+      return true;
+    } catch {
+      toast(`Erro ao salvar apelido`, 'error');
+      return false;
+    }
+  }, [toast, channelId]);
+
+  const handleDeleteSignature = useCallback(async (signatureId: string) => {
+    const cid = parseInt(String(channelId), 10);
+    try {
+      await deleteAuthorSignature(cid, signatureId);
+      setData(p => {
+        if (!p) return p;
+        const authorSignatures = (p.channel.authorSignatures || []).filter(s => s.signatureId !== signatureId);
+        return { ...p, channel: { ...p.channel, authorSignatures } };
+      });
+      toast(`Apelido excluído`, 'error');
+    } catch {
+      toast(`Erro ao excluir apelido`, 'error');
+    }
+  }, [toast, channelId]);
+
+  const handleResetVariantStats = useCallback(async () => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    try {
+      await resetCaptionVariantStats(cid);
+      setData(p => {
+        if (!p) return p;
+        const captionVariants = (p.channel.captionVariants || []).map(v => ({ ...v, usageCount: 0, lastUsedAt: null }));
+        return { ...p, channel: { ...p.channel, captionVariants } };
+      });
+      toast('Estatísticas zeradas', 'info');
+    } catch {
+      toast(`Erro ao zerar estatísticas`, 'error');
+    }
+  }, [toast, data]);
+
   const handleDynamicLinks = useCallback(async (field: string, value: boolean) => {
     if (!data) return;
     const cid = parseInt(String(channelId), 10);
@@ -585,6 +738,42 @@ const DashboardContent = memo(function DashboardContent() {
     }
   }, [toast, channelId, data]);
 
+  const handleReactionMode = useCallback(async (mode: ReactionMode) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, reactionMode: mode } };
+    });
+
+    try {
+      await updateReactionMode(cid, mode);
+      toast(mode === 'native' ? 'Reações nativas ativadas' : 'Botões de voto ativados', 'success');
+    } catch (err: any) {
+      setData(data);
+      toast(err.message || 'Erro ao atualizar modo de reações', 'error');
+    }
+  }, [toast, data]);
+
+  const handleVoteSettings = useCallback(async (settings: VoteSettings) => {
+    if (!data) return;
+    const cid = parseInt(String(channelId), 10);
+
+    setData(p => {
+      if (!p) return p;
+      return { ...p, channel: { ...p.channel, voteSettings: settings } };
+    });
+
+    try {
+      await updateVoteSettings(cid, settings);
+      toast('Votação atualizada', 'success');
+    } catch (err: any) {
+      setData(data);
+      toast(err.message || 'Erro ao atualizar votação', 'error');
+    }
+  }, [toast, data]);
+
   const getGreeting = useCallback(() => {
     const h = new Date().getHours();
     if (h < 12) return 'Bom dia';
@@ -972,7 +1161,12 @@ const DashboardContent = memo(function DashboardContent() {
 
           {!isChannels && !isAdmin && activeTab === 'legendas' && channel && (
             <div className="space-y-4 tab-content-wrapper">
-              <CaptionCard caption={channel.defaultCaption} onUpdate={handleUpdateCaption} />
+              <CaptionCard
+                caption={channel.defaultCaption}
+                overflow={channel.captionOverflow ?? 'shorten'}
+                onUpdate={handleUpdateCaption}
+                onOverflowChange={handleCaptionOverflow}
+              />
               <NewPackCaptionCard
                 caption={channel.newPackCaption}
                 messageButtons={channel.newPackMessageButtons ?? true}
@@ -981,7 +1175,38 @@ const DashboardContent = memo(function DashboardContent() {
                 replyToSticker={channel.newPackReplyToSticker ?? false}
                 onUpdate={handleUpdateNewPack}
               />
-              <ReactionsCard reactions={channel.reactions} onUpdate={handleUpdateReactions} />
+              <CaptionPoolCard
+                mode={channel.captionRotation ?? ''}
+                variants={channel.captionVariants ?? []}
+                onModeChange={handleCaptionRotation}
+                onResetStats={handleResetVariantStats}
+              />
+              <TranslationCard
+                language={channel.translateLanguage ?? ''}
+                mode={channel.translateMode ?? 'append'}
+                onUpdate={handleTranslation}
+              />
+              <SignatureCard
+                enabled={channel.signatureFooter ?? false}
+                format={channel.signatureFormat ?? '✍️ {author}'}
+                signatures={channel.authorSignatures ?? []}
+                onFooterChange={handleSignatureFooter}
+                onSave={handleSaveSignature}
+                onDelete={handleDeleteSignature}
+              />
+              <ReactionsCard
+                reactions={channel.reactions}
+                mode={channel.reactionMode ?? 'buttons'}
+                onUpdate={handleUpdateReactions}
+                onModeChange={handleReactionMode}
+              />
+              {(channel.reactionMode ?? 'buttons') === 'buttons' && (
+                <VoteSettingsCard
+                  settings={channel.voteSettings ?? { mode: 'single', hideCounts: false, durationMinutes: 0, showResults: false }}
+                  onUpdate={handleVoteSettings}
+                />
+              )}
+              <VoteStatsCard channelId={channel.id} />
             </div>
           )}
 
@@ -1120,6 +1345,42 @@ const DashboardContent = memo(function DashboardContent() {
                 </div>
               </div>
 
+              {/* Edições de Posts */}
+              <div className="card">
+                <div className="section-header">
+                  <div className="section-icon purple">
+                    <Type size={18} />
+                  </div>
+                  <div className="flex-1 min-w-0">
+                    <h3 className="text-[15px] font-semibold truncate">Edições de Posts</h3>
+                    <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+                      Reaplica legenda e botões quando um post é editado
+                    </p>
+                  </div>
+                  <span className={`badge ${channel.processEdits ? 'badge-accent' : 'badge-ghost'}`}>
+                    {channel.processEdits ? 'ON' : 'OFF'}
+                  </span>
+                </div>
+                <div
+                  className={`perm-row ${channel.processEdits ? 'on' : ''}`}
+                  onClick={() => handleProcessEdits(!channel.processEdits)}
+                >
+                  <div className="flex items-center gap-3 min-w-0">
+                    <span
+                      className="flex-shrink-0"
+                      style={{
+                        color: channel.processEdits ? 'var(--accent)' : 'var(--hint)',
+                        opacity: channel.processEdits ? 1 : 0.4
+                      }}
+                    >
+                      <Type size={16} />
+                    </span>
+                    <span className="text-[13px] font-medium">Reprocessar Posts Editados</span>
+                  </div>
+                  <div className={`toggle ${channel.processEdits ? 'on' : ''}`} />
+                </div>
+              </div>
+
               <PermissionsCard
                 title="Permissões de Mensagem"
                 icon={<MessageCircle size={18} />}
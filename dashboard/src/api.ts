@@ -1,4 +1,4 @@
-import { DashboardData, Button, Permission, ChannelsResponse, AdminDashboardData, AdminLogsFilters, AdminLogsResponse } from './types';
+import { DashboardData, Button, Permission, CaptionRotation, CaptionOverflow, TranslateMode, ReactionMode, VoteSettings, VoteAnalytics, ChannelsResponse, AdminDashboardData, AdminLogsFilters, AdminLogsResponse } from './types';
 
 export interface AuthRequestBody {
     channelID: number;
@@ -111,6 +111,20 @@ export const updateReactionPosition = async (channelId: number, reactionPosition
     });
 };
 
+export const updateReactionMode = async (channelId: number, mode: ReactionMode) => {
+    return apiFetch(`/api/channel/${channelId}/reactions/mode`, {
+        method: 'PUT',
+        body: JSON.stringify({ mode }),
+    });
+};
+
+export const updateVoteSettings = async (channelId: number, settings: VoteSettings) => {
+    return apiFetch(`/api/channel/${channelId}/votes/settings`, {
+        method: 'PUT',
+        body: JSON.stringify(settings),
+    });
+};
+
 export const updateDynamicLinks = async (channelId: number, settings: {
     dynamicLinks: boolean;
     dlBotButtons: boolean;
@@ -123,6 +137,77 @@ export const updateDynamicLinks = async (channelId: number, settings: {
     });
 };
 
+export const updateProcessEdits = async (channelId: number, processEdits: boolean) => {
+    return apiFetch(`/api/channel/${channelId}/edits`, {
+        method: 'PUT',
+        body: JSON.stringify({ processEdits }),
+    });
+};
+
+export const updateCaptionRotation = async (channelId: number, mode: CaptionRotation) => {
+    return apiFetch(`/api/channel/${channelId}/caption/rotation`, {
+        method: 'PUT',
+        body: JSON.stringify({ mode }),
+    });
+};
+
+export const updateCaptionOverflow = async (channelId: number, policy: CaptionOverflow) => {
+    return apiFetch(`/api/channel/${channelId}/caption/overflow`, {
+        method: 'PUT',
+        body: JSON.stringify({ policy }),
+    });
+};
+
+export const updateTranslation = async (channelId: number, language: string, mode: TranslateMode) => {
+    return apiFetch(`/api/channel/${channelId}/translation`, {
+        method: 'PUT',
+        body: JSON.stringify({ language, mode }),
+    });
+};
+
+export const updateSignatureFooter = async (channelId: number, enabled: boolean, format: string) => {
+    return apiFetch(`/api/channel/${channelId}/signatures/footer`, {
+        method: 'PUT',
+        body: JSON.stringify({ enabled, format }),
+    });
+};
+
+export const createAuthorSignature = async (channelId: number, signature: string, label: string) => {
+    return apiFetch(`/api/channel/${channelId}/signatures`, {
+        method: 'POST',
+        body: JSON.stringify({ signature, label }),
+    });
+};
+
+export const updateAuthorSignature = async (channelId: number, signatureId: string, signature: string, label: string) => {
+    return apiFetch(`/api/channel/${channelId}/signatures/${signatureId}`, {
+        method: 'PUT',
+        body: JSON.stringify({ signature, label }),
+    });
+};
+
+export const deleteAuthorSignature = async (channelId: number, signatureId: string) => {
+    return apiFetch(`/api/channel/${channelId}/signatures/${signatureId}`, {
+        method: 'DELETE',
+    });
+};
+
+export const fetchVoteAnalytics = async (channelId: number, days: number): Promise<VoteAnalytics | null> => {
+    const response = await apiFetch(`/api/channel/${channelId}/votes/analytics?days=${days}`, {
+        method: 'GET',
+    });
+    return response?.data || null;
+};
+
+export const voteExportUrl = (channelId: number, days: number, format: 'csv' | 'json') =>
+    `/api/channel/${channelId}/votes/export?days=${days}&format=${format}`;
+
+export const resetCaptionVariantStats = async (channelId: number) => {
+    return apiFetch(`/api/channel/${channelId}/caption/variants/stats`, {
+        method: 'DELETE',
+    });
+};
+
 export const updateMessagePermission = async (channelId: number, perms: Permission) => {
     const payload = {
         linkPreview: Boolean(perms.linkPreview),
@@ -18,6 +18,7 @@ const statusLabels: Record<string, string> = {
   success: 'Sucesso',
   error: 'Erro',
   skipped: 'Ignorado',
+  filtered: 'Filtrado',
   info: 'Info',
 };
 
@@ -54,6 +55,8 @@ function statusBadgeClass(status: string): string {
       return 'badge-danger';
     case 'skipped':
       return 'badge-warning';
+    case 'filtered':
+      return 'badge-accent';
     default:
       return 'badge-muted';
   }
@@ -126,6 +129,7 @@ export function AdminLogsTab({ navigateToChannel, initialChannelId = '' }: Admin
           <option value="success">Sucesso</option>
           <option value="error">Erro</option>
           <option value="skipped">Ignorado</option>
+          <option value="filtered">Filtrado</option>
           <option value="info">Info</option>
         </select>
         <div className="search-bar-container relative">
@@ -181,6 +185,7 @@ export function AdminLogsTab({ navigateToChannel, initialChannelId = '' }: Admin
                     <span>Actor: {event.actorId || '-'}</span>
                     <span>Mensagem: {event.telegramMessageId || '-'}</span>
                     <span>Sessão: {event.sessionId || '-'}</span>
+                    <span className="col-span-2 truncate">Correlação: {event.correlationId || '-'}</span>
                   </div>
                   {event.channelId !== 0 && (
                     <button className="btn btn-secondary w-full" onClick={() => navigateToChannel(event.channelId)}>
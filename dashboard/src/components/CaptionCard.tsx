@@ -1,14 +1,22 @@
 import { useState, useEffect, memo } from 'react';
-import { Caption } from '../types';
+import { Caption, CaptionOverflow } from '../types';
 import { FileText, Pencil, X, Check } from 'lucide-react';
 import { RichTextEditor } from './RichTextEditor';
 
 interface Props {
   caption: Caption;
+  overflow?: CaptionOverflow;
   onUpdate?: (text: string) => void;
+  onOverflowChange?: (policy: CaptionOverflow) => void;
 }
 
-export const CaptionCard = memo(({ caption, onUpdate }: Props) => {
+const overflowPolicies: { id: CaptionOverflow; label: string }[] = [
+  { id: 'shorten', label: 'Encurtar original' },
+  { id: 'drop', label: 'Omitir caption' },
+  { id: 'reply', label: 'Responder post' },
+];
+
+export const CaptionCard = memo(({ caption, overflow = 'shorten', onUpdate, onOverflowChange }: Props) => {
   const [editing, setEditing] = useState(false);
   const [text, setText] = useState(caption.caption);
 
@@ -54,6 +62,26 @@ export const CaptionCard = memo(({ caption, onUpdate }: Props) => {
           {caption.caption || <span style={{ opacity: 0.3, fontStyle: 'italic' }}>Sem caption definida</span>}
         </div>
       )}
+
+      {onOverflowChange && (
+        <div className="mt-3">
+          <p className="text-[11px] mb-2" style={{ color: 'var(--hint)' }}>
+            Quando o post passar do limite do Telegram (1024 em mídias, 4096 em textos)
+          </p>
+          <div className="grid grid-cols-3 gap-2">
+            {overflowPolicies.map(p => (
+              <button
+                key={p.id}
+                type="button"
+                className={`btn btn-sm ${overflow === p.id ? 'btn-primary' : 'btn-secondary'}`}
+                onClick={() => overflow !== p.id && onOverflowChange(p.id)}
+              >
+                {p.label}
+              </button>
+            ))}
+          </div>
+        </div>
+      )}
     </div>
   );
 });
@@ -0,0 +1,95 @@
+import { memo } from 'react';
+import { Shuffle, RotateCcw } from 'lucide-react';
+import { CaptionRotation, CaptionVariant } from '../types';
+
+interface Props {
+  mode: CaptionRotation;
+  variants: CaptionVariant[];
+  onModeChange: (mode: CaptionRotation) => void;
+  onResetStats: () => void;
+}
+
+const modes: { id: CaptionRotation; label: string }[] = [
+  { id: '', label: 'Desativado' },
+  { id: 'round_robin', label: 'Rodízio' },
+  { id: 'weighted', label: 'Por peso' },
+  { id: 'weekday', label: 'Por dia' },
+];
+
+const weekdayLabels = ['Dom', 'Seg', 'Ter', 'Qua', 'Qui', 'Sex', 'Sáb'];
+
+const formatWeekdays = (value: string) => {
+  const days = value.split(',').map(d => d.trim()).filter(Boolean);
+  if (days.length === 0) return 'Todos os dias';
+  return days.map(d => weekdayLabels[Number(d)] ?? d).join(', ');
+};
+
+export const CaptionPoolCard = memo(({ mode, variants, onModeChange, onResetStats }: Props) => {
+  const total = variants.reduce((sum, v) => sum + (v.usageCount || 0), 0);
+
+  return (
+    <div className="card">
+      <div className="section-header">
+        <div className="section-icon purple">
+          <Shuffle size={18} />
+        </div>
+        <div className="flex-1 min-w-0">
+          <h3 className="text-[15px] font-semibold truncate">Pool de Legendas</h3>
+          <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+            {variants.length} variante{variants.length === 1 ? '' : 's'} · {total} uso{total === 1 ? '' : 's'}
+          </p>
+        </div>
+        <span className={`badge ${mode ? 'badge-accent' : 'badge-ghost'}`}>
+          {mode ? 'ON' : 'OFF'}
+        </span>
+      </div>
+
+      <div className="grid grid-cols-2 gap-2 mt-3">
+        {modes.map(m => (
+          <button
+            key={m.id || 'off'}
+            type="button"
+            className={`btn btn-sm ${mode === m.id ? 'btn-primary' : 'btn-secondary'}`}
+            onClick={() => mode !== m.id && onModeChange(m.id)}
+          >
+            {m.label}
+          </button>
+        ))}
+      </div>
+
+      {variants.length === 0 ? (
+        <p className="text-[11px] mt-3" style={{ color: 'var(--hint)' }}>
+          Nenhuma variante cadastrada. Crie variantes pela API em <code>/caption/variants</code>.
+        </p>
+      ) : (
+        <div className="space-y-3 mt-4">
+          {variants.map(v => {
+            const share = total > 0 ? Math.round((v.usageCount / total) * 1000) / 10 : 0;
+            return (
+              <div key={v.variantId} style={{ opacity: v.enabled ? 1 : 0.5 }}>
+                <div className="flex items-center justify-between gap-2">
+                  <span className="text-[13px] font-medium truncate">{v.name || v.caption.slice(0, 32) || 'Sem nome'}</span>
+                  <span className="text-[11px] flex-shrink-0" style={{ color: 'var(--hint)' }}>
+                    {v.usageCount} · {share}%
+                  </span>
+                </div>
+                <div style={{ height: 6, borderRadius: 3, background: 'var(--surface)', marginTop: 4, overflow: 'hidden' }}>
+                  <div style={{ width: `${share}%`, height: '100%', background: 'var(--accent)' }} />
+                </div>
+                <p className="text-[10px] mt-1" style={{ color: 'var(--hint)' }}>
+                  Peso {v.weight} · {formatWeekdays(v.weekdays)}
+                  {v.buttons?.length ? ` · ${v.buttons.length} botão(ões)` : ''}
+                  {!v.enabled ? ' · desativada' : ''}
+                </p>
+              </div>
+            );
+          })}
+
+          <button type="button" className="btn btn-secondary btn-sm w-full" onClick={onResetStats} disabled={total === 0}>
+            <RotateCcw size={14} /> Zerar estatísticas
+          </button>
+        </div>
+      )}
+    </div>
+  );
+});
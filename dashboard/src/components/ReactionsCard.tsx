@@ -1,12 +1,15 @@
 import { useState, useEffect, memo } from 'react';
 import { SmilePlus, X } from 'lucide-react';
+import { ReactionMode } from '../types';
 
 interface ReactionsCardProps {
     reactions: string;
+    mode: ReactionMode;
     onUpdate: (reactions: string) => Promise<void>;
+    onModeChange: (mode: ReactionMode) => void;
 }
 
-export const ReactionsCard = memo(({ reactions, onUpdate }: ReactionsCardProps) => {
+export const ReactionsCard = memo(({ reactions, mode, onUpdate, onModeChange }: ReactionsCardProps) => {
     const [slots, setSlots] = useState<string[]>(['', '', '', '', '']);
     const [loading, setLoading] = useState(false);
 
@@ -79,6 +82,27 @@ export const ReactionsCard = memo(({ reactions, onUpdate }: ReactionsCardProps)
             </div>
 
             <div className="mt-4">
+                <div className="flex gap-2 mb-2">
+                    <button
+                        type="button"
+                        className={`btn btn-sm flex-1 ${mode === 'buttons' ? 'btn-primary' : 'btn-secondary'}`}
+                        onClick={() => mode !== 'buttons' && onModeChange('buttons')}
+                    >
+                        Botões de voto
+                    </button>
+                    <button
+                        type="button"
+                        className={`btn btn-sm flex-1 ${mode === 'native' ? 'btn-primary' : 'btn-secondary'}`}
+                        onClick={() => mode !== 'native' && onModeChange('native')}
+                    >
+                        Reações nativas
+                    </button>
+                </div>
+                <p className="text-[10px] mb-4" style={{ color: 'var(--hint)' }}>
+                    {mode === 'native'
+                        ? 'O bot reage ao post com o primeiro emoji e conta as reações do canal. Use emojis aceitos pelo Telegram e libere-os nas reações do canal.'
+                        : 'Os emojis viram botões abaixo do post e cada voto é registrado por usuário.'}
+                </p>
                 <div className="grid grid-cols-5 gap-2 mb-4">
                     {slots.map((slot, index) => (
                         <div key={index} className="relative group">
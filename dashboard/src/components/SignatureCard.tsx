@@ -0,0 +1,115 @@
+import { useState, useEffect, memo } from 'react';
+import { PenLine, Check, Trash2, X } from 'lucide-react';
+import { AuthorSignature } from '../types';
+
+interface Props {
+  enabled: boolean;
+  format: string;
+  signatures: AuthorSignature[];
+  onFooterChange: (enabled: boolean, format: string) => void;
+  onSave: (signature: string, label: string, signatureId?: string) => Promise<boolean>;
+  onDelete: (signatureId: string) => void;
+}
+
+export const SignatureCard = memo(({ enabled, format, signatures, onFooterChange, onSave, onDelete }: Props) => {
+  const [draftFormat, setDraftFormat] = useState(format);
+  const [editingId, setEditingId] = useState<string | undefined>();
+  const [signature, setSignature] = useState('');
+  const [label, setLabel] = useState('');
+
+  useEffect(() => { setDraftFormat(format); }, [format]);
+
+  const resetForm = () => {
+    setEditingId(undefined);
+    setSignature('');
+    setLabel('');
+  };
+
+  const handleSubmit = async () => {
+    if (!signature.trim() || !label.trim()) return;
+    if (await onSave(signature.trim(), label.trim(), editingId)) resetForm();
+  };
+
+  return (
+    <div className="card">
+      <div className="section-header">
+        <div className="section-icon purple">
+          <PenLine size={18} />
+        </div>
+        <div className="flex-1 min-w-0">
+          <h3 className="text-[15px] font-semibold truncate">Assinatura dos Admins</h3>
+          <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+            Rodapé com quem publicou o post (exige "Assinar mensagens" no canal)
+          </p>
+        </div>
+        <span className={`badge ${enabled ? 'badge-accent' : 'badge-ghost'}`}>
+          {enabled ? 'ON' : 'OFF'}
+        </span>
+      </div>
+
+      <div className={`perm-row ${enabled ? 'on' : ''}`} onClick={() => onFooterChange(!enabled, format)}>
+        <div className="flex items-center gap-3 min-w-0">
+          <span className="flex-shrink-0" style={{ color: enabled ? 'var(--accent)' : 'var(--hint)', opacity: enabled ? 1 : 0.4 }}>
+            <PenLine size={16} />
+          </span>
+          <span className="text-[13px] font-medium">Rodapé de Assinatura</span>
+        </div>
+        <div className={`toggle ${enabled ? 'on' : ''}`} />
+      </div>
+
+      <div className="flex items-center gap-2 mt-3">
+        <input
+          className="input flex-1"
+          value={draftFormat}
+          onChange={e => setDraftFormat(e.target.value)}
+          placeholder="✍️ {author}"
+        />
+        <button
+          type="button"
+          className="btn btn-primary btn-sm"
+          disabled={draftFormat === format}
+          onClick={() => onFooterChange(enabled, draftFormat)}
+        >
+          <Check size={14} />
+        </button>
+      </div>
+      <p className="text-[10px] mt-1" style={{ color: 'var(--hint)' }}>
+        Use <code>{'{author}'}</code> para o apelido do admin. A variável também funciona nas legendas.
+      </p>
+
+      {signatures.length > 0 && (
+        <div className="space-y-2 mt-4">
+          {signatures.map(s => (
+            <div key={s.signatureId} className="flex items-center justify-between gap-2">
+              <button
+                type="button"
+                className="flex-1 min-w-0 text-left text-[13px] truncate"
+                onClick={() => { setEditingId(s.signatureId); setSignature(s.signature); setLabel(s.label); }}
+              >
+                <span style={{ color: 'var(--hint)' }}>{s.signature}</span> → <span className="font-medium">{s.label}</span>
+              </button>
+              <button type="button" className="btn btn-secondary btn-sm" onClick={() => onDelete(s.signatureId)}>
+                <Trash2 size={14} />
+              </button>
+            </div>
+          ))}
+        </div>
+      )}
+
+      <div className="grid grid-cols-2 gap-2 mt-3">
+        <input className="input" value={signature} onChange={e => setSignature(e.target.value)} placeholder="Assinatura (ex: Ana Souza)" />
+        <input className="input" value={label} onChange={e => setLabel(e.target.value)} placeholder="Apelido ou emoji" />
+      </div>
+      <div className="flex gap-2 mt-2">
+        <button type="button" className="btn btn-primary btn-sm flex-1" disabled={!signature.trim() || !label.trim()} onClick={handleSubmit}>
+          <Check size={14} /> {editingId ? 'Salvar apelido' : 'Adicionar apelido'}
+        </button>
+        {editingId && (
+          <button type="button" className="btn btn-secondary btn-sm" onClick={resetForm}>
+            <X size={14} />
+          </button>
+        )}
+      </div>
+    </div>
+  );
+});
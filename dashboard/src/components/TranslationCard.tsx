@@ -0,0 +1,91 @@
+import { useState, useEffect, memo } from 'react';
+import { Languages, Check } from 'lucide-react';
+import { TranslateMode } from '../types';
+
+interface Props {
+  language: string;
+  mode: TranslateMode;
+  onUpdate: (language: string, mode: TranslateMode) => void;
+}
+
+const languages = [
+  { id: '', label: 'Desativada' },
+  { id: 'en', label: 'Inglês' },
+  { id: 'es', label: 'Espanhol' },
+  { id: 'pt', label: 'Português' },
+];
+
+const modes: { id: TranslateMode; label: string }[] = [
+  { id: 'append', label: 'Acrescentar' },
+  { id: 'replace', label: 'Substituir' },
+];
+
+export const TranslationCard = memo(({ language, mode, onUpdate }: Props) => {
+  const [custom, setCustom] = useState(language);
+
+  useEffect(() => { setCustom(language); }, [language]);
+
+  return (
+    <div className="card">
+      <div className="section-header">
+        <div className="section-icon purple">
+          <Languages size={18} />
+        </div>
+        <div className="flex-1 min-w-0">
+          <h3 className="text-[15px] font-semibold truncate">Tradução Automática</h3>
+          <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+            Traduz o texto dos posts, sem alterar a caption do canal
+          </p>
+        </div>
+        <span className={`badge ${language ? 'badge-accent' : 'badge-ghost'}`}>
+          {language ? language.toUpperCase() : 'OFF'}
+        </span>
+      </div>
+
+      <div className="grid grid-cols-2 gap-2 mt-3">
+        {languages.map(l => (
+          <button
+            key={l.id || 'off'}
+            type="button"
+            className={`btn btn-sm ${language === l.id ? 'btn-primary' : 'btn-secondary'}`}
+            onClick={() => language !== l.id && onUpdate(l.id, mode)}
+          >
+            {l.label}
+          </button>
+        ))}
+      </div>
+
+      <div className="flex items-center gap-2 mt-3">
+        <input
+          className="input flex-1"
+          value={custom}
+          onChange={e => setCustom(e.target.value.trim().toLowerCase())}
+          placeholder="Outro idioma (ex: fr, de, it)"
+        />
+        <button
+          type="button"
+          className="btn btn-primary btn-sm"
+          disabled={custom === language}
+          onClick={() => onUpdate(custom, mode)}
+        >
+          <Check size={14} />
+        </button>
+      </div>
+
+      {language && (
+        <div className="grid grid-cols-2 gap-2 mt-3">
+          {modes.map(m => (
+            <button
+              key={m.id}
+              type="button"
+              className={`btn btn-sm ${mode === m.id ? 'btn-primary' : 'btn-secondary'}`}
+              onClick={() => mode !== m.id && onUpdate(language, m.id)}
+            >
+              {m.label}
+            </button>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+});
@@ -0,0 +1,97 @@
+import { memo } from 'react';
+import { Vote, EyeOff, BarChart3 } from 'lucide-react';
+import { VoteMode, VoteSettings } from '../types';
+
+interface Props {
+  settings: VoteSettings;
+  onUpdate: (settings: VoteSettings) => void;
+}
+
+const modes: { id: VoteMode; label: string }[] = [
+  { id: 'single', label: 'Escolha única' },
+  { id: 'multiple', label: 'Múltipla escolha' },
+];
+
+const durations = [
+  { minutes: 0, label: 'Sem prazo' },
+  { minutes: 60, label: '1 hora' },
+  { minutes: 24 * 60, label: '24 horas' },
+  { minutes: 3 * 24 * 60, label: '3 dias' },
+  { minutes: 7 * 24 * 60, label: '7 dias' },
+];
+
+export const VoteSettingsCard = memo(({ settings, onUpdate }: Props) => {
+  const update = (patch: Partial<VoteSettings>) => onUpdate({ ...settings, ...patch });
+  const customDuration = !durations.some(d => d.minutes === settings.durationMinutes);
+
+  return (
+    <div className="card">
+      <div className="section-header">
+        <div className="section-icon purple">
+          <Vote size={18} />
+        </div>
+        <div className="flex-1 min-w-0">
+          <h3 className="text-[15px] font-semibold truncate">Modo de Votação</h3>
+          <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+            Regras dos botões de reação, aplicadas aos posts a partir do primeiro voto
+          </p>
+        </div>
+        <span className={`badge ${settings.durationMinutes > 0 ? 'badge-accent' : 'badge-ghost'}`}>
+          {settings.durationMinutes > 0 ? 'COM PRAZO' : 'ABERTA'}
+        </span>
+      </div>
+
+      <div className="grid grid-cols-2 gap-2 mt-3">
+        {modes.map(m => (
+          <button
+            key={m.id}
+            type="button"
+            className={`btn btn-sm ${settings.mode === m.id ? 'btn-primary' : 'btn-secondary'}`}
+            onClick={() => settings.mode !== m.id && update({ mode: m.id })}
+          >
+            {m.label}
+          </button>
+        ))}
+      </div>
+
+      <div className={`perm-row mt-3 ${settings.hideCounts ? 'on' : ''}`} onClick={() => update({ hideCounts: !settings.hideCounts })}>
+        <div className="flex items-center gap-3 min-w-0">
+          <span className="flex-shrink-0" style={{ color: settings.hideCounts ? 'var(--accent)' : 'var(--hint)', opacity: settings.hideCounts ? 1 : 0.4 }}>
+            <EyeOff size={16} />
+          </span>
+          <span className="text-[13px] font-medium">Ocultar contagens até votar</span>
+        </div>
+        <div className={`toggle ${settings.hideCounts ? 'on' : ''}`} />
+      </div>
+
+      <div className={`perm-row ${settings.showResults ? 'on' : ''}`} onClick={() => update({ showResults: !settings.showResults })}>
+        <div className="flex items-center gap-3 min-w-0">
+          <span className="flex-shrink-0" style={{ color: settings.showResults ? 'var(--accent)' : 'var(--hint)', opacity: settings.showResults ? 1 : 0.4 }}>
+            <BarChart3 size={16} />
+          </span>
+          <span className="text-[13px] font-medium">Botão 📊 de resultados</span>
+        </div>
+        <div className={`toggle ${settings.showResults ? 'on' : ''}`} />
+      </div>
+
+      <p className="text-xs font-medium mt-3" style={{ color: 'var(--hint)' }}>Encerrar votação após</p>
+      <div className="grid grid-cols-3 gap-2 mt-2">
+        {durations.map(d => (
+          <button
+            key={d.minutes}
+            type="button"
+            className={`btn btn-sm ${settings.durationMinutes === d.minutes ? 'btn-primary' : 'btn-secondary'}`}
+            onClick={() => settings.durationMinutes !== d.minutes && update({ durationMinutes: d.minutes })}
+          >
+            {d.label}
+          </button>
+        ))}
+        {customDuration && (
+          <button type="button" className="btn btn-sm btn-primary">
+            {settings.durationMinutes} min
+          </button>
+        )}
+      </div>
+    </div>
+  );
+});
@@ -0,0 +1,127 @@
+import { useEffect, useState, memo } from 'react';
+import { BarChart3, Download, RefreshCcw } from 'lucide-react';
+import { fetchVoteAnalytics, voteExportUrl } from '../api';
+import { VoteAnalytics } from '../types';
+
+interface Props {
+  channelId: number;
+}
+
+const periods = [7, 30, 90];
+
+export const VoteStatsCard = memo(({ channelId }: Props) => {
+  const [days, setDays] = useState(30);
+  const [stats, setStats] = useState<VoteAnalytics | null>(null);
+  const [loading, setLoading] = useState(false);
+  const [failed, setFailed] = useState(false);
+
+  const load = async (period: number) => {
+    setLoading(true);
+    setFailed(false);
+    try {
+      setStats(await fetchVoteAnalytics(channelId, period));
+    } catch {
+      setFailed(true);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => { load(days); }, [channelId, days]);
+
+  const maxDay = Math.max(1, ...(stats?.byDay || []).map(d => d.votes));
+
+  return (
+    <div className="card">
+      <div className="section-header">
+        <div className="section-icon purple">
+          <BarChart3 size={18} />
+        </div>
+        <div className="flex-1 min-w-0">
+          <h3 className="text-[15px] font-semibold truncate">Estatísticas de Votos</h3>
+          <p className="text-xs mt-0.5" style={{ color: 'var(--hint)' }}>
+            Votos nos botões e reações nativas dos posts
+          </p>
+        </div>
+        <button type="button" className="btn btn-secondary btn-sm" onClick={() => load(days)} disabled={loading}>
+          <RefreshCcw size={14} />
+        </button>
+      </div>
+
+      <div className="flex gap-2">
+        {periods.map(p => (
+          <button
+            key={p}
+            type="button"
+            className={`btn btn-sm flex-1 ${days === p ? 'btn-primary' : 'btn-secondary'}`}
+            onClick={() => setDays(p)}
+          >
+            {p} dias
+          </button>
+        ))}
+      </div>
+
+      {failed && (
+        <p className="text-xs mt-3" style={{ color: 'var(--hint)' }}>Não foi possível carregar as estatísticas.</p>
+      )}
+
+      {stats && (
+        <>
+          <div className="grid grid-cols-3 gap-2 mt-4 text-center">
+            <div>
+              <div className="text-lg font-semibold">{stats.totalVotes}</div>
+              <div className="text-[10px]" style={{ color: 'var(--hint)' }}>Votos</div>
+            </div>
+            <div>
+              <div className="text-lg font-semibold">{stats.uniqueVoters}</div>
+              <div className="text-[10px]" style={{ color: 'var(--hint)' }}>Votantes</div>
+            </div>
+            <div>
+              <div className="text-lg font-semibold">{stats.posts}</div>
+              <div className="text-[10px]" style={{ color: 'var(--hint)' }}>Posts</div>
+            </div>
+          </div>
+
+          <div className="flex items-end gap-[2px] h-16 mt-4">
+            {stats.byDay.map(d => (
+              <div
+                key={d.date}
+                title={`${d.date}: ${d.votes}`}
+                className="flex-1 rounded-sm"
+                style={{ height: `${Math.max(4, (d.votes / maxDay) * 100)}%`, background: d.votes ? 'var(--accent)' : 'var(--hint)', opacity: d.votes ? 1 : 0.2 }}
+              />
+            ))}
+          </div>
+
+          {stats.byEmoji.length > 0 && (
+            <div className="flex flex-wrap gap-2 mt-4">
+              {stats.byEmoji.map(e => (
+                <span key={e.emoji} className="badge badge-ghost">{e.emoji} {e.votes} · {e.share}%</span>
+              ))}
+            </div>
+          )}
+
+          {stats.topPosts.length > 0 && (
+            <div className="space-y-1 mt-4">
+              {stats.topPosts.map((p, i) => (
+                <a key={p.messageId} href={p.link} target="_blank" rel="noreferrer" className="flex justify-between text-[13px]">
+                  <span>{i + 1}. Post {p.messageId}</span>
+                  <span className="font-medium">{p.votes}</span>
+                </a>
+              ))}
+            </div>
+          )}
+
+          <div className="flex gap-2 mt-4">
+            <a className="btn btn-secondary btn-sm flex-1" href={voteExportUrl(channelId, days, 'csv')} download>
+              <Download size={14} /> CSV
+            </a>
+            <a className="btn btn-secondary btn-sm flex-1" href={voteExportUrl(channelId, days, 'json')} download>
+              <Download size={14} /> JSON
+            </a>
+          </div>
+        </>
+      )}
+    </div>
+  );
+});
@@ -49,6 +49,43 @@ export interface Button {
   updated_at: string;
 }
 
+export type CaptionRotation = '' | 'round_robin' | 'weighted' | 'weekday';
+
+export type CaptionOverflow = 'shorten' | 'drop' | 'reply';
+
+export type TranslateMode = 'append' | 'replace';
+
+export type ReactionMode = 'buttons' | 'native';
+
+export type VoteMode = 'single' | 'multiple';
+
+export interface VoteSettings {
+  mode: VoteMode;
+  hideCounts: boolean;
+  durationMinutes: number;
+  showResults: boolean;
+}
+
+export interface AuthorSignature {
+  signatureId: string;
+  signature: string;
+  label: string;
+  created_at: string;
+}
+
+export interface CaptionVariant {
+  variantId: string;
+  name: string;
+  caption: string;
+  weight: number;
+  weekdays: string;
+  enabled: boolean;
+  usageCount: number;
+  lastUsedAt: string | null;
+  buttons: Button[];
+  created_at: string;
+}
+
 export interface Channel {
   id: number;
   title: string;
@@ -61,10 +98,21 @@ export interface Channel {
   ownerId: number;
   reactions: string;
   reactionPosition: number;
+  reactionMode?: ReactionMode;
+  voteSettings?: VoteSettings;
   dynamicLinks: boolean;
   dlBotButtons: boolean;
   dlBotCaptions: boolean;
   dlBotReactions: boolean;
+  processEdits: boolean;
+  captionRotation?: CaptionRotation;
+  captionOverflow?: CaptionOverflow;
+  translateLanguage?: string;
+  translateMode?: TranslateMode;
+  signatureFooter?: boolean;
+  signatureFormat?: string;
+  authorSignatures?: AuthorSignature[];
+  captionVariants?: CaptionVariant[];
   defaultCaption: Caption;
   buttons: Button[];
   customCaptions: Caption[];
@@ -103,11 +151,24 @@ export interface ChannelEvent {
   messageType: string;
   telegramMessageId: number;
   sessionId: string;
+  correlationId: string;
   errorMessage: string;
   metadata: string;
   created_at: string;
 }
 
+export interface VoteAnalytics {
+  days: number;
+  since: string;
+  totalVotes: number;
+  uniqueVoters: number;
+  nativeReactions: number;
+  posts: number;
+  byEmoji: { emoji: string; votes: number; share: number }[];
+  byDay: { date: string; votes: number }[];
+  topPosts: { messageId: number; link: string; votes: number; emojis?: Record<string, number> }[];
+}
+
 export interface AdminLogsResponse {
   events: ChannelEvent[];
   total: number;
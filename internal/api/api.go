@@ -3,33 +3,31 @@ package api
 import (
 	"context"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/mymmrac/telego"
+	"github.com/leirbagxis/FreddyBot/internal/api/middleware"
 	"github.com/leirbagxis/FreddyBot/internal/api/routes"
 	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
 	"github.com/leirbagxis/FreddyBot/internal/utils"
 	"github.com/leirbagxis/FreddyBot/pkg/config"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
-	"gorm.io/gorm"
 )
 
-func StartApi(db *gorm.DB, webhookHandler http.Handler, tb *telego.Bot) error {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
-
-	app := container.NewAppContainer(db, tb)
+// StartApi sobe o servidor HTTP com o container compartilhado e bloqueia até
+// ctx ser cancelado, quando encerra o servidor aguardando as requisições em andamento.
+func StartApi(ctx context.Context, app *container.AppContainer, webhookHandler http.Handler) error {
 	router := gin.Default() // Usar Default para ter Logger e Recovery
+	// Permite usar o *gin.Context como context.Context com os campos de log da requisição.
+	router.ContextWithFallback = true
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     config.CORSAllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
 		AllowHeaders:     []string{"Content-Type", "Authorization", "x-telegram-init-data"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -42,6 +40,14 @@ func StartApi(db *gorm.DB, webhookHandler http.Handler, tb *telego.Bot) error {
 		router.POST("/webhook", gin.WrapH(webhookHandler))
 	}
 
+	// Métricas Prometheus (bot e API rodam no mesmo processo). Sem token o
+	// endpoint fica desligado, para não expor as métricas publicamente.
+	if config.MetricsToken != "" {
+		router.GET("/metrics", middleware.MetricsAuth(config.MetricsToken), gin.WrapH(metrics.Handler()))
+	} else {
+		logger.Warn("API", "⚠️ METRICS_TOKEN não definido: endpoint /metrics desativado")
+	}
+
 	// 1. Arquivos estáticos PRECISAM vir antes das rotas dinâmicas
 	router.Static("/assets", "./dashboard/dist/assets")
 	router.Static("/dashboard/assets", "./dashboard/dist/assets")
@@ -98,15 +104,19 @@ func StartApi(db *gorm.DB, webhookHandler http.Handler, tb *telego.Bot) error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	serveErr := make(chan error, 1)
 	go func() {
 		logger.API("🌐 API REST rodando em http://localhost%s", port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Error("API", "Erro ao iniciar servidor: %v", err)
-			os.Exit(1)
+			serveErr <- err
 		}
 	}()
 
-	<-ctx.Done()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
 	logger.API("🔻 Encerrando API...")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -21,16 +21,17 @@ func NewChannelEventsController(app *container.AppContainer) *ChannelEventsContr
 
 func (c *ChannelEventsController) List(ctx *gin.Context) {
 	filters := services.ChannelEventListFilters{
-		ChannelID: parseInt64Query(ctx, "channelId"),
-		OwnerID:   parseInt64Query(ctx, "ownerId"),
-		ActorID:   parseInt64Query(ctx, "actorId"),
-		Source:    ctx.Query("source"),
-		EventType: ctx.Query("eventType"),
-		Status:    ctx.Query("status"),
-		SessionID: ctx.Query("sessionId"),
-		Query:     ctx.Query("q"),
-		Limit:     parseIntQuery(ctx, "limit", 50),
-		Offset:    parseIntQuery(ctx, "offset", 0),
+		ChannelID:     parseInt64Query(ctx, "channelId"),
+		OwnerID:       parseInt64Query(ctx, "ownerId"),
+		ActorID:       parseInt64Query(ctx, "actorId"),
+		Source:        ctx.Query("source"),
+		EventType:     ctx.Query("eventType"),
+		Status:        ctx.Query("status"),
+		SessionID:     ctx.Query("sessionId"),
+		CorrelationID: ctx.Query("correlationId"),
+		Query:         ctx.Query("q"),
+		Limit:         parseIntQuery(ctx, "limit", 50),
+		Offset:        parseIntQuery(ctx, "offset", 0),
 	}
 
 	if dateFrom := parseTimeQuery(ctx, "dateFrom"); dateFrom != nil {
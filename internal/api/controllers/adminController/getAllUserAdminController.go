@@ -125,6 +125,18 @@ func (c *UsersAdminController) dispatchNotice(notice NoticeRequest) {
 		})
 	}
 
+	var jobs []container.BroadcastJob
+	defer func() {
+		if len(jobs) == 0 {
+			return
+		}
+		if err := c.container.EnqueueBroadcast(ctx, jobs...); err != nil {
+			logger.Error("API", "Erro ao enfileirar broadcast: %v", err)
+			return
+		}
+		logger.API("📨 Broadcast enfileirado para %d destino(s)", len(jobs))
+	}()
+
 	baseText := utils.MarkdownToTelegramHTML(notice.Message)
 	supportText := "# 📨 <b>MENSAGEM DO SUPORTE</b>\n\n" + baseText
 	enqueueTargets := func(ids []int64, text string) {
@@ -133,12 +145,12 @@ func (c *UsersAdminController) dispatchNotice(notice NoticeRequest) {
 			if id == 0 || sent[id] {
 				continue
 			}
-			c.container.BroadcastQueue <- container.BroadcastJob{
+			jobs = append(jobs, container.BroadcastJob{
 				ChatID:   id,
 				Text:     text,
 				ImageUrl: notice.ImageUrl,
 				Buttons:  buttons,
-			}
+			})
 			sent[id] = true
 		}
 	}
@@ -177,12 +189,12 @@ func (c *UsersAdminController) dispatchNotice(notice NoticeRequest) {
 		}
 
 		for _, user := range users {
-			c.container.BroadcastQueue <- container.BroadcastJob{
+			jobs = append(jobs, container.BroadcastJob{
 				ChatID:   user.UserId,
 				Text:     baseText,
 				ImageUrl: notice.ImageUrl,
 				Buttons:  buttons,
-			}
+			})
 		}
 
 	case "channels":
@@ -193,12 +205,12 @@ func (c *UsersAdminController) dispatchNotice(notice NoticeRequest) {
 		}
 
 		for _, channel := range channels {
-			c.container.BroadcastQueue <- container.BroadcastJob{
+			jobs = append(jobs, container.BroadcastJob{
 				ChatID:   channel.ID,
 				Text:     baseText,
 				ImageUrl: notice.ImageUrl,
 				Buttons:  buttons,
-			}
+			})
 		}
 
 	case "all":
@@ -209,24 +221,24 @@ func (c *UsersAdminController) dispatchNotice(notice NoticeRequest) {
 
 		for _, user := range users {
 			if !sentMap[user.UserId] {
-				c.container.BroadcastQueue <- container.BroadcastJob{
+				jobs = append(jobs, container.BroadcastJob{
 					ChatID:   user.UserId,
 					Text:     baseText,
 					ImageUrl: notice.ImageUrl,
 					Buttons:  buttons,
-				}
+				})
 				sentMap[user.UserId] = true
 			}
 		}
 
 		for _, channel := range channels {
 			if !sentMap[channel.ID] {
-				c.container.BroadcastQueue <- container.BroadcastJob{
+				jobs = append(jobs, container.BroadcastJob{
 					ChatID:   channel.ID,
 					Text:     baseText,
 					ImageUrl: notice.ImageUrl,
 					Buttons:  buttons,
-				}
+				})
 				sentMap[channel.ID] = true
 			}
 		}
@@ -0,0 +1,59 @@
+package admincontroller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/queue"
+)
+
+type QueueController struct {
+	container *container.AppContainer
+}
+
+func NewQueueController(app *container.AppContainer) *QueueController {
+	return &QueueController{container: app}
+}
+
+func (c *QueueController) ListDead(ctx *gin.Context) {
+	filters := services.QueueJobListFilters{
+		Queue:     ctx.DefaultQuery("queue", services.QueueChannelPost),
+		ChannelID: parseInt64Query(ctx, "channelId"),
+		Limit:     parseIntQuery(ctx, "limit", 50),
+		Offset:    parseIntQuery(ctx, "offset", 0),
+	}
+
+	result, err := c.container.JobQueueService.ListDead(ctx, filters)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(result))
+}
+
+func (c *QueueController) RetryDead(ctx *gin.Context) {
+	if err := c.container.JobQueueService.RetryDead(ctx, ctx.Param("jobId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Job devolvido à fila"))
+}
+
+func (c *QueueController) DeleteDead(ctx *gin.Context) {
+	if err := c.container.JobQueueService.DeleteDead(ctx, ctx.Param("jobId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Job removido da dead-letter"))
+}
+
+// Stats retorna profundidade e latência por canal das filas em memória.
+func (c *QueueController) Stats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(queue.Snapshot()))
+}
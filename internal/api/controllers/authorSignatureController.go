@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type AuthorSignatureController struct {
+	container *container.AppContainer
+}
+
+func NewAuthorSignatureController(container *container.AppContainer) *AuthorSignatureController {
+	return &AuthorSignatureController{
+		container: container,
+	}
+}
+
+func (ctrl *AuthorSignatureController) ListSignaturesController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	signatures, err := ctrl.container.AuthorSignatureService.ListSignatures(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	result := make([]dto.AuthorSignatureDTO, 0, len(signatures))
+	for i := range signatures {
+		result = append(result, dto.ToAuthorSignatureDTO(&signatures[i]))
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(result, "Assinaturas carregadas com sucesso"))
+}
+
+func (ctrl *AuthorSignatureController) CreateSignatureController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.AuthorSignatureRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	signature, err := ctrl.container.AuthorSignatureService.CreateSignature(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, types.NewSuccessResponse(dto.ToAuthorSignatureDTO(signature), "Assinatura criada com sucesso"))
+}
+
+func (ctrl *AuthorSignatureController) UpdateSignatureController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.AuthorSignatureRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	signature, err := ctrl.container.AuthorSignatureService.UpdateSignature(ctx, channelID, ctx.Param("signatureId"), body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToAuthorSignatureDTO(signature), "Assinatura atualizada com sucesso"))
+}
+
+func (ctrl *AuthorSignatureController) DeleteSignatureController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	if err := ctrl.container.AuthorSignatureService.DeleteSignature(ctx, channelID, ctx.Param("signatureId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Assinatura deletada com sucesso"))
+}
+
+func (ctrl *AuthorSignatureController) UpdateFooterController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.SignatureFooterUpdateRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := ctrl.container.AuthorSignatureService.UpdateFooter(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Rodapé de assinatura atualizado com sucesso"))
+}
@@ -43,6 +43,98 @@ func (c *CaptionController) UpdateDefaultCaptionController(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Legenda padrão atualizada com sucesso"))
 }
 
+func (c *CaptionController) UpdateCaptionLayoutController(ctx *gin.Context) {
+	channelIdStr := ctx.Param("channelId")
+	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("ID do canal inválido"))
+		return
+	}
+
+	var layoutData types.CaptionLayoutUpdateRequest
+	if err := ctx.ShouldBindJSON(&layoutData); err != nil {
+		ctx.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := c.container.CaptionService.UpdateCaptionLayout(ctx, channelId, layoutData)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Posição da legenda atualizada com sucesso"))
+}
+
+func (c *CaptionController) UpdateCaptionOverflowController(ctx *gin.Context) {
+	channelIdStr := ctx.Param("channelId")
+	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("ID do canal inválido"))
+		return
+	}
+
+	var overflowData types.CaptionOverflowUpdateRequest
+	if err := ctx.ShouldBindJSON(&overflowData); err != nil {
+		ctx.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := c.container.CaptionService.UpdateCaptionOverflow(ctx, channelId, overflowData.Policy)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Política de legendas longas atualizada com sucesso"))
+}
+
+func (c *CaptionController) UpdateTranslationController(ctx *gin.Context) {
+	channelIdStr := ctx.Param("channelId")
+	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("ID do canal inválido"))
+		return
+	}
+
+	var translationData types.TranslationUpdateRequest
+	if err := ctx.ShouldBindJSON(&translationData); err != nil {
+		ctx.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := c.container.CaptionService.UpdateTranslation(ctx, channelId, translationData)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Tradução automática atualizada com sucesso"))
+}
+
+func (c *CaptionController) PreviewCaptionController(ctx *gin.Context) {
+	channelIdStr := ctx.Param("channelId")
+	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("ID do canal inválido"))
+		return
+	}
+
+	var previewData types.CaptionPreviewRequest
+	if err := ctx.ShouldBindJSON(&previewData); err != nil {
+		ctx.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	preview, err := c.container.CaptionService.PreviewCaption(ctx, channelId, previewData)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(preview, "Pré-visualização gerada com sucesso"))
+}
+
 func (c *CaptionController) UpdateNewPackCaptionController(ctx *gin.Context) {
 	channelIdStr := ctx.Param("channelId")
 	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
@@ -89,6 +181,29 @@ func (c *CaptionController) UpdateReactionsController(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Reações atualizadas com sucesso"))
 }
 
+func (c *CaptionController) UpdateReactionModeController(ctx *gin.Context) {
+	channelIdStr := ctx.Param("channelId")
+	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("ID do canal inválido"))
+		return
+	}
+
+	var modeData types.ReactionModeUpdateRequest
+	if err := ctx.ShouldBindJSON(&modeData); err != nil {
+		ctx.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := c.container.CaptionService.UpdateReactionMode(ctx, channelId, modeData.Mode)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Modo de reações atualizado com sucesso"))
+}
+
 func (c *CaptionController) UpdateReactionPositionController(ctx *gin.Context) {
 	channelIdStr := ctx.Param("channelId")
 	channelId, err := strconv.ParseInt(channelIdStr, 10, 64)
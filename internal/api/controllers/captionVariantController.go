@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type CaptionVariantController struct {
+	container *container.AppContainer
+}
+
+func NewCaptionVariantController(container *container.AppContainer) *CaptionVariantController {
+	return &CaptionVariantController{
+		container: container,
+	}
+}
+
+func (ctrl *CaptionVariantController) ListVariantsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	variants, err := ctrl.container.CaptionVariantService.ListVariants(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	result := make([]dto.CaptionVariantDTO, 0, len(variants))
+	for i := range variants {
+		result = append(result, dto.ToCaptionVariantDTO(&variants[i]))
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(result, "Variantes de legenda carregadas com sucesso"))
+}
+
+func (ctrl *CaptionVariantController) CreateVariantController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CaptionVariantRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	variant, err := ctrl.container.CaptionVariantService.CreateVariant(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, types.NewSuccessResponse(dto.ToCaptionVariantDTO(variant), "Variante de legenda criada com sucesso"))
+}
+
+func (ctrl *CaptionVariantController) UpdateVariantController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CaptionVariantRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	variant, err := ctrl.container.CaptionVariantService.UpdateVariant(ctx, channelID, ctx.Param("variantId"), body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToCaptionVariantDTO(variant), "Variante de legenda atualizada com sucesso"))
+}
+
+func (ctrl *CaptionVariantController) DeleteVariantController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	if err := ctrl.container.CaptionVariantService.DeleteVariant(ctx, channelID, ctx.Param("variantId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Variante de legenda deletada com sucesso"))
+}
+
+func (ctrl *CaptionVariantController) UpdateRotationController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CaptionRotationUpdateRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	rowsAffected, err := ctrl.container.CaptionVariantService.UpdateRotationMode(ctx, channelID, body.Mode)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Rotação de legendas atualizada com sucesso"))
+}
+
+func (ctrl *CaptionVariantController) StatsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	stats, err := ctrl.container.CaptionVariantService.Stats(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(stats, "Estatísticas do pool de legendas"))
+}
+
+func (ctrl *CaptionVariantController) ResetStatsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	rowsAffected, err := ctrl.container.CaptionVariantService.ResetStats(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(gin.H{"rows_affected": rowsAffected}, "Estatísticas do pool de legendas zeradas"))
+}
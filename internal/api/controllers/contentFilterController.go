@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type ContentFilterController struct {
+	container *container.AppContainer
+}
+
+func NewContentFilterController(container *container.AppContainer) *ContentFilterController {
+	return &ContentFilterController{
+		container: container,
+	}
+}
+
+func (ctrl *ContentFilterController) GetContentFilterController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	filter, err := ctrl.container.ContentFilterService.GetContentFilter(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToContentFilterDTO(filter), "Filtro de conteúdo carregado com sucesso"))
+}
+
+func (ctrl *ContentFilterController) UpdateContentFilterController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.ContentFilterRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	filter, err := ctrl.container.ContentFilterService.UpdateContentFilter(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToContentFilterDTO(filter), "Filtro de conteúdo atualizado com sucesso"))
+}
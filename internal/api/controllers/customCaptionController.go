@@ -179,3 +179,85 @@ func (ctrl *CustomCaptionController) DeleteCustomCaptionButtonController(ctx *gi
 
 	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Botão deletado com sucesso"))
 }
+
+// --- REGRAS DE SELEÇÃO DE LEGENDA ---
+
+func (ctrl *CustomCaptionController) ListCaptionRulesController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	rules, err := ctrl.container.CustomCaptionService.ListCaptionRules(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	result := make([]dto.CaptionRuleDTO, 0, len(rules))
+	for i := range rules {
+		result = append(result, dto.ToCaptionRuleDTO(&rules[i]))
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(result, "Regras de legenda carregadas com sucesso"))
+}
+
+func (ctrl *CustomCaptionController) CreateCaptionRuleController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CaptionRuleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	rule, err := ctrl.container.CustomCaptionService.CreateCaptionRule(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, types.NewSuccessResponse(dto.ToCaptionRuleDTO(rule), "Regra de legenda criada com sucesso"))
+}
+
+func (ctrl *CustomCaptionController) UpdateCaptionRuleController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CaptionRuleRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	rule, err := ctrl.container.CustomCaptionService.UpdateCaptionRule(ctx, channelID, ctx.Param("ruleId"), body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToCaptionRuleDTO(rule), "Regra de legenda atualizada com sucesso"))
+}
+
+func (ctrl *CustomCaptionController) DeleteCaptionRuleController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	if err := ctrl.container.CustomCaptionService.DeleteCaptionRule(ctx, channelID, ctx.Param("ruleId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Regra de legenda deletada com sucesso"))
+}
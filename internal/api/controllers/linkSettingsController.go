@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type LinkSettingsController struct {
+	container *container.AppContainer
+}
+
+func NewLinkSettingsController(container *container.AppContainer) *LinkSettingsController {
+	return &LinkSettingsController{
+		container: container,
+	}
+}
+
+func (ctrl *LinkSettingsController) GetLinkSettingsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	settings, err := ctrl.container.LinkSettingsService.GetLinkSettings(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToLinkSettingsDTO(settings), "Regras de links carregadas com sucesso"))
+}
+
+func (ctrl *LinkSettingsController) UpdateLinkSettingsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.LinkSettingsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	settings, err := ctrl.container.LinkSettingsService.UpdateLinkSettings(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToLinkSettingsDTO(settings), "Regras de links atualizadas com sucesso"))
+}
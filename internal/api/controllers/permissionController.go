@@ -135,3 +135,25 @@ func (ctrl *PermissionController) UpdateDynamicLinksController(c *gin.Context) {
 
 	c.JSON(http.StatusOK, types.NewSuccessResponse(body))
 }
+
+func (ctrl *PermissionController) UpdateProcessEditsController(c *gin.Context) {
+	channelIDStr := c.Param("channelId")
+	channelID, err := strconv.ParseInt(channelIDStr, 10, 64)
+	if err != nil {
+		c.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.UpdateProcessEditsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(errors.BadRequest("Dados inválidos: " + err.Error()))
+		return
+	}
+
+	if err := ctrl.container.ChannelService.UpdateProcessEdits(c, channelID, *body.ProcessEdits); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.NewSuccessResponse(body))
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type ScheduledPostController struct {
+	container *container.AppContainer
+}
+
+func NewScheduledPostController(container *container.AppContainer) *ScheduledPostController {
+	return &ScheduledPostController{
+		container: container,
+	}
+}
+
+func (ctrl *ScheduledPostController) ListScheduledPostsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	status := ctx.DefaultQuery("status", services.ScheduledPostStatusPending)
+	if status == "all" {
+		status = ""
+	}
+
+	posts, err := ctrl.container.ScheduledPostService.ListByChannel(ctx, channelID, status)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToScheduledPostDTOs(posts)))
+}
+
+func (ctrl *ScheduledPostController) CreateScheduledPostController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.CreateScheduledPostRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	var userID int64
+	if value, ok := ctx.Get("userID"); ok {
+		userID, _ = value.(int64)
+	}
+
+	post, err := ctrl.container.ScheduledPostService.ScheduleFromSession(ctx, channelID, userID, body.SessionID, body.ScheduledAt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctrl.container.ChannelEventService.Record(ctx, services.ChannelEventRecordInput{
+		ChannelID: channelID,
+		ActorID:   userID,
+		Source:    services.ChannelEventSourcePostBuilder,
+		EventType: "postbuilder_scheduled",
+		Status:    services.ChannelEventStatusInfo,
+		SessionID: body.SessionID,
+		Metadata:  map[string]any{"scheduled_post_id": post.ID, "scheduled_at": post.ScheduledAt, "media_type": post.MediaType, "origin": "api"},
+	})
+
+	ctx.JSON(http.StatusCreated, types.NewSuccessResponse(dto.ToScheduledPostDTO(post), "Postagem agendada com sucesso"))
+}
+
+func (ctrl *ScheduledPostController) CancelScheduledPostController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+	postID := ctx.Param("scheduledId")
+
+	if err := ctrl.container.ScheduledPostService.Cancel(ctx, channelID, postID); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var userID int64
+	if value, ok := ctx.Get("userID"); ok {
+		userID, _ = value.(int64)
+	}
+	ctrl.container.ChannelEventService.Record(ctx, services.ChannelEventRecordInput{
+		ChannelID: channelID,
+		ActorID:   userID,
+		Source:    services.ChannelEventSourcePostBuilder,
+		EventType: "postbuilder_schedule_canceled",
+		Status:    services.ChannelEventStatusInfo,
+		Metadata:  map[string]any{"scheduled_post_id": postID, "origin": "api"},
+	})
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Agendamento cancelado com sucesso"))
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+// SeparatorController expõe as configurações e as variantes do sticker
+// separador. Os stickers são enviados pelo bot (menu do separador); o painel
+// ajusta o rodízio, o intervalo e quando cada variante é usada.
+type SeparatorController struct {
+	container *container.AppContainer
+}
+
+func NewSeparatorController(container *container.AppContainer) *SeparatorController {
+	return &SeparatorController{
+		container: container,
+	}
+}
+
+func (ctrl *SeparatorController) GetSeparatorController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	sep, err := ctrl.container.SeparatorService.GetSeparatorByOwnerChannelID(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	variants, err := ctrl.container.SeparatorService.ListVariants(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToSeparatorDTO(sep, variants), "Separador carregado com sucesso"))
+}
+
+func (ctrl *SeparatorController) UpdateSettingsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.SeparatorSettingsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	sep, err := ctrl.container.SeparatorService.UpdateSettings(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	variants, err := ctrl.container.SeparatorService.ListVariants(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToSeparatorDTO(sep, variants), "Configurações do separador atualizadas com sucesso"))
+}
+
+func (ctrl *SeparatorController) UpdateVariantController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.SeparatorVariantRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	variant, err := ctrl.container.SeparatorService.UpdateVariant(ctx, channelID, ctx.Param("variantId"), body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToSeparatorVariantDTO(variant), "Variante do separador atualizada com sucesso"))
+}
+
+func (ctrl *SeparatorController) DeleteVariantController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	if err := ctrl.container.SeparatorService.DeleteVariant(ctx, channelID, ctx.Param("variantId")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse[any](nil, "Variante do separador deletada com sucesso"))
+}
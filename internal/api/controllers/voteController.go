@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/internal/api/dto"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+type VoteController struct {
+	container *container.AppContainer
+}
+
+func NewVoteController(container *container.AppContainer) *VoteController {
+	return &VoteController{
+		container: container,
+	}
+}
+
+func (ctrl *VoteController) AnalyticsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	days, err := optionalIntQuery(ctx, "days")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	top, err := optionalIntQuery(ctx, "top")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	analytics, err := ctrl.container.VoteService.Analytics(ctx, channelID, days, top)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(analytics, "Estatísticas de votos carregadas com sucesso"))
+}
+
+// ExportController baixa os votos por post e emoji em CSV (padrão) ou JSON.
+func (ctrl *VoteController) ExportController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		ctx.Error(errors.BadRequest("format inválido (use csv ou json)"))
+		return
+	}
+	days, err := optionalIntQuery(ctx, "days")
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rows, err := ctrl.container.VoteService.Export(ctx, channelID, days)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "application/json; charset=utf-8"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+		w := csv.NewWriter(&buf)
+		_ = w.Write([]string{"message_id", "link", "emoji", "votes", "source"})
+		for _, row := range rows {
+			_ = w.Write([]string{strconv.Itoa(row.MessageID), row.Link, row.Emoji, strconv.FormatInt(row.Votes, 10), row.Source})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			ctx.Error(errors.Internal(err))
+			return
+		}
+	} else if err := json.NewEncoder(&buf).Encode(rows); err != nil {
+		ctx.Error(errors.Internal(err))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=votes-%d.%s", channelID, format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+func (ctrl *VoteController) GetSettingsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	settings, err := ctrl.container.VoteService.GetVoteSettings(ctx, channelID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToVoteSettingsDTO(settings), "Configurações de votação carregadas com sucesso"))
+}
+
+func (ctrl *VoteController) UpdateSettingsController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+
+	var body types.VoteSettingsRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	settings, err := ctrl.container.VoteService.UpdateVoteSettings(ctx, channelID, body)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(dto.ToVoteSettingsDTO(settings), "Configurações de votação atualizadas com sucesso"))
+}
+
+// UpdatePollController encerra, reabre ou muda o prazo da votação de um post.
+func (ctrl *VoteController) UpdatePollController(ctx *gin.Context) {
+	channelID, err := strconv.ParseInt(ctx.Param("channelId"), 10, 64)
+	if err != nil {
+		ctx.Error(errors.BadRequest("channelId inválido"))
+		return
+	}
+	messageID, err := strconv.Atoi(ctx.Param("messageId"))
+	if err != nil {
+		ctx.Error(errors.BadRequest("messageId inválido"))
+		return
+	}
+
+	var body types.VotePollRequest
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.Error(errors.BadRequest("payload inválido: " + err.Error()))
+		return
+	}
+
+	poll, err := ctrl.container.VoteService.UpdatePollDeadline(ctx, channelID, messageID, body.ClosesAt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, types.NewSuccessResponse(poll, "Prazo da votação atualizado com sucesso"))
+}
+
+// optionalIntQuery lê um parâmetro inteiro opcional; ausente vale 0.
+func optionalIntQuery(ctx *gin.Context, key string) (int, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.BadRequest(key + " inválido")
+	}
+	return n, nil
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+)
 
 type UserDTO struct {
 	ID            int64        `json:"id"`
@@ -13,26 +17,94 @@ type UserDTO struct {
 }
 
 type ChannelDTO struct {
-	ID                     int64              `json:"id"`
-	Title                  string             `json:"title"`
-	NewPackCaption         string             `json:"newPackCaption"`
-	NewPackMessageButtons  bool               `json:"newPackMessageButtons"`
-	NewPackStickerButtons  bool               `json:"newPackStickerButtons"`
-	NewPackMessagePosition string             `json:"newPackMessagePosition"`
-	NewPackReplyToSticker  bool               `json:"newPackReplyToSticker"`
-	InviteURL              string             `json:"inviteUrl"`
-	OwnerID                int64              `json:"ownerId"`
-	Reactions              string             `json:"reactions"`
-	ReactionPosition       int                `json:"reactionPosition"`
-	DynamicLinks           bool               `json:"dynamicLinks"`
-	DLBotButtons           bool               `json:"dlBotButtons"`
-	DLBotCaptions          bool               `json:"dlBotCaptions"`
-	DLBotReactions         bool               `json:"dlBotReactions"`
-	DefaultCaption         *DefaultCaptionDTO `json:"defaultCaption,omitempty"`
-	Buttons                []ButtonDTO        `json:"buttons,omitempty"`
-	CustomCaptions         []CustomCaptionDTO `json:"customCaptions,omitempty"`
-	CreatedAt              time.Time          `json:"created_at"`
-	UpdatedAt              time.Time          `json:"updated_at"`
+	ID                     int64                `json:"id"`
+	Title                  string               `json:"title"`
+	NewPackCaption         string               `json:"newPackCaption"`
+	NewPackMessageButtons  bool                 `json:"newPackMessageButtons"`
+	NewPackStickerButtons  bool                 `json:"newPackStickerButtons"`
+	NewPackMessagePosition string               `json:"newPackMessagePosition"`
+	NewPackReplyToSticker  bool                 `json:"newPackReplyToSticker"`
+	InviteURL              string               `json:"inviteUrl"`
+	OwnerID                int64                `json:"ownerId"`
+	Reactions              string               `json:"reactions"`
+	ReactionPosition       int                  `json:"reactionPosition"`
+	ReactionMode           string               `json:"reactionMode"`
+	DynamicLinks           bool                 `json:"dynamicLinks"`
+	DLBotButtons           bool                 `json:"dlBotButtons"`
+	DLBotCaptions          bool                 `json:"dlBotCaptions"`
+	DLBotReactions         bool                 `json:"dlBotReactions"`
+	ProcessEdits           bool                 `json:"processEdits"`
+	CaptionPosition        string               `json:"captionPosition"`
+	CaptionSeparator       string               `json:"captionSeparator"`
+	CaptionRotation        string               `json:"captionRotation"`
+	CaptionOverflow        string               `json:"captionOverflow"`
+	TranslateLanguage      string               `json:"translateLanguage"`
+	TranslateMode          string               `json:"translateMode"`
+	SignatureFooter        bool                 `json:"signatureFooter"`
+	SignatureFormat        string               `json:"signatureFormat"`
+	DefaultCaption         *DefaultCaptionDTO   `json:"defaultCaption,omitempty"`
+	Buttons                []ButtonDTO          `json:"buttons,omitempty"`
+	CustomCaptions         []CustomCaptionDTO   `json:"customCaptions,omitempty"`
+	CaptionRules           []CaptionRuleDTO     `json:"captionRules,omitempty"`
+	CaptionVariants        []CaptionVariantDTO  `json:"captionVariants,omitempty"`
+	AuthorSignatures       []AuthorSignatureDTO `json:"authorSignatures,omitempty"`
+	LinkSettings           *LinkSettingsDTO     `json:"linkSettings,omitempty"`
+	ContentFilter          *ContentFilterDTO    `json:"contentFilter,omitempty"`
+	VoteSettings           *VoteSettingsDTO     `json:"voteSettings"`
+	CreatedAt              time.Time            `json:"created_at"`
+	UpdatedAt              time.Time            `json:"updated_at"`
+}
+
+type LinkRewriteDTO struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type LinkSettingsDTO struct {
+	Enabled      bool             `json:"enabled"`
+	UTMSource    string           `json:"utmSource"`
+	UTMMedium    string           `json:"utmMedium"`
+	UTMCampaign  string           `json:"utmCampaign"`
+	UTMContent   string           `json:"utmContent"`
+	AllowDomains []string         `json:"allowDomains"`
+	DenyDomains  []string         `json:"denyDomains"`
+	Rewrites     []LinkRewriteDTO `json:"rewrites"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+type ContentFilterDTO struct {
+	Enabled        bool      `json:"enabled"`
+	Action         string    `json:"action"`
+	BannedWords    []string  `json:"bannedWords"`
+	Patterns       []string  `json:"patterns"`
+	BlockedDomains []string  `json:"blockedDomains"`
+	BlockedTypes   []string  `json:"blockedTypes"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type VoteSettingsDTO struct {
+	Mode            string    `json:"mode"`
+	HideCounts      bool      `json:"hideCounts"`
+	DurationMinutes int       `json:"durationMinutes"`
+	ShowResults     bool      `json:"showResults"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SeparatorDTO reúne o sticker principal (SeparatorID vazio quando o canal não
+// tem separador), as configurações e as variantes do separador.
+type SeparatorDTO struct {
+	SeparatorID     string                `json:"separatorId"`
+	Rotation        string                `json:"rotation"`
+	CooldownMinutes int                   `json:"cooldownMinutes"`
+	Variants        []SeparatorVariantDTO `json:"variants"`
+}
+
+type SeparatorVariantDTO struct {
+	VariantID   string    `json:"variantId"`
+	SeparatorID string    `json:"separatorId"`
+	MessageType string    `json:"messageType"`
+	Hashtag     string    `json:"hashtag"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type DefaultCaptionDTO struct {
@@ -68,6 +140,68 @@ type CustomCaptionDTO struct {
 	Code        string      `json:"code"`
 	Caption     string      `json:"caption"`
 	LinkPreview bool        `json:"linkPreview"`
+	Position    string      `json:"position"`
+	Separator   string      `json:"separator"`
 	Buttons     []ButtonDTO `json:"buttons,omitempty"`
 	CreatedAt   time.Time   `json:"created_at"`
 }
+
+type CaptionRuleDTO struct {
+	RuleID        string    `json:"ruleId"`
+	CaptionID     string    `json:"captionId"`
+	Name          string    `json:"name"`
+	Priority      int       `json:"priority"`
+	Enabled       bool      `json:"enabled"`
+	Hashtags      string    `json:"hashtags"`
+	Keywords      string    `json:"keywords"`
+	Pattern       string    `json:"pattern"`
+	MessageTypes  string    `json:"messageTypes"`
+	ForwardedFrom string    `json:"forwardedFrom"`
+	StartTime     string    `json:"startTime"`
+	EndTime       string    `json:"endTime"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AuthorSignatureDTO struct {
+	SignatureID string    `json:"signatureId"`
+	Signature   string    `json:"signature"`
+	Label       string    `json:"label"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CaptionVariantDTO struct {
+	VariantID  string                    `json:"variantId"`
+	Name       string                    `json:"name"`
+	Caption    string                    `json:"caption"`
+	Weight     int                       `json:"weight"`
+	Weekdays   string                    `json:"weekdays"`
+	Enabled    bool                      `json:"enabled"`
+	UsageCount int64                     `json:"usageCount"`
+	LastUsedAt *time.Time                `json:"lastUsedAt"`
+	Buttons    []CaptionVariantButtonDTO `json:"buttons"`
+	CreatedAt  time.Time                 `json:"created_at"`
+}
+
+type CaptionVariantButtonDTO struct {
+	ButtonID   string `json:"buttonId"`
+	NameButton string `json:"nameButton"`
+	ButtonURL  string `json:"buttonUrl"`
+	PositionX  int    `json:"positionX"`
+	PositionY  int    `json:"positionY"`
+}
+
+type ScheduledPostDTO struct {
+	ID            string                  `json:"id"`
+	ChannelID     int64                   `json:"channelId"`
+	CreatedBy     int64                   `json:"createdBy"`
+	SessionID     string                  `json:"sessionId"`
+	Status        string                  `json:"status"`
+	ScheduledAt   time.Time               `json:"scheduledAt"`
+	Attempts      int                     `json:"attempts"`
+	ErrorMessage  string                  `json:"errorMessage,omitempty"`
+	SentMessageID int                     `json:"sentMessageId,omitempty"`
+	SentAt        *time.Time              `json:"sentAt,omitempty"`
+	Post          *cache.PostBuilderState `json:"post,omitempty"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
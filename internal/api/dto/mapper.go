@@ -1,6 +1,16 @@
 package dto
 
-import "github.com/leirbagxis/FreddyBot/internal/database/models"
+import (
+	"encoding/json"
+
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/contentfilter"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
+)
 
 func ToUserDTO(u *models.User) UserDTO {
 	if u == nil {
@@ -40,10 +50,20 @@ func ToChannelDTO(c *models.Channel) ChannelDTO {
 		OwnerID:                c.OwnerID,
 		Reactions:              c.Reactions,
 		ReactionPosition:       c.ReactionPosition,
+		ReactionMode:           reactions.ModeOf(c.ReactionMode),
 		DynamicLinks:           c.DynamicLinks,
 		DLBotButtons:           c.DLBotButtons,
 		DLBotCaptions:          c.DLBotCaptions,
 		DLBotReactions:         c.DLBotReactions,
+		ProcessEdits:           c.ProcessEdits,
+		CaptionPosition:        stringValueOrDefault(&c.CaptionPosition, "append"),
+		CaptionSeparator:       c.CaptionSeparator,
+		CaptionRotation:        c.CaptionRotation,
+		CaptionOverflow:        stringValueOrDefault(&c.CaptionOverflow, "shorten"),
+		TranslateLanguage:      c.TranslateLanguage,
+		TranslateMode:          stringValueOrDefault(&c.TranslateMode, "append"),
+		SignatureFooter:        c.SignatureFooter,
+		SignatureFormat:        stringValueOrDefault(&c.SignatureFormat, captiontpl.DefaultSignatureFormat),
 		CreatedAt:              c.CreatedAt,
 		UpdatedAt:              c.UpdatedAt,
 	}
@@ -64,6 +84,121 @@ func ToChannelDTO(c *models.Channel) ChannelDTO {
 		}
 	}
 
+	for _, rule := range c.CaptionRules {
+		dto.CaptionRules = append(dto.CaptionRules, ToCaptionRuleDTO(&rule))
+	}
+
+	for _, variant := range c.CaptionVariants {
+		dto.CaptionVariants = append(dto.CaptionVariants, ToCaptionVariantDTO(&variant))
+	}
+
+	for _, signature := range c.AuthorSignatures {
+		dto.AuthorSignatures = append(dto.AuthorSignatures, ToAuthorSignatureDTO(&signature))
+	}
+
+	if c.LinkSettings != nil {
+		dto.LinkSettings = ToLinkSettingsDTO(c.LinkSettings)
+	}
+
+	if c.ContentFilter != nil {
+		dto.ContentFilter = ToContentFilterDTO(c.ContentFilter)
+	}
+
+	dto.VoteSettings = ToVoteSettingsDTO(c.VoteSettings)
+
+	return dto
+}
+
+func ToSeparatorDTO(sep *models.Separator, variants []models.SeparatorVariant) SeparatorDTO {
+	dto := SeparatorDTO{Variants: make([]SeparatorVariantDTO, 0, len(variants))}
+	if sep != nil {
+		dto.SeparatorID = sep.SeparatorID
+		dto.Rotation = sep.Rotation
+		dto.CooldownMinutes = sep.CooldownMinutes
+	}
+	for i := range variants {
+		dto.Variants = append(dto.Variants, ToSeparatorVariantDTO(&variants[i]))
+	}
+	return dto
+}
+
+func ToSeparatorVariantDTO(v *models.SeparatorVariant) SeparatorVariantDTO {
+	return SeparatorVariantDTO{
+		VariantID:   v.VariantID,
+		SeparatorID: v.SeparatorID,
+		MessageType: v.MessageType,
+		Hashtag:     v.Hashtag,
+		CreatedAt:   v.CreatedAt,
+	}
+}
+
+// ToLinkSettingsDTO converte as listas salvas como texto em arrays. Canais sem
+// configuração recebem as regras vazias e desativadas.
+func ToLinkSettingsDTO(s *models.LinkSettings) *LinkSettingsDTO {
+	dto := &LinkSettingsDTO{
+		AllowDomains: []string{},
+		DenyDomains:  []string{},
+		Rewrites:     []LinkRewriteDTO{},
+	}
+	if s == nil {
+		return dto
+	}
+
+	dto.Enabled = s.Enabled
+	dto.UTMSource = s.UTMSource
+	dto.UTMMedium = s.UTMMedium
+	dto.UTMCampaign = s.UTMCampaign
+	dto.UTMContent = s.UTMContent
+	dto.UpdatedAt = s.UpdatedAt
+	dto.AllowDomains = append(dto.AllowDomains, linkrewrite.SplitDomains(s.AllowDomains)...)
+	dto.DenyDomains = append(dto.DenyDomains, linkrewrite.SplitDomains(s.DenyDomains)...)
+	for _, rw := range linkrewrite.ParseRewrites(s.Rewrites) {
+		dto.Rewrites = append(dto.Rewrites, LinkRewriteDTO{From: rw.From, To: rw.To})
+	}
+	return dto
+}
+
+// ToContentFilterDTO converte as listas salvas como texto em arrays. Canais sem
+// configuração recebem o filtro vazio e desativado.
+func ToContentFilterDTO(f *models.ContentFilter) *ContentFilterDTO {
+	dto := &ContentFilterDTO{
+		Action:         contentfilter.ActionStrip,
+		BannedWords:    []string{},
+		Patterns:       []string{},
+		BlockedDomains: []string{},
+		BlockedTypes:   []string{},
+	}
+	if f == nil {
+		return dto
+	}
+
+	dto.Enabled = f.Enabled
+	if contentfilter.IsValidAction(f.Action) {
+		dto.Action = f.Action
+	}
+	dto.UpdatedAt = f.UpdatedAt
+	dto.BannedWords = append(dto.BannedWords, contentfilter.SplitLines(f.BannedWords)...)
+	dto.Patterns = append(dto.Patterns, contentfilter.SplitLines(f.Patterns)...)
+	dto.BlockedDomains = append(dto.BlockedDomains, linkrewrite.SplitDomains(f.BlockedDomains)...)
+	dto.BlockedTypes = append(dto.BlockedTypes, contentfilter.SplitList(f.BlockedTypes)...)
+	return dto
+}
+
+// ToVoteSettingsDTO preenche o padrão (escolha única, contagens visíveis e sem
+// prazo) para canais sem configuração.
+func ToVoteSettingsDTO(s *models.VoteSettings) *VoteSettingsDTO {
+	dto := &VoteSettingsDTO{Mode: votepoll.ModeSingle}
+	if s == nil {
+		return dto
+	}
+
+	if votepoll.IsValidMode(s.Mode) {
+		dto.Mode = s.Mode
+	}
+	dto.HideCounts = s.HideCounts
+	dto.DurationMinutes = s.DurationMinutes
+	dto.ShowResults = s.ShowResults
+	dto.UpdatedAt = s.UpdatedAt
 	return dto
 }
 
@@ -137,12 +272,67 @@ func ToCustomCaptionButtonDTO(b *models.CustomCaptionButton) ButtonDTO {
 	}
 }
 
+func ToCaptionRuleDTO(r *models.CaptionRule) CaptionRuleDTO {
+	return CaptionRuleDTO{
+		RuleID:        r.RuleID,
+		CaptionID:     r.CaptionID,
+		Name:          r.Name,
+		Priority:      r.Priority,
+		Enabled:       r.Enabled,
+		Hashtags:      r.Hashtags,
+		Keywords:      r.Keywords,
+		Pattern:       r.Pattern,
+		MessageTypes:  r.MessageTypes,
+		ForwardedFrom: r.ForwardedFrom,
+		StartTime:     r.StartTime,
+		EndTime:       r.EndTime,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+func ToAuthorSignatureDTO(s *models.AuthorSignature) AuthorSignatureDTO {
+	return AuthorSignatureDTO{
+		SignatureID: s.SignatureID,
+		Signature:   s.Signature,
+		Label:       s.Label,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+func ToCaptionVariantDTO(v *models.CaptionVariant) CaptionVariantDTO {
+	result := CaptionVariantDTO{
+		VariantID:  v.VariantID,
+		Name:       v.Name,
+		Caption:    v.Caption,
+		Weight:     v.Weight,
+		Weekdays:   v.Weekdays,
+		Enabled:    v.Enabled,
+		UsageCount: v.UsageCount,
+		LastUsedAt: v.LastUsedAt,
+		Buttons:    make([]CaptionVariantButtonDTO, 0, len(v.Buttons)),
+		CreatedAt:  v.CreatedAt,
+	}
+	for _, b := range v.Buttons {
+		result.Buttons = append(result.Buttons, CaptionVariantButtonDTO{
+			ButtonID:   b.ButtonID,
+			NameButton: b.NameButton,
+			ButtonURL:  b.ButtonURL,
+			PositionX:  b.PositionX,
+			PositionY:  b.PositionY,
+		})
+	}
+	return result
+}
+
 func ToCustomCaptionDTO(cc *models.CustomCaption) CustomCaptionDTO {
 	dto := CustomCaptionDTO{
 		CaptionID:   cc.CaptionID,
 		Code:        cc.Code,
 		Caption:     cc.Caption,
 		LinkPreview: cc.LinkPreview,
+		Position:    cc.Position,
+		Separator:   cc.Separator,
 		CreatedAt:   cc.CreatedAt,
 	}
 
@@ -154,3 +344,34 @@ func ToCustomCaptionDTO(cc *models.CustomCaption) CustomCaptionDTO {
 
 	return dto
 }
+
+func ToScheduledPostDTO(sp *models.ScheduledPost) ScheduledPostDTO {
+	dto := ScheduledPostDTO{
+		ID:            sp.ID,
+		ChannelID:     sp.ChannelID,
+		CreatedBy:     sp.CreatedBy,
+		SessionID:     sp.SessionID,
+		Status:        sp.Status,
+		ScheduledAt:   sp.ScheduledAt,
+		Attempts:      sp.Attempts,
+		ErrorMessage:  sp.ErrorMessage,
+		SentMessageID: sp.SentMessageID,
+		SentAt:        sp.SentAt,
+		CreatedAt:     sp.CreatedAt,
+	}
+
+	var state cache.PostBuilderState
+	if err := json.Unmarshal([]byte(sp.Payload), &state); err == nil {
+		dto.Post = &state
+	}
+
+	return dto
+}
+
+func ToScheduledPostDTOs(posts []models.ScheduledPost) []ScheduledPostDTO {
+	result := make([]ScheduledPostDTO, 0, len(posts))
+	for i := range posts {
+		result = append(result, ToScheduledPostDTO(&posts[i]))
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID reaproveita o X-Request-ID recebido (ou gera um novo), devolve no
+// header da resposta e anexa request_id e correlation_id ao contexto da
+// requisição para os logs e os ChannelEvents gravados por ela.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = logger.NewCorrelationID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := logger.With(c.Request.Context(), logger.FieldRequestID, requestID)
+		ctx = logger.WithCorrelationID(ctx, requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
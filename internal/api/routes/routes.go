@@ -12,6 +12,8 @@ import (
 
 func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Metrics())
 	r.Use(middleware.ErrorHandler())
 
 	api := r.Group("/api")
@@ -22,6 +24,13 @@ func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 	ButtonsController := controllers.NewButtonsController(c)
 	permissionsController := controllers.NewPermissionController(c)
 	customCaptionController := controllers.NewCustomCaptionController(c)
+	captionVariantController := controllers.NewCaptionVariantController(c)
+	linkSettingsController := controllers.NewLinkSettingsController(c)
+	contentFilterController := controllers.NewContentFilterController(c)
+	authorSignatureController := controllers.NewAuthorSignatureController(c)
+	scheduledPostController := controllers.NewScheduledPostController(c)
+	voteController := controllers.NewVoteController(c)
+	separatorController := controllers.NewSeparatorController(c)
 	userController := controllers.NewUserController(c)
 	channelController := controllers.NewChannelController(c)
 	getALlUsers := admincontroller.NewUsersAdminController(c)
@@ -29,6 +38,7 @@ func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 	mediaController := admincontroller.NewMediaController(c)
 	auditController := admincontroller.NewAuditController(c)
 	channelEventsController := admincontroller.NewChannelEventsController(c)
+	queueController := admincontroller.NewQueueController(c)
 
 	// --- Rota de Login Unificada ---
 	api.POST("/login", authController.Login)
@@ -48,11 +58,38 @@ func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 			channelRoutes.GET("", channelController.GetChannelByIDController)
 			channelRoutes.DELETE("", channelController.DisconectChannel)
 			channelRoutes.PUT("/caption", captionController.UpdateDefaultCaptionController)
+			channelRoutes.POST("/caption/preview", captionController.PreviewCaptionController)
+			channelRoutes.PUT("/caption/layout", captionController.UpdateCaptionLayoutController)
+			channelRoutes.PUT("/caption/overflow", captionController.UpdateCaptionOverflowController)
+			channelRoutes.PUT("/translation", captionController.UpdateTranslationController)
+			channelRoutes.PUT("/caption/rotation", captionVariantController.UpdateRotationController)
+			channelRoutes.GET("/caption/variants", captionVariantController.ListVariantsController)
+			channelRoutes.POST("/caption/variants", captionVariantController.CreateVariantController)
+			channelRoutes.GET("/caption/variants/stats", captionVariantController.StatsController)
+			channelRoutes.DELETE("/caption/variants/stats", captionVariantController.ResetStatsController)
+			channelRoutes.PUT("/caption/variants/:variantId", captionVariantController.UpdateVariantController)
+			channelRoutes.DELETE("/caption/variants/:variantId", captionVariantController.DeleteVariantController)
+			channelRoutes.GET("/links", linkSettingsController.GetLinkSettingsController)
+			channelRoutes.PUT("/links", linkSettingsController.UpdateLinkSettingsController)
+			channelRoutes.GET("/filter", contentFilterController.GetContentFilterController)
+			channelRoutes.PUT("/filter", contentFilterController.UpdateContentFilterController)
+			channelRoutes.GET("/signatures", authorSignatureController.ListSignaturesController)
+			channelRoutes.POST("/signatures", authorSignatureController.CreateSignatureController)
+			channelRoutes.PUT("/signatures/footer", authorSignatureController.UpdateFooterController)
+			channelRoutes.PUT("/signatures/:signatureId", authorSignatureController.UpdateSignatureController)
+			channelRoutes.DELETE("/signatures/:signatureId", authorSignatureController.DeleteSignatureController)
 			channelRoutes.PUT("/newpackcaption", captionController.UpdateNewPackCaptionController)
 			channelRoutes.PUT("/reactions", captionController.UpdateReactionsController)
 			channelRoutes.PUT("/reactions/active", permissionsController.UpdateReactionsActiveController)
 			channelRoutes.PUT("/reactions/position", captionController.UpdateReactionPositionController)
+			channelRoutes.PUT("/reactions/mode", captionController.UpdateReactionModeController)
+			channelRoutes.GET("/votes/analytics", voteController.AnalyticsController)
+			channelRoutes.GET("/votes/export", voteController.ExportController)
+			channelRoutes.GET("/votes/settings", voteController.GetSettingsController)
+			channelRoutes.PUT("/votes/settings", voteController.UpdateSettingsController)
+			channelRoutes.PUT("/votes/posts/:messageId", voteController.UpdatePollController)
 			channelRoutes.PUT("/dynamic-links", permissionsController.UpdateDynamicLinksController)
+			channelRoutes.PUT("/edits", permissionsController.UpdateProcessEditsController)
 			channelRoutes.PUT("/caption/permissions", permissionsController.UpdateMessagePermissionController)
 			channelRoutes.PUT("/buttons/permissions", permissionsController.UpdateButtonsPermissionController)
 
@@ -68,7 +105,19 @@ func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 			channelRoutes.PUT("/custom-captions/:captionId/buttons/:buttonId", customCaptionController.UpdateCustomCaptionButtonController)
 			channelRoutes.DELETE("/custom-captions/:captionId", customCaptionController.DeleteCustomCaptionController)
 			channelRoutes.DELETE("/custom-captions/:captionId/buttons/:buttonId", customCaptionController.DeleteCustomCaptionButtonController)
+			channelRoutes.GET("/custom-captions/rules", customCaptionController.ListCaptionRulesController)
+			channelRoutes.POST("/custom-captions/rules", customCaptionController.CreateCaptionRuleController)
+			channelRoutes.PUT("/custom-captions/rules/:ruleId", customCaptionController.UpdateCaptionRuleController)
+			channelRoutes.DELETE("/custom-captions/rules/:ruleId", customCaptionController.DeleteCaptionRuleController)
 
+			channelRoutes.GET("/scheduled", scheduledPostController.ListScheduledPostsController)
+			channelRoutes.POST("/scheduled", scheduledPostController.CreateScheduledPostController)
+			channelRoutes.DELETE("/scheduled/:scheduledId", scheduledPostController.CancelScheduledPostController)
+
+			channelRoutes.GET("/separator", separatorController.GetSeparatorController)
+			channelRoutes.PUT("/separator/settings", separatorController.UpdateSettingsController)
+			channelRoutes.PUT("/separator/variants/:variantId", separatorController.UpdateVariantController)
+			channelRoutes.DELETE("/separator/variants/:variantId", separatorController.DeleteVariantController)
 			channelRoutes.GET("/separator/:separatorId", channelController.GetSeparator)
 		}
 	}
@@ -88,6 +137,10 @@ func RegisterRoutes(r *gin.Engine, c *container.AppContainer) {
 		adminRoute.GET("/media-proxy/:fileId", mediaController.GetMediaPreview)
 		adminRoute.GET("/audit/checkbot", auditController.GetCheckBotAudit)
 		adminRoute.GET("/logs", channelEventsController.List)
+		adminRoute.GET("/queue/stats", queueController.Stats)
+		adminRoute.GET("/queue/dead", queueController.ListDead)
+		adminRoute.POST("/queue/dead/:jobId/retry", queueController.RetryDead)
+		adminRoute.DELETE("/queue/dead/:jobId", queueController.DeleteDead)
 		adminRoute.POST("/audit/bulk-delete", auditController.BulkDeleteUserChannels)
 
 		adminRoute.POST("/users/:userId/admin", getALlUsers.UpdateUserAdminController)
@@ -0,0 +1,11 @@
+package types
+
+type AuthorSignatureRequest struct {
+	Signature string `json:"signature" binding:"required"`
+	Label     string `json:"label" binding:"required"`
+}
+
+type SignatureFooterUpdateRequest struct {
+	Enabled bool   `json:"enabled"`
+	Format  string `json:"format"`
+}
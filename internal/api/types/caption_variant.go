@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// CaptionVariantButtonRequest é um botão da variante; a posição segue a grade
+// dos botões do canal (linha em PositionY, coluna em PositionX).
+type CaptionVariantButtonRequest struct {
+	ButtonCreateRequest
+	PositionX int `json:"positionX"`
+	PositionY int `json:"positionY"`
+}
+
+// CaptionVariantRequest cria ou substitui uma variante do pool de legendas.
+// Os botões enviados substituem todos os botões da variante.
+type CaptionVariantRequest struct {
+	Name     string                        `json:"name"`
+	Caption  string                        `json:"caption" binding:"required"`
+	Weight   int                           `json:"weight"`
+	Weekdays string                        `json:"weekdays"`
+	Enabled  *bool                         `json:"enabled"`
+	Buttons  []CaptionVariantButtonRequest `json:"buttons"`
+}
+
+type CaptionRotationUpdateRequest struct {
+	Mode string `json:"mode"`
+}
+
+type CaptionVariantUsage struct {
+	VariantID  string     `json:"variantId"`
+	Name       string     `json:"name"`
+	Enabled    bool       `json:"enabled"`
+	UsageCount int64      `json:"usageCount"`
+	Share      float64    `json:"share"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type CaptionPoolStatsResponse struct {
+	Mode     string                `json:"mode"`
+	Total    int64                 `json:"total"`
+	Variants []CaptionVariantUsage `json:"variants"`
+}
@@ -4,6 +4,41 @@ type CaptionDefaultUpdateRequest struct {
 	Caption string `json:"caption" binding:"required"`
 }
 
+// CaptionPreviewRequest renderiza um template de legenda com dados de exemplo.
+type CaptionPreviewRequest struct {
+	Caption         string `json:"caption" binding:"required"`
+	MessageType     string `json:"messageType"`
+	OriginalCaption string `json:"originalCaption"`
+	FileName        string `json:"fileName"`
+	Duration        int    `json:"duration"`
+}
+
+type CaptionPreviewResponse struct {
+	HTML        string   `json:"html"`
+	Variables   []string `json:"variables"`
+	MessageType string   `json:"messageType"`
+	// Overflow é o texto que iria na resposta ao post (política reply).
+	Overflow string `json:"overflow,omitempty"`
+	// Warnings lista as correções de HTML e o truncamento aplicados ao preview.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type CaptionLayoutUpdateRequest struct {
+	Position  string `json:"position" binding:"required"`
+	Separator string `json:"separator"`
+}
+
+type CaptionOverflowUpdateRequest struct {
+	Policy string `json:"policy" binding:"required"`
+}
+
+// TranslationUpdateRequest configura a tradução automática. Language vazio
+// desativa a tradução.
+type TranslationUpdateRequest struct {
+	Language string `json:"language"`
+	Mode     string `json:"mode"`
+}
+
 type NewPackCaptionUpdateRequest struct {
 	Caption                string  `json:"caption"`
 	NewPackCaption         string  `json:"newPackCaption"`
@@ -24,6 +59,10 @@ type ReactionsUpdateRequest struct {
 	Reactions string `json:"reactions"`
 }
 
+type ReactionModeUpdateRequest struct {
+	Mode string `json:"mode" binding:"required"`
+}
+
 type ReactionPositionUpdateRequest struct {
 	ReactionPosition int `json:"reactionPosition"`
 }
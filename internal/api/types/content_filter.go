@@ -0,0 +1,10 @@
+package types
+
+type ContentFilterRequest struct {
+	Enabled        bool     `json:"enabled"`
+	Action         string   `json:"action"`
+	BannedWords    []string `json:"bannedWords"`
+	Patterns       []string `json:"patterns"`
+	BlockedDomains []string `json:"blockedDomains"`
+	BlockedTypes   []string `json:"blockedTypes"`
+}
@@ -4,6 +4,25 @@ type CreateCustomCaptionRequest struct {
 	Code        string `json:"code" binding:"required"`
 	Caption     string `json:"caption" binding:"required"`
 	LinkPreview bool   `json:"linkPreview"`
+	// Position e Separator vazios herdam a configuração do canal.
+	Position  string `json:"position"`
+	Separator string `json:"separator"`
+}
+
+// CaptionRuleRequest cria ou substitui uma regra de seleção de legenda. Listas
+// (hashtags, palavras-chave, tipos e origens) são separadas por vírgula.
+type CaptionRuleRequest struct {
+	CaptionID     string `json:"captionId" binding:"required"`
+	Name          string `json:"name"`
+	Priority      int    `json:"priority"`
+	Enabled       *bool  `json:"enabled"`
+	Hashtags      string `json:"hashtags"`
+	Keywords      string `json:"keywords"`
+	Pattern       string `json:"pattern"`
+	MessageTypes  string `json:"messageTypes"`
+	ForwardedFrom string `json:"forwardedFrom"`
+	StartTime     string `json:"startTime"`
+	EndTime       string `json:"endTime"`
 }
 
 type CreateCustomCaptionResponse struct {
@@ -0,0 +1,17 @@
+package types
+
+type LinkRewriteRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type LinkSettingsRequest struct {
+	Enabled      bool                 `json:"enabled"`
+	UTMSource    string               `json:"utmSource"`
+	UTMMedium    string               `json:"utmMedium"`
+	UTMCampaign  string               `json:"utmCampaign"`
+	UTMContent   string               `json:"utmContent"`
+	AllowDomains []string             `json:"allowDomains"`
+	DenyDomains  []string             `json:"denyDomains"`
+	Rewrites     []LinkRewriteRequest `json:"rewrites"`
+}
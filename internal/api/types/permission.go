@@ -22,6 +22,10 @@ type UpdateButtonsPermissionRequest struct {
 	GIF      *bool `json:"gif" binding:"required"`
 }
 
+type UpdateProcessEditsRequest struct {
+	ProcessEdits *bool `json:"processEdits" binding:"required"`
+}
+
 type UpdatePermissionsResponse struct {
 	Success bool                   `json:"success"`
 	Message string                 `json:"message"`
@@ -0,0 +1,8 @@
+package types
+
+import "time"
+
+type CreateScheduledPostRequest struct {
+	SessionID   string    `json:"sessionId" binding:"required"`
+	ScheduledAt time.Time `json:"scheduledAt" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package types
+
+// SeparatorSettingsRequest ajusta o rodízio e o intervalo de silêncio do
+// separador.
+type SeparatorSettingsRequest struct {
+	Rotation        string `json:"rotation"`
+	CooldownMinutes int    `json:"cooldownMinutes"`
+}
+
+// SeparatorVariantRequest define quando a variante é usada: em um tipo de
+// mensagem, em uma legenda customizada (hashtag) ou, com os dois vazios, no
+// rodízio com o sticker principal.
+type SeparatorVariantRequest struct {
+	MessageType string `json:"messageType"`
+	Hashtag     string `json:"hashtag"`
+}
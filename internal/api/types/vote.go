@@ -0,0 +1,57 @@
+package types
+
+import "time"
+
+// VoteAnalyticsResponse resume os votos de reação nos posts do canal.
+type VoteAnalyticsResponse struct {
+	Days         int       `json:"days"`
+	Since        time.Time `json:"since"`
+	TotalVotes   int64     `json:"totalVotes"`
+	UniqueVoters int64     `json:"uniqueVoters"`
+	// NativeReactions é a parte do total que veio de reações nativas.
+	NativeReactions int64        `json:"nativeReactions"`
+	Posts           int64        `json:"posts"`
+	ByEmoji         []EmojiVotes `json:"byEmoji"`
+	ByDay           []DayVotes   `json:"byDay"`
+	TopPosts        []PostVotes  `json:"topPosts"`
+}
+
+type EmojiVotes struct {
+	Emoji string  `json:"emoji"`
+	Votes int64   `json:"votes"`
+	Share float64 `json:"share"`
+}
+
+type DayVotes struct {
+	Date  string `json:"date"`
+	Votes int64  `json:"votes"`
+}
+
+type PostVotes struct {
+	MessageID int              `json:"messageId"`
+	Link      string           `json:"link"`
+	Votes     int64            `json:"votes"`
+	Emojis    map[string]int64 `json:"emojis,omitempty"`
+}
+
+// VoteExportRow é uma linha da exportação: votos de um emoji em um post.
+type VoteExportRow struct {
+	MessageID int    `json:"messageId"`
+	Link      string `json:"link"`
+	Emoji     string `json:"emoji"`
+	Votes     int64  `json:"votes"`
+	Source    string `json:"source"` // buttons ou native
+}
+
+type VoteSettingsRequest struct {
+	Mode            string `json:"mode"`
+	HideCounts      bool   `json:"hideCounts"`
+	DurationMinutes int    `json:"durationMinutes"`
+	ShowResults     bool   `json:"showResults"`
+}
+
+// VotePollRequest muda o prazo da votação de um post; closesAt nulo reabre a
+// votação sem prazo.
+type VotePollRequest struct {
+	ClosesAt *time.Time `json:"closesAt"`
+}
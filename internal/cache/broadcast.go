@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ### BROADCAST QUEUE ### \\
+
+// A fila de broadcast fica no Redis para que qualquer réplica da API possa
+// enfileirar envios, que são consumidos apenas pelos workers da instância líder.
+const broadcastQueueKey = "broadcast:queue"
+
+const broadcastPushBatch = 1000
+
+func (s *Service) PushBroadcastJobs(ctx context.Context, jobs ...[]byte) error {
+	client := GetRedisClient()
+	for start := 0; start < len(jobs); start += broadcastPushBatch {
+		end := min(start+broadcastPushBatch, len(jobs))
+		values := make([]interface{}, 0, end-start)
+		for _, job := range jobs[start:end] {
+			values = append(values, job)
+		}
+		if err := client.LPush(ctx, broadcastQueueKey, values...).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PopBroadcastJob aguarda até timeout pelo próximo envio; retorna nil quando a fila continua vazia.
+func (s *Service) PopBroadcastJob(ctx context.Context, timeout time.Duration) ([]byte, error) {
+	res, err := GetRedisClient().BRPop(ctx, timeout, broadcastQueueKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// BRPop retorna [chave, valor].
+	return []byte(res[1]), nil
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -67,22 +68,29 @@ func (s *Service) GetSession(ctx context.Context, key string) (*ChannelPayload,
 }
 
 func (s *Service) Get(ctx context.Context, key string, dest interface{}) error {
+	keyspace := metrics.Keyspace(key)
+
 	// 1. Tenta L1 (Local)
 	if val, found := localCache.Get(key); found {
 		if data, ok := val.([]byte); ok {
+			metrics.CacheLookups.WithLabelValues("l1", keyspace, "hit").Inc()
 			return json.Unmarshal(data, dest)
 		}
 	}
+	metrics.CacheLookups.WithLabelValues("l1", keyspace, "miss").Inc()
 
 	client := GetRedisClient()
 
 	data, err := client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
+			metrics.CacheLookups.WithLabelValues("l2", keyspace, "miss").Inc()
 			return fmt.Errorf("cache miss")
 		}
+		metrics.CacheLookups.WithLabelValues("l2", keyspace, "error").Inc()
 		return fmt.Errorf("failed to get from cache: %w", err)
 	}
+	metrics.CacheLookups.WithLabelValues("l2", keyspace, "hit").Inc()
 
 	// Salva no L1 para a próxima (serializado para manter consistência no Get genérico)
 	localCache.Set(key, []byte(data), 5*time.Minute)
@@ -96,11 +104,12 @@ func (s *Service) Get(ctx context.Context, key string, dest interface{}) error {
 }
 
 func (s *Service) GetChannel(ctx context.Context, channelID int64) (*models.Channel, error) {
-	key := fmt.Sprintf("channel:v2:%d", channelID)
+	key := channelKey(channelID)
 
 	// 1. Tenta L1
 	if val, found := localCache.Get(key); found {
 		if channel, ok := val.(*models.Channel); ok {
+			metrics.CacheLookups.WithLabelValues("l1", "channel", "hit").Inc()
 			return channel, nil
 		}
 	}
@@ -118,29 +127,36 @@ func (s *Service) GetChannel(ctx context.Context, channelID int64) (*models.Chan
 }
 
 func (s *Service) SetChannel(ctx context.Context, channel *models.Channel) error {
-	key := fmt.Sprintf("channel:v2:%d", channel.ID)
+	key := channelKey(channel.ID)
 	// Salva no L1
 	localCache.Set(key, channel, 5*time.Minute)
 	// Salva no L2 (Redis)
 	return s.Set(ctx, key, channel, 1*time.Hour)
 }
 
+// InvalidateChannel descarta a configuração do canal no L1, no Redis e nos
+// caches registrados em OnChannelInvalidated, e avisa as outras instâncias.
 func (s *Service) InvalidateChannel(ctx context.Context, channelID int64) error {
-	key := fmt.Sprintf("channel:v2:%d", channelID)
-	localCache.Delete(key)
+	key := channelKey(channelID)
+	evictChannel(channelID)
 
 	client := GetRedisClient()
 	client.Del(ctx, key)
 
 	// Também limpa o debounce de atualização
 	updateKey := fmt.Sprintf("last_update:channel:%d", channelID)
-	return client.Del(ctx, updateKey).Err()
+	err := client.Del(ctx, updateKey).Err()
+
+	publishInvalidation(ctx, invalidationMessage{Kind: invalidateChannel, ChannelID: channelID})
+	return err
 }
 
 func (s *Service) Delete(ctx context.Context, key string) error {
 	localCache.Delete(key)
 	client := GetRedisClient()
-	return client.Del(ctx, key).Err()
+	err := client.Del(ctx, key).Err()
+	publishInvalidation(ctx, invalidationMessage{Kind: invalidateKey, Key: key})
+	return err
 }
 
 func (s *Service) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
@@ -188,31 +204,39 @@ func (s *Service) GetSelectedChannel(ctx context.Context, userID int64) (int64,
 
 // ### SEPARATOR CHANNEL ## \\
 
-func (s *Service) SetAwaitingStickerSeparator(ctx context.Context, userID, channelID int64) error {
+// SetAwaitingStickerSeparator aguarda o sticker do separador do canal. target
+// indica onde o sticker entra: vazio troca o principal; os demais valores são
+// interpretados pelo fluxo do bot (variantes do pool).
+func (s *Service) SetAwaitingStickerSeparator(ctx context.Context, userID, channelID int64, target string) error {
 	client := GetRedisClient()
 
 	key := fmt.Sprintf("awaiting_sticker:%d", userID)
-	return client.Set(ctx, key, channelID, 5*time.Minute).Err()
+	value := strconv.FormatInt(channelID, 10)
+	if target != "" {
+		value += "|" + target
+	}
+	return client.Set(ctx, key, value, 5*time.Minute).Err()
 }
 
-func (s *Service) GetAwaitingStickerSeparator(ctx context.Context, userID int64) (int64, error) {
+func (s *Service) GetAwaitingStickerSeparator(ctx context.Context, userID int64) (int64, string, error) {
 	client := GetRedisClient()
 
 	key := fmt.Sprintf("awaiting_sticker:%d", userID)
 	data, err := client.Get(ctx, key).Result()
 	if err != nil {
 		if err.Error() == "redis: nil" {
-			return 0, fmt.Errorf("session not found or expired")
+			return 0, "", fmt.Errorf("session not found or expired")
 		}
-		return 0, fmt.Errorf("failed to get from cache: %w", err)
+		return 0, "", fmt.Errorf("failed to get from cache: %w", err)
 	}
 
+	data, target, _ := strings.Cut(data, "|")
 	channelID, err := strconv.ParseInt(data, 10, 64)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
-	return channelID, nil
+	return channelID, target, nil
 }
 
 func (s *Service) DeleteAwaitingStickerSeparator(ctx context.Context, userID int64) error {
@@ -398,13 +422,10 @@ func (s *Service) ShouldUpdateChannel(ctx context.Context, channelID int64) bool
 
 // ### DELETE ALL SESSIONS ### \\\
 func (s *Service) DeleteAllUserSessionsBySuffix(ctx context.Context, userID int64) (int64, error) {
-	// 1. Limpa o cache local (RAM)
-	suffix := ":" + strconv.FormatInt(userID, 10)
-	for k := range localCache.Items() {
-		if strings.HasSuffix(k, suffix) {
-			localCache.Delete(k)
-		}
-	}
+	// 1. Limpa o cache local (RAM), aqui e nas outras instâncias
+	suffix := suffixForUser(userID)
+	evictSuffix(suffix)
+	publishInvalidation(ctx, invalidationMessage{Kind: invalidateSuffix, Key: suffix})
 
 	// 2. Limpa o Redis
 	client := GetRedisClient()
@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ### MEDIA GROUP (ÁLBUNS) ### \\
+
+// As partes de um álbum podem chegar em réplicas diferentes. Cada parte é
+// adicionada à lista do grupo e empurra o prazo de fechamento; o grupo só é
+// fechado depois do prazo e por uma única instância. Os scripts garantem que
+// uma parte nunca entra em um grupo já fechado.
+
+const (
+	mediaGroupBaseWindow = 800 * time.Millisecond
+	mediaGroupItemWindow = 200 * time.Millisecond
+	mediaGroupMaxWindow  = 2 * time.Second
+	mediaGroupItemsTTL   = 2 * time.Minute
+	// MediaGroupProcessedTTL é por quanto tempo partes atrasadas de um álbum já enviado são ignoradas.
+	MediaGroupProcessedTTL = 30 * time.Minute
+)
+
+// KEYS: itens, prazo, processado. ARGV: item, base ms, por item ms, máximo ms, agora ms, ttl ms.
+// Retorna a janela em ms até o fechamento ou 0 quando o grupo já foi processado.
+var appendMediaGroupScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[3]) == 1 then
+	return 0
+end
+local n = redis.call('RPUSH', KEYS[1], ARGV[1])
+local window = tonumber(ARGV[2]) + n * tonumber(ARGV[3])
+if window > tonumber(ARGV[4]) then
+	window = tonumber(ARGV[4])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+redis.call('SET', KEYS[2], tonumber(ARGV[5]) + window, 'PX', ARGV[6])
+return window
+`)
+
+// KEYS: itens, prazo, processado. ARGV: agora ms, ttl do marcador ms.
+// Retorna {-1} se outra instância já fechou o grupo, {ms restantes} se o prazo
+// ainda não venceu ou {0, itens...} para a instância que fechou o grupo.
+var claimMediaGroupScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[3]) == 1 then
+	return {-1}
+end
+local deadline = tonumber(redis.call('GET', KEYS[2]) or '0')
+local now = tonumber(ARGV[1])
+if deadline > now then
+	return {deadline - now}
+end
+redis.call('SET', KEYS[3], '1', 'PX', ARGV[2])
+local items = redis.call('LRANGE', KEYS[1], 0, -1)
+redis.call('DEL', KEYS[1], KEYS[2])
+local result = {0}
+for i = 1, #items do
+	result[#result + 1] = items[i]
+end
+return result
+`)
+
+func mediaGroupKeys(groupID string) []string {
+	// A hash tag {groupID} mantém as três chaves no mesmo slot em Redis Cluster.
+	return []string{
+		fmt.Sprintf("mediagroup:{%s}:items", groupID),
+		fmt.Sprintf("mediagroup:{%s}:deadline", groupID),
+		fmt.Sprintf("mediagroup:{%s}:processed", groupID),
+	}
+}
+
+// AppendMediaGroupItem adiciona uma parte ao álbum e retorna em quanto tempo
+// ele deve ser fechado. ok é false quando o álbum já foi processado.
+func (s *Service) AppendMediaGroupItem(ctx context.Context, groupID string, item []byte) (window time.Duration, ok bool, err error) {
+	ms, err := appendMediaGroupScript.Run(ctx, GetRedisClient(), mediaGroupKeys(groupID),
+		item,
+		mediaGroupBaseWindow.Milliseconds(),
+		mediaGroupItemWindow.Milliseconds(),
+		mediaGroupMaxWindow.Milliseconds(),
+		time.Now().UnixMilli(),
+		mediaGroupItemsTTL.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	if ms == 0 {
+		return 0, false, nil
+	}
+	return time.Duration(ms) * time.Millisecond, true, nil
+}
+
+// ClaimMediaGroup tenta fechar o álbum. Retorna as partes para a instância que
+// conseguiu fechá-lo; wait > 0 indica que chegaram partes novas e o fechamento
+// deve ser tentado de novo depois desse tempo. Sem partes e sem espera, outra
+// instância já fechou o grupo.
+func (s *Service) ClaimMediaGroup(ctx context.Context, groupID string) (items [][]byte, wait time.Duration, err error) {
+	res, err := claimMediaGroupScript.Run(ctx, GetRedisClient(), mediaGroupKeys(groupID),
+		time.Now().UnixMilli(),
+		MediaGroupProcessedTTL.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(res) == 0 {
+		return nil, 0, fmt.Errorf("resposta vazia ao fechar media group %s", groupID)
+	}
+
+	status, _ := res[0].(int64)
+	switch {
+	case status < 0:
+		return nil, 0, nil
+	case status > 0:
+		return nil, time.Duration(status) * time.Millisecond, nil
+	}
+
+	items = make([][]byte, 0, len(res)-1)
+	for _, raw := range res[1:] {
+		if str, ok := raw.(string); ok {
+			items = append(items, []byte(str))
+		}
+	}
+	return items, 0, nil
+}
+
+// ### NEWPACK ### \\
+
+const newPackStateTTL = 30 * time.Minute
+
+func newPackKey(channelID int64) string {
+	return fmt.Sprintf("newpack:%d", channelID)
+}
+
+func (s *Service) SetNewPackState(ctx context.Context, channelID int64, state NewPackState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return GetRedisClient().Set(ctx, newPackKey(channelID), data, newPackStateTTL).Err()
+}
+
+// TakeNewPackState lê e remove o estado em uma única operação, para que apenas
+// uma réplica processe o sticker de resposta ao !newpack.
+func (s *Service) TakeNewPackState(ctx context.Context, channelID int64) (*NewPackState, error) {
+	data, err := GetRedisClient().GetDel(ctx, newPackKey(channelID)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var state NewPackState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *Service) DeleteNewPackState(ctx context.Context, channelID int64) error {
+	return GetRedisClient().Del(ctx, newPackKey(channelID)).Err()
+}
+
+// ### ROTAÇÃO DE LEGENDAS ### \\
+
+func captionRotationKey(channelID int64) string {
+	return fmt.Sprintf("caption_rotation:%d", channelID)
+}
+
+// NextCaptionRotation avança o contador de rodízio do pool de legendas do
+// canal. O contador fica no Redis para que todas as réplicas sigam a mesma
+// sequência.
+func (s *Service) NextCaptionRotation(ctx context.Context, channelID int64) (int64, error) {
+	return GetRedisClient().Incr(ctx, captionRotationKey(channelID)).Result()
+}
+
+// ### SEPARADOR ### \\
+
+func separatorRotationKey(channelID int64) string {
+	return fmt.Sprintf("separator_rotation:%d", channelID)
+}
+
+func separatorLastPostKey(channelID int64) string {
+	return fmt.Sprintf("separator_last_post:%d", channelID)
+}
+
+// NextSeparatorRotation avança o contador de rodízio dos stickers separadores
+// do canal, compartilhado entre as réplicas.
+func (s *Service) NextSeparatorRotation(ctx context.Context, channelID int64) (int64, error) {
+	return GetRedisClient().Incr(ctx, separatorRotationKey(channelID)).Result()
+}
+
+// MarkSeparatorPost registra um novo post no canal e retorna o número dele na
+// sequência do canal, compartilhada entre as réplicas. A chave expira depois
+// do intervalo de silêncio, quando nenhum separador pendente a consulta mais.
+func (s *Service) MarkSeparatorPost(ctx context.Context, channelID int64, quiet time.Duration) (int64, error) {
+	key := separatorLastPostKey(channelID)
+	pipe := GetRedisClient().TxPipeline()
+	seq := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, quiet+time.Minute)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return seq.Val(), nil
+}
+
+// IsLastSeparatorPost indica se seq ainda é o último post do canal, ou seja,
+// se o canal ficou em silêncio desde que ele foi registrado.
+func (s *Service) IsLastSeparatorPost(ctx context.Context, channelID int64, seq int64) (bool, error) {
+	last, err := GetRedisClient().Get(ctx, separatorLastPostKey(channelID)).Int64()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return last == seq, err
+}
+
+// ### TRADUÇÃO ### \\
+
+const translationTTL = 7 * 24 * time.Hour
+
+// GetTranslation busca uma tradução já feita (ver translate.CacheKey).
+func (s *Service) GetTranslation(ctx context.Context, key string) (string, bool) {
+	value, err := GetRedisClient().Get(ctx, key).Result()
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// SetTranslation guarda a tradução por uma semana. Falhas são ignoradas: o
+// cache só evita chamadas repetidas ao tradutor.
+func (s *Service) SetTranslation(ctx context.Context, key, value string) {
+	_ = GetRedisClient().Set(ctx, key, value, translationTTL).Err()
+}
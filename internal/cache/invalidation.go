@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel é o canal Redis pub/sub usado para avisar as outras
+// instâncias (réplicas do bot/API) que um item do cache L1 ficou desatualizado.
+const invalidationChannel = "cache:invalidate"
+
+const (
+	invalidateChannel = "channel"
+	invalidateKey     = "key"
+	invalidateSuffix  = "suffix"
+)
+
+type invalidationMessage struct {
+	Kind      string `json:"kind"`
+	ChannelID int64  `json:"channelId,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Origin    string `json:"origin"`
+}
+
+// instanceID identifica este processo para ignorar as próprias mensagens.
+var instanceID = newInstanceID()
+
+var (
+	channelHooksMu sync.RWMutex
+	channelHooks   []func(channelID int64)
+)
+
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// OnChannelInvalidated registra uma função chamada sempre que a configuração de
+// um canal é invalidada, nesta ou em outra instância. Usado por caches em
+// memória fora deste pacote (ex: PermissionManager). channelID 0 indica que
+// todos os canais devem ser descartados.
+func OnChannelInvalidated(fn func(channelID int64)) {
+	channelHooksMu.Lock()
+	defer channelHooksMu.Unlock()
+	channelHooks = append(channelHooks, fn)
+}
+
+func channelKey(channelID int64) string {
+	return fmt.Sprintf("channel:v2:%d", channelID)
+}
+
+// evictChannel remove o canal do L1 e avisa os caches registrados.
+func evictChannel(channelID int64) {
+	if channelID == 0 {
+		localCache.Flush()
+	} else {
+		localCache.Delete(channelKey(channelID))
+	}
+
+	channelHooksMu.RLock()
+	hooks := channelHooks
+	channelHooksMu.RUnlock()
+	for _, fn := range hooks {
+		fn(channelID)
+	}
+}
+
+func evictSuffix(suffix string) {
+	for k := range localCache.Items() {
+		if strings.HasSuffix(k, suffix) {
+			localCache.Delete(k)
+		}
+	}
+}
+
+// publishInvalidation avisa as outras instâncias. Falhas só são registradas:
+// no pior caso as réplicas servem o valor antigo até o TTL do L1 (5 minutos).
+func publishInvalidation(ctx context.Context, msg invalidationMessage) {
+	msg.Origin = instanceID
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := GetRedisClient().Publish(ctx, invalidationChannel, data).Err(); err != nil {
+		logger.Warn("CACHE", "Falha ao publicar invalidação %s: %v", msg.Kind, err)
+		return
+	}
+	metrics.CacheInvalidations.WithLabelValues(msg.Kind, "published").Inc()
+}
+
+// ListenInvalidations aplica no L1 local as invalidações publicadas pelas
+// outras instâncias até ctx ser cancelado. Ao (re)conectar, o L1 inteiro é
+// descartado, já que mensagens podem ter sido perdidas enquanto a conexão caiu.
+func (s *Service) ListenInvalidations(ctx context.Context) {
+	pubsub := GetRedisClient().Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	subscribed := false
+	for {
+		received, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("CACHE", "Conexão de invalidação perdida, reconectando: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch m := received.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			if subscribed {
+				logger.Info("CACHE", "Invalidação reconectada, descartando cache local")
+				evictChannel(0)
+			} else {
+				logger.Info("CACHE", "Escutando invalidações de cache (instância %s)", instanceID)
+			}
+			subscribed = true
+		case *redis.Message:
+			applyInvalidation(m.Payload)
+		}
+	}
+}
+
+func applyInvalidation(payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		logger.Warn("CACHE", "Mensagem de invalidação inválida: %v", err)
+		return
+	}
+	if msg.Origin == instanceID {
+		return
+	}
+
+	switch msg.Kind {
+	case invalidateChannel:
+		evictChannel(msg.ChannelID)
+	case invalidateKey:
+		localCache.Delete(msg.Key)
+	case invalidateSuffix:
+		evictSuffix(msg.Key)
+	default:
+		return
+	}
+	metrics.CacheInvalidations.WithLabelValues(msg.Kind, "received").Inc()
+}
+
+func suffixForUser(userID int64) string {
+	return ":" + strconv.FormatInt(userID, 10)
+}
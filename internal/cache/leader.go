@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	leaderTTL           = 15 * time.Second
+	leaderRenewInterval = 5 * time.Second
+)
+
+// Renova a liderança apenas se ela ainda pertence a esta instância.
+var renewLeaderScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseLeaderScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Leader elege, via lease no Redis, uma única instância para executar rotinas
+// que não podem rodar em paralelo entre réplicas (limpeza de eventos,
+// broadcasts). As tarefas registradas em OnElected rodam enquanto a instância
+// for líder e têm o ctx cancelado quando a liderança é perdida.
+type Leader struct {
+	key    string
+	tasks  []func(ctx context.Context)
+	leader atomic.Bool
+	done   chan struct{}
+}
+
+func NewLeader(name string) *Leader {
+	return &Leader{
+		key:  "leader:" + name,
+		done: make(chan struct{}),
+	}
+}
+
+// OnElected registra uma tarefa. Deve ser chamado antes de Run.
+func (l *Leader) OnElected(task func(ctx context.Context)) {
+	l.tasks = append(l.tasks, task)
+}
+
+// IsLeader informa se esta instância é a líder no momento.
+func (l *Leader) IsLeader() bool {
+	return l.leader.Load()
+}
+
+// Done é fechado quando Run termina e as tarefas da liderança já pararam.
+func (l *Leader) Done() <-chan struct{} {
+	return l.done
+}
+
+// Run disputa a liderança até ctx ser cancelado. Ao sair, aguarda as tarefas
+// e libera o lease para outra réplica assumir sem esperar o TTL.
+func (l *Leader) Run(ctx context.Context) {
+	defer close(l.done)
+
+	var (
+		stopTasks func()
+		lastRenew time.Time
+	)
+	stepDown := func() {
+		if !l.leader.Swap(false) {
+			return
+		}
+		stopTasks()
+	}
+
+	ticker := time.NewTicker(leaderRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		if l.leader.Load() {
+			owned, err := l.renew(ctx)
+			switch {
+			case err == nil && owned:
+				lastRenew = time.Now()
+			case ctx.Err() != nil:
+			case err == nil || time.Since(lastRenew) > leaderTTL-leaderRenewInterval:
+				// Outra instância assumiu, ou o lease pode ter expirado sem renovação.
+				logger.Warn("APP", "⚠️ Liderança perdida (%s)", l.key)
+				stepDown()
+			}
+		} else if l.acquire(ctx) {
+			lastRenew = time.Now()
+			logger.Info("APP", "👑 Instância %s eleita líder (%s)", instanceID, l.key)
+			l.leader.Store(true)
+			stopTasks = l.startTasks()
+		}
+
+		select {
+		case <-ctx.Done():
+			wasLeader := l.leader.Load()
+			stepDown()
+			if wasLeader {
+				l.release()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startTasks inicia as tarefas da liderança e retorna a função que as cancela
+// e aguarda terminarem.
+func (l *Leader) startTasks() (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var running sync.WaitGroup
+	for _, task := range l.tasks {
+		running.Add(1)
+		go func(task func(context.Context)) {
+			defer running.Done()
+			task(ctx)
+		}(task)
+	}
+	return func() {
+		cancel()
+		running.Wait()
+	}
+}
+
+func (l *Leader) acquire(ctx context.Context) bool {
+	ok, err := GetRedisClient().SetNX(ctx, l.key, instanceID, leaderTTL).Result()
+	if err != nil {
+		logger.Warn("APP", "Falha ao disputar liderança (%s): %v", l.key, err)
+		return false
+	}
+	return ok
+}
+
+func (l *Leader) renew(ctx context.Context) (bool, error) {
+	renewed, err := renewLeaderScript.Run(ctx, GetRedisClient(), []string{l.key}, instanceID, leaderTTL.Milliseconds()).Int64()
+	return renewed == 1, err
+}
+
+func (l *Leader) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = releaseLeaderScript.Run(ctx, GetRedisClient(), []string{l.key}, instanceID).Err()
+}
@@ -29,4 +29,13 @@ type PostBuilderState struct {
 	Reactions       string              `json:"reactions"`
 	Buttons         []PostBuilderButton `json:"buttons"`
 	Step            string              `json:"step"`
+	// OwnerID é quem criou a postagem; vazio no PostBuilder fixo, que qualquer
+	// usuário pode usar.
+	OwnerID int64 `json:"owner_id,omitempty"`
+}
+
+// NewPackState é o estado de um canal que recebeu !newpack e aguarda o sticker do pack.
+type NewPackState struct {
+	WaitingForSticker bool `json:"waiting_for_sticker"`
+	MessageID         int  `json:"message_id"`
 }
@@ -0,0 +1,326 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// ### VOTOS ### \\
+
+// Os votos dos botões de reação são contados no Redis: cada toque é aceito na
+// hora por um script e a mensagem fica marcada como pendente. Os votantes
+// alterados são gravados no banco em lote (ver TakeDirtyVoters) e o teclado
+// só é redesenhado uma vez por janela (ver ClaimVoteRender).
+//
+// Por mensagem ficam as escolhas de cada usuário (emojis separados por
+// vírgula), a contagem por emoji, os usuários pendentes e um marcador de que o
+// estado já foi carregado do banco.
+
+const (
+	voteStateTTL = 72 * time.Hour
+	// voteDirtyKey lista as mensagens com votos ainda não gravados no banco.
+	voteDirtyKey = "votes:dirty"
+)
+
+// ErrVotesNotLoaded indica que o estado da mensagem precisa ser carregado do
+// banco com SeedVotes antes de votar.
+var ErrVotesNotLoaded = fmt.Errorf("votos da mensagem não carregados")
+
+func voteKeys(message string) []string {
+	// A hash tag {message} mantém as chaves da mensagem no mesmo slot em Redis Cluster.
+	return []string{
+		fmt.Sprintf("votes:{%s}:choices", message),
+		fmt.Sprintf("votes:{%s}:counts", message),
+		fmt.Sprintf("votes:{%s}:pending", message),
+		fmt.Sprintf("votes:{%s}:loaded", message),
+	}
+}
+
+func voteRenderKey(message string) string {
+	return fmt.Sprintf("votes:{%s}:render", message)
+}
+
+func votePollKey(message string) string {
+	return fmt.Sprintf("votes:{%s}:poll", message)
+}
+
+// KEYS: escolhas, contagens, pendentes, carregado. ARGV: usuário, emoji,
+// múltipla (1/0), ttl ms. Retorna {-1} sem estado carregado ou {adicionado,
+// emoji, contagem, ...} com todas as contagens da mensagem.
+var toggleVoteScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[4]) == 0 then
+	return {-1}
+end
+local current = redis.call('HGET', KEYS[1], ARGV[1]) or ''
+local choices = {}
+local found = false
+for emoji in string.gmatch(current, '[^,]+') do
+	if emoji == ARGV[2] then
+		found = true
+	else
+		table.insert(choices, emoji)
+	end
+end
+local added = 0
+if found then
+	redis.call('HINCRBY', KEYS[2], ARGV[2], -1)
+else
+	if ARGV[3] ~= '1' then
+		for _, emoji in ipairs(choices) do
+			redis.call('HINCRBY', KEYS[2], emoji, -1)
+		end
+		choices = {}
+	end
+	table.insert(choices, ARGV[2])
+	redis.call('HINCRBY', KEYS[2], ARGV[2], 1)
+	added = 1
+end
+if #choices == 0 then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+else
+	redis.call('HSET', KEYS[1], ARGV[1], table.concat(choices, ','))
+end
+redis.call('HSET', KEYS[3], ARGV[1], '1')
+for i = 1, 4 do
+	redis.call('PEXPIRE', KEYS[i], ARGV[4])
+end
+local result = {added}
+local counts = redis.call('HGETALL', KEYS[2])
+for i = 1, #counts do
+	result[#result + 1] = counts[i]
+end
+return result
+`)
+
+// KEYS: escolhas, contagens, pendentes, carregado. ARGV: ttl ms, depois pares
+// usuário/emojis. Só grava quando o estado ainda não foi carregado.
+var seedVotesScript = redis.NewScript(`
+if redis.call('SET', KEYS[4], '1', 'NX', 'PX', ARGV[1]) == false then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+for i = 2, #ARGV, 2 do
+	redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+	for emoji in string.gmatch(ARGV[i + 1], '[^,]+') do
+		redis.call('HINCRBY', KEYS[2], emoji, 1)
+	end
+end
+for i = 1, 2 do
+	redis.call('PEXPIRE', KEYS[i], ARGV[1])
+end
+return 1
+`)
+
+// KEYS: escolhas, contagens, pendentes, carregado. Retorna pares
+// usuário/emojis (vazio quando o usuário não tem mais votos) dos pendentes e
+// limpa a lista.
+var takeDirtyVotersScript = redis.NewScript(`
+local users = redis.call('HKEYS', KEYS[3])
+redis.call('DEL', KEYS[3])
+local result = {}
+for _, user in ipairs(users) do
+	result[#result + 1] = user
+	result[#result + 1] = redis.call('HGET', KEYS[1], user) or ''
+end
+return result
+`)
+
+// ToggleVote alterna o voto do usuário no emoji e retorna as contagens
+// atualizadas da mensagem. Sem multiple, votar em outro emoji substitui o voto
+// anterior. Retorna ErrVotesNotLoaded quando a mensagem ainda não foi
+// carregada do banco.
+func (s *Service) ToggleVote(ctx context.Context, message string, userID int64, emoji string, multiple bool) (bool, map[string]int64, error) {
+	flag := "0"
+	if multiple {
+		flag = "1"
+	}
+	res, err := toggleVoteScript.Run(ctx, GetRedisClient(), voteKeys(message),
+		strconv.FormatInt(userID, 10), emoji, flag, voteStateTTL.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return false, nil, err
+	}
+	if len(res) == 0 {
+		return false, nil, fmt.Errorf("resposta vazia ao votar em %s", message)
+	}
+	if status, _ := res[0].(int64); status < 0 {
+		return false, nil, ErrVotesNotLoaded
+	}
+
+	// Registrada depois do script: se o flush levar a mensagem antes disso, ela
+	// volta para a lista e os pendentes são gravados na rodada seguinte.
+	if err := GetRedisClient().SAdd(ctx, voteDirtyKey, message).Err(); err != nil {
+		return false, nil, err
+	}
+
+	added, _ := res[0].(int64)
+	return added == 1, parseVoteCounts(res[1:]), nil
+}
+
+// SeedVotes carrega as escolhas de cada usuário (lidas do banco) se a
+// mensagem ainda não estiver no Redis.
+func (s *Service) SeedVotes(ctx context.Context, message string, choices map[int64][]string) error {
+	args := make([]interface{}, 0, 1+2*len(choices))
+	args = append(args, voteStateTTL.Milliseconds())
+	for userID, emojis := range choices {
+		if len(emojis) == 0 {
+			continue
+		}
+		args = append(args, strconv.FormatInt(userID, 10), strings.Join(emojis, ","))
+	}
+	return seedVotesScript.Run(ctx, GetRedisClient(), voteKeys(message), args...).Err()
+}
+
+// VoteCounts retorna as contagens da mensagem; loaded é false quando ela
+// ainda não está no Redis e o banco deve ser consultado.
+func (s *Service) VoteCounts(ctx context.Context, message string) (counts map[string]int64, loaded bool, err error) {
+	keys := voteKeys(message)
+	client := GetRedisClient()
+
+	pipe := client.Pipeline()
+	exists := pipe.Exists(ctx, keys[3])
+	all := pipe.HGetAll(ctx, keys[1])
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+
+	counts = make(map[string]int64)
+	for emoji, raw := range all.Val() {
+		if n, _ := strconv.ParseInt(raw, 10, 64); n > 0 {
+			counts[emoji] = n
+		}
+	}
+	return counts, true, nil
+}
+
+// UserVotes retorna os emojis votados pelo usuário; loaded é false quando a
+// mensagem ainda não está no Redis.
+func (s *Service) UserVotes(ctx context.Context, message string, userID int64) (emojis []string, loaded bool, err error) {
+	keys := voteKeys(message)
+	client := GetRedisClient()
+
+	pipe := client.Pipeline()
+	exists := pipe.Exists(ctx, keys[3])
+	choice := pipe.HGet(ctx, keys[0], strconv.FormatInt(userID, 10))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, false, err
+	}
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+	if choice.Val() == "" {
+		return nil, true, nil
+	}
+	return strings.Split(choice.Val(), ","), true, nil
+}
+
+// PopDirtyVoteMessages retira até limit mensagens com votos pendentes.
+func (s *Service) PopDirtyVoteMessages(ctx context.Context, limit int) ([]string, error) {
+	messages, err := GetRedisClient().SPopN(ctx, voteDirtyKey, int64(limit)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return messages, err
+}
+
+// TakeDirtyVoters retorna as escolhas atuais dos usuários que votaram desde o
+// último flush da mensagem. Usuários sem votos vêm com a lista vazia.
+func (s *Service) TakeDirtyVoters(ctx context.Context, message string) (map[int64][]string, error) {
+	res, err := takeDirtyVotersScript.Run(ctx, GetRedisClient(), voteKeys(message)).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	voters := make(map[int64][]string, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		userID, err := strconv.ParseInt(res[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		var emojis []string
+		if res[i+1] != "" {
+			emojis = strings.Split(res[i+1], ",")
+		}
+		voters[userID] = emojis
+	}
+	return voters, nil
+}
+
+// MarkVotersDirty devolve a mensagem e os usuários para a lista de pendentes,
+// quando a gravação no banco falhou.
+func (s *Service) MarkVotersDirty(ctx context.Context, message string, userIDs []int64) error {
+	client := GetRedisClient()
+	if len(userIDs) > 0 {
+		fields := make([]interface{}, 0, 2*len(userIDs))
+		for _, userID := range userIDs {
+			fields = append(fields, strconv.FormatInt(userID, 10), "1")
+		}
+		if err := client.HSet(ctx, voteKeys(message)[2], fields...).Err(); err != nil {
+			return err
+		}
+	}
+	return client.SAdd(ctx, voteDirtyKey, message).Err()
+}
+
+// ClaimVoteRender reserva o redesenho do teclado da mensagem pela janela.
+// Retorna false quando outro voto (nesta ou em outra instância) já agendou um.
+func (s *Service) ClaimVoteRender(ctx context.Context, message string, window time.Duration) (bool, error) {
+	// A reserva dura mais que a janela para cobrir atrasos do agendamento; o
+	// redesenho a libera antes de ler as contagens.
+	return GetRedisClient().SetNX(ctx, voteRenderKey(message), "1", 4*window).Result()
+}
+
+// ReleaseVoteRender libera a reserva. Votos recebidos depois disso agendam um
+// novo redesenho.
+func (s *Service) ReleaseVoteRender(ctx context.Context, message string) error {
+	return GetRedisClient().Del(ctx, voteRenderKey(message)).Err()
+}
+
+// GetVotePoll retorna as regras de votação já resolvidas para a mensagem, ou
+// nil quando ainda não estão no Redis.
+func (s *Service) GetVotePoll(ctx context.Context, message string) (*models.VotePoll, error) {
+	data, err := GetRedisClient().Get(ctx, votePollKey(message)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var poll models.VotePoll
+	if err := json.Unmarshal(data, &poll); err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// SetVotePoll guarda as regras de votação da mensagem pelo mesmo prazo das
+// contagens, evitando consultar o banco a cada toque.
+func (s *Service) SetVotePoll(ctx context.Context, message string, poll *models.VotePoll) error {
+	data, err := json.Marshal(poll)
+	if err != nil {
+		return err
+	}
+	return GetRedisClient().Set(ctx, votePollKey(message), data, voteStateTTL).Err()
+}
+
+func parseVoteCounts(pairs []interface{}) map[string]int64 {
+	counts := make(map[string]int64, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		emoji, _ := pairs[i].(string)
+		raw, _ := pairs[i+1].(string)
+		if n, _ := strconv.ParseInt(raw, 10, 64); n > 0 {
+			counts[emoji] = n
+		}
+	}
+	return counts
+}
@@ -0,0 +1,137 @@
+// Package captionpool escolhe a variante de legenda de um canal com rotação
+// ativa: rodízio, sorteio ponderado ou por dia da semana.
+package captionpool
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+// Modos de rotação. ModeOff mantém a legenda padrão do canal.
+const (
+	ModeOff        = ""
+	ModeRoundRobin = "round_robin"
+	ModeWeighted   = "weighted"
+	ModeWeekday    = "weekday"
+)
+
+const MaxWeight = 100
+
+func IsValidMode(mode string) bool {
+	switch mode {
+	case ModeOff, ModeRoundRobin, ModeWeighted, ModeWeekday:
+		return true
+	}
+	return false
+}
+
+// NeedsSequence indica se o modo usa o contador de rodízio do canal.
+func NeedsSequence(mode string) bool {
+	return mode == ModeRoundRobin || mode == ModeWeekday
+}
+
+// Pick escolhe a variante para um post. seq é o contador de rodízio do canal
+// (crescente a cada post), postedAt a data do post no fuso do bot, que define
+// o dia da semana, e random(n) deve devolver um inteiro em [0, n).
+// Variantes desativadas ou restritas a outros dias são ignoradas; retorna nil
+// quando nenhuma serve, e o post cai na legenda padrão.
+func Pick(variants []models.CaptionVariant, mode string, seq int64, postedAt time.Time, random func(n int) int) *models.CaptionVariant {
+	if mode == ModeOff {
+		return nil
+	}
+
+	today := postedAt.Weekday()
+	var eligible, scheduled []*models.CaptionVariant
+	for i := range variants {
+		v := &variants[i]
+		if !v.Enabled {
+			continue
+		}
+		days, err := ParseWeekdays(v.Weekdays)
+		if err != nil {
+			continue
+		}
+		if len(days) == 0 {
+			eligible = append(eligible, v)
+		} else if slices.Contains(days, today) {
+			eligible = append(eligible, v)
+			scheduled = append(scheduled, v)
+		}
+	}
+
+	// No modo por dia, as variantes do dia têm preferência sobre as que valem
+	// para todos os dias.
+	if mode == ModeWeekday && len(scheduled) > 0 {
+		eligible = scheduled
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	if mode == ModeWeighted {
+		total := 0
+		for _, v := range eligible {
+			total += weight(v)
+		}
+		n := random(total)
+		for _, v := range eligible {
+			if n -= weight(v); n < 0 {
+				return v
+			}
+		}
+		return eligible[len(eligible)-1]
+	}
+
+	if seq < 0 {
+		seq = -seq
+	}
+	return eligible[seq%int64(len(eligible))]
+}
+
+func weight(v *models.CaptionVariant) int {
+	if v.Weight < 1 {
+		return 1
+	}
+	return v.Weight
+}
+
+// ParseWeekdays lê a lista de dias separada por vírgulas (0 = domingo).
+func ParseWeekdays(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, item := range splitList(value) {
+		n, err := strconv.Atoi(item)
+		if err != nil || n < 0 || n > 6 {
+			return nil, fmt.Errorf("dia da semana inválido %q (use 0 a 6, 0 = domingo)", item)
+		}
+		if !slices.Contains(days, time.Weekday(n)) {
+			days = append(days, time.Weekday(n))
+		}
+	}
+	return days, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Validate confere os campos de uma variante antes de salvá-la.
+func Validate(v *models.CaptionVariant) error {
+	if v.Weight < 1 || v.Weight > MaxWeight {
+		return fmt.Errorf("peso deve estar entre 1 e %d", MaxWeight)
+	}
+	if _, err := ParseWeekdays(v.Weekdays); err != nil {
+		return err
+	}
+	return nil
+}
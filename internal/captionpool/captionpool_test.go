@@ -0,0 +1,88 @@
+package captionpool
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestPickRoundRobinSkipsDisabled(t *testing.T) {
+	variants := []models.CaptionVariant{
+		{VariantID: "a", Enabled: true},
+		{VariantID: "b", Enabled: false},
+		{VariantID: "c", Enabled: true},
+	}
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	var got []string
+	for seq := int64(1); seq <= 4; seq++ {
+		got = append(got, Pick(variants, ModeRoundRobin, seq, now, nil).VariantID)
+	}
+	if want := []string{"c", "a", "c", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if v := Pick(variants, ModeOff, 1, now, nil); v != nil {
+		t.Fatalf("expected nil when rotation is off, got %s", v.VariantID)
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	variants := []models.CaptionVariant{
+		{VariantID: "light", Enabled: true, Weight: 1},
+		{VariantID: "heavy", Enabled: true, Weight: 3},
+	}
+	now := time.Now()
+
+	cases := map[int]string{0: "light", 1: "heavy", 3: "heavy"}
+	for n, want := range cases {
+		v := Pick(variants, ModeWeighted, 0, now, func(total int) int {
+			if total != 4 {
+				t.Fatalf("expected total weight 4, got %d", total)
+			}
+			return n
+		})
+		if v.VariantID != want {
+			t.Errorf("random %d: got %s, want %s", n, v.VariantID, want)
+		}
+	}
+}
+
+func TestPickWeekday(t *testing.T) {
+	variants := []models.CaptionVariant{
+		{VariantID: "any", Enabled: true},
+		{VariantID: "weekend", Enabled: true, Weekdays: "0,6"},
+	}
+	saturday := time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	if v := Pick(variants, ModeWeekday, 0, saturday, nil); v.VariantID != "weekend" {
+		t.Fatalf("expected weekend variant on saturday, got %s", v.VariantID)
+	}
+	if v := Pick(variants, ModeWeekday, 1, monday, nil); v.VariantID != "any" {
+		t.Fatalf("expected fallback variant on monday, got %s", v.VariantID)
+	}
+	// Fora do modo por dia, a restrição de dias continua valendo.
+	if v := Pick(variants, ModeRoundRobin, 1, monday, nil); v.VariantID != "any" {
+		t.Fatalf("expected restricted variant to be skipped, got %s", v.VariantID)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []models.CaptionVariant{
+		{Weight: 0},
+		{Weight: MaxWeight + 1},
+		{Weight: 1, Weekdays: "1,7"},
+		{Weight: 1, Weekdays: "seg"},
+	}
+	for _, v := range invalid {
+		if err := Validate(&v); err == nil {
+			t.Errorf("expected error for %+v", v)
+		}
+	}
+	if err := Validate(&models.CaptionVariant{Weight: 2, Weekdays: "1, 3,5"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
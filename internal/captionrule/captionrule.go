@@ -0,0 +1,208 @@
+// Package captionrule avalia as regras que escolhem a legenda customizada de
+// um post (hashtags, palavras-chave, regex, tipo de mensagem, origem do
+// encaminhamento e horário).
+package captionrule
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+const maxPatternLength = 512
+
+// Input descreve o post avaliado.
+type Input struct {
+	// Hashtags do post na ordem em que aparecem, sem o '#'.
+	Hashtags []string
+	// Text é o texto puro (sem HTML) do post.
+	Text        string
+	MessageType string
+	// ForwardedFrom traz os identificadores da origem do encaminhamento (ID
+	// numérico e username), vazio quando o post não é encaminhado.
+	ForwardedFrom []string
+	// Time é a data de publicação do post, já no fuso em que as faixas de
+	// horário são lidas.
+	Time time.Time
+}
+
+// Match é a regra que disparou e, quando foi por hashtag, qual delas.
+type Match struct {
+	Rule    *models.CaptionRule
+	Hashtag string
+}
+
+// Evaluate retorna a primeira regra ativa, por prioridade, cujas condições
+// casam com o post, ou nil para cair no fluxo padrão.
+func Evaluate(rules []models.CaptionRule, in Input) *Match {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	ordered := make([]*models.CaptionRule, 0, len(rules))
+	for i := range rules {
+		if rules[i].Enabled {
+			ordered = append(ordered, &rules[i])
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	for _, rule := range ordered {
+		if hashtag, ok := matches(rule, in); ok {
+			return &Match{Rule: rule, Hashtag: hashtag}
+		}
+	}
+	return nil
+}
+
+func matches(rule *models.CaptionRule, in Input) (hashtag string, ok bool) {
+	if !hasCondition(rule) {
+		return "", false
+	}
+
+	if tags := SplitList(rule.Hashtags); len(tags) > 0 {
+		for _, want := range tags {
+			want = strings.TrimPrefix(want, "#")
+			for _, got := range in.Hashtags {
+				if strings.EqualFold(want, got) {
+					hashtag = got
+					break
+				}
+			}
+			if hashtag != "" {
+				break
+			}
+		}
+		if hashtag == "" {
+			return "", false
+		}
+	}
+
+	if keywords := SplitList(rule.Keywords); len(keywords) > 0 {
+		text := strings.ToLower(in.Text)
+		if !slices.ContainsFunc(keywords, func(k string) bool { return strings.Contains(text, strings.ToLower(k)) }) {
+			return "", false
+		}
+	}
+
+	if rule.Pattern != "" {
+		re, err := compile(rule.Pattern)
+		if err != nil || !re.MatchString(in.Text) {
+			return "", false
+		}
+	}
+
+	if types := SplitList(rule.MessageTypes); len(types) > 0 && !slices.Contains(types, in.MessageType) {
+		return "", false
+	}
+
+	if sources := SplitList(rule.ForwardedFrom); len(sources) > 0 {
+		if !slices.ContainsFunc(sources, func(s string) bool {
+			return slices.ContainsFunc(in.ForwardedFrom, func(f string) bool { return normalizeSource(s) == normalizeSource(f) })
+		}) {
+			return "", false
+		}
+	}
+
+	if rule.StartTime != "" && rule.EndTime != "" && !inWindow(rule.StartTime, rule.EndTime, in.Time) {
+		return "", false
+	}
+
+	return hashtag, true
+}
+
+func hasCondition(rule *models.CaptionRule) bool {
+	return rule.Hashtags != "" || rule.Keywords != "" || rule.Pattern != "" ||
+		rule.MessageTypes != "" || rule.ForwardedFrom != "" || (rule.StartTime != "" && rule.EndTime != "")
+}
+
+// SplitList separa uma lista por vírgulas, descartando itens vazios.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func normalizeSource(source string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(source), "@"))
+}
+
+// inWindow considera [start, end) no horário de t. Janelas com start > end
+// atravessam a meia-noite (ex: 22:00-06:00).
+func inWindow(start, end string, t time.Time) bool {
+	from, err1 := parseClock(start)
+	to, err2 := parseClock(end)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+var patternCache sync.Map
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// Validate confere os campos de uma regra antes de salvá-la.
+func Validate(rule *models.CaptionRule) error {
+	if !hasCondition(rule) {
+		return fmt.Errorf("a regra precisa de pelo menos uma condição")
+	}
+	if len(rule.Pattern) > maxPatternLength {
+		return fmt.Errorf("expressão regular muito longa (máximo %d caracteres)", maxPatternLength)
+	}
+	if rule.Pattern != "" {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("expressão regular inválida: %v", err)
+		}
+	}
+	for _, t := range SplitList(rule.MessageTypes) {
+		if !slices.Contains(captiontpl.MessageTypes, t) {
+			return fmt.Errorf("tipo de mensagem desconhecido %q (use %s)", t, strings.Join(captiontpl.MessageTypes, ", "))
+		}
+	}
+	if (rule.StartTime == "") != (rule.EndTime == "") {
+		return fmt.Errorf("informe início e fim do horário")
+	}
+	if rule.StartTime != "" {
+		if _, err := parseClock(rule.StartTime); err != nil {
+			return fmt.Errorf("horário inicial inválido (use HH:MM)")
+		}
+		if _, err := parseClock(rule.EndTime); err != nil {
+			return fmt.Errorf("horário final inválido (use HH:MM)")
+		}
+	}
+	return nil
+}
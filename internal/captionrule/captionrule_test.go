@@ -0,0 +1,69 @@
+package captionrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestEvaluateOrderAndConditions(t *testing.T) {
+	rules := []models.CaptionRule{
+		{RuleID: "late", Priority: 2, Enabled: true, Keywords: "promo"},
+		{RuleID: "disabled", Priority: 0, Enabled: false, Keywords: "promo"},
+		{RuleID: "tags", Priority: 1, Enabled: true, Hashtags: "#news, promo", MessageTypes: "photo,video"},
+	}
+
+	in := Input{Hashtags: []string{"Promo", "News"}, Text: "Grande PROMO hoje", MessageType: "photo"}
+	match := Evaluate(rules, in)
+	if match == nil || match.Rule.RuleID != "tags" || match.Hashtag != "News" {
+		t.Fatalf("expected rule tags with hashtag News, got %+v", match)
+	}
+
+	in.MessageType = "text"
+	match = Evaluate(rules, in)
+	if match == nil || match.Rule.RuleID != "late" || match.Hashtag != "" {
+		t.Fatalf("expected keyword rule to fire for text, got %+v", match)
+	}
+
+	if match := Evaluate(rules, Input{Text: "nada aqui", MessageType: "photo"}); match != nil {
+		t.Fatalf("expected no rule, got %s", match.Rule.RuleID)
+	}
+}
+
+func TestEvaluatePatternSourceAndTime(t *testing.T) {
+	rules := []models.CaptionRule{
+		{RuleID: "night", Enabled: true, StartTime: "22:00", EndTime: "06:00", ForwardedFrom: "@Fonte, -100200"},
+		{RuleID: "regex", Priority: 1, Enabled: true, Pattern: `(?i)^cap[ií]tulo \d+`},
+	}
+
+	at := func(h, m int) time.Time { return time.Date(2026, 1, 1, h, m, 0, 0, time.UTC) }
+
+	if match := Evaluate(rules, Input{ForwardedFrom: []string{"-100200", "fonte"}, Time: at(23, 30)}); match == nil || match.Rule.RuleID != "night" {
+		t.Fatalf("expected night rule, got %+v", match)
+	}
+	if match := Evaluate(rules, Input{ForwardedFrom: []string{"fonte"}, Time: at(12, 0)}); match != nil {
+		t.Fatalf("expected no rule at noon, got %s", match.Rule.RuleID)
+	}
+	if match := Evaluate(rules, Input{Text: "Capítulo 12 saiu", Time: at(12, 0)}); match == nil || match.Rule.RuleID != "regex" {
+		t.Fatalf("expected regex rule, got %+v", match)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []models.CaptionRule{
+		{},
+		{Pattern: "("},
+		{MessageTypes: "photo,banana"},
+		{StartTime: "10:00"},
+		{StartTime: "25:00", EndTime: "10:00"},
+	}
+	for _, rule := range invalid {
+		if err := Validate(&rule); err == nil {
+			t.Errorf("expected error for %+v", rule)
+		}
+	}
+	if err := Validate(&models.CaptionRule{Hashtags: "news", StartTime: "08:00", EndTime: "12:00"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
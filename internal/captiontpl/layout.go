@@ -0,0 +1,75 @@
+package captiontpl
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Posições da legenda do canal em relação ao texto original do post.
+const (
+	PositionAppend  = "append"
+	PositionPrepend = "prepend"
+	PositionWrap    = "wrap"
+	PositionReplace = "replace"
+)
+
+// DefaultSeparator é usado entre o texto original e a legenda quando o canal
+// não define um separador.
+const DefaultSeparator = "\n\n"
+
+func IsValidPosition(position string) bool {
+	switch position {
+	case PositionAppend, PositionPrepend, PositionWrap, PositionReplace:
+		return true
+	}
+	return false
+}
+
+// wrapDividerRegex é a linha "---" que divide cabeçalho e rodapé no modo wrap.
+var wrapDividerRegex = regexp.MustCompile(`(?m)^[ \t]*---[ \t]*$\n?`)
+
+// SplitWrap divide a legenda em cabeçalho e rodapé. Sem a linha "---", a
+// legenda inteira vira rodapé.
+func SplitWrap(caption string) (header, footer string) {
+	loc := wrapDividerRegex.FindStringIndex(caption)
+	if loc == nil {
+		return "", caption
+	}
+	return strings.TrimSpace(caption[:loc[0]]), strings.TrimSpace(caption[loc[1]:])
+}
+
+// Compose monta o texto final a partir do texto original e da legenda, ambos em
+// HTML. O separador é texto puro e é escapado; vazio usa DefaultSeparator.
+func Compose(original, caption, separator, position string) string {
+	if separator == "" {
+		separator = DefaultSeparator
+	} else {
+		separator = html.EscapeString(separator)
+	}
+
+	switch position {
+	case PositionReplace:
+		if caption != "" {
+			return caption
+		}
+		return original
+	case PositionPrepend:
+		return join(separator, caption, original)
+	case PositionWrap:
+		header, footer := SplitWrap(caption)
+		return join(separator, header, original, footer)
+	default:
+		return join(separator, original, caption)
+	}
+}
+
+func join(separator string, parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
@@ -0,0 +1,24 @@
+package captiontpl
+
+import "testing"
+
+func TestCompose(t *testing.T) {
+	cases := []struct {
+		name, original, caption, separator, position, want string
+	}{
+		{"append default", "post", "legenda", "", PositionAppend, "post\n\nlegenda"},
+		{"unknown position appends", "post", "legenda", "", "", "post\n\nlegenda"},
+		{"prepend", "post", "legenda", "\n", PositionPrepend, "legenda\npost"},
+		{"wrap", "post", "topo\n---\nrodapé", " | ", PositionWrap, "topo | post | rodapé"},
+		{"wrap without divider", "post", "rodapé", "", PositionWrap, "post\n\nrodapé"},
+		{"replace", "post", "legenda", "", PositionReplace, "legenda"},
+		{"replace without caption", "post", "", "", PositionReplace, "post"},
+		{"empty original", "", "legenda", "", PositionPrepend, "legenda"},
+		{"separator is escaped", "a", "b", " <> ", PositionAppend, "a &lt;&gt; b"},
+	}
+	for _, tc := range cases {
+		if got := Compose(tc.original, tc.caption, tc.separator, tc.position); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package captiontpl
+
+import (
+	"html"
+	"regexp"
+
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+var (
+	boldRegex   = regexp.MustCompile(`\*([^\*\n]+)\*`)
+	italicRegex = regexp.MustCompile(`_([^\_\n]+)_`)
+	codeRegex   = regexp.MustCompile("`([^`\\n]+)`")
+)
+
+// MarkdownToHTML converte o Markdown simples usado nas legendas (*negrito*,
+// _itálico_, `código` e [texto](url)) para o HTML aceito pelo Telegram.
+func MarkdownToHTML(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	// Escapar caracteres HTML ANTES de aplicar as regexes de Markdown
+	// Isso garante que '&' vire '&amp;', mas as tags que inserirmos depois (<b>, <i>) fiquem intactas.
+	res := html.EscapeString(text)
+	res, protectedLinks := utils.ProtectMarkdownLinks(res, "captiontpl.MarkdownToHTML")
+
+	res = boldRegex.ReplaceAllString(res, "<b>$1</b>")
+	res = italicRegex.ReplaceAllString(res, "<i>$1</i>")
+	res = codeRegex.ReplaceAllString(res, "<code>$1</code>")
+
+	res = utils.RestoreProtectedMarkdownLinks(res, protectedLinks)
+
+	return res
+}
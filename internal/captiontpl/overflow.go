@@ -0,0 +1,69 @@
+package captiontpl
+
+import "github.com/leirbagxis/FreddyBot/internal/tghtml"
+
+// Políticas para quando o texto original somado à legenda do canal passa do
+// limite do Telegram.
+const (
+	OverflowShorten = "shorten" // encurta o texto original (padrão)
+	OverflowDrop    = "drop"    // mantém o original e descarta a legenda
+	OverflowReply   = "reply"   // envia o excedente em uma resposta ao post
+)
+
+func IsValidOverflow(policy string) bool {
+	switch policy {
+	case OverflowShorten, OverflowDrop, OverflowReply:
+		return true
+	}
+	return false
+}
+
+// Fitted é o texto final montado por Fit.
+type Fitted struct {
+	Text string
+	// Overflow é o HTML que não coube no post (política reply).
+	Overflow string
+	// Action é a política aplicada, vazia quando o texto coube sem ajustes.
+	Action string
+	// Length é o tamanho, em unidades UTF-16, do texto antes do ajuste.
+	Length int
+}
+
+// Fit monta o texto com compose (que recebe o texto original em HTML e
+// devolve o post com a legenda) e, se ele passar de limit, aplica a política.
+// Quando a política não resolve (ex: a legenda sozinha já passa do limite),
+// o texto é devolvido como está para ser truncado no envio.
+func Fit(original string, compose func(original string) string, policy string, limit int) Fitted {
+	text := compose(original)
+	length := tghtml.TextLen(text)
+	if length <= limit {
+		return Fitted{Text: text}
+	}
+
+	switch policy {
+	case OverflowDrop:
+		return Fitted{Text: original, Action: OverflowDrop, Length: length}
+	case OverflowReply:
+		head, tail := tghtml.Split(text, limit)
+		return Fitted{Text: head, Overflow: tail, Action: OverflowReply, Length: length}
+	}
+
+	// A legenda ocupa o que sobra do limite; o original fica com o resto. O
+	// template pode repetir {original_caption}, então o corte é refeito com o
+	// excesso até caber.
+	originalLen := tghtml.TextLen(original)
+	budget := originalLen - (length - limit)
+	for range 3 {
+		if budget <= 0 {
+			break
+		}
+		shortened, _ := tghtml.Truncate(original, budget)
+		fitted := compose(shortened)
+		n := tghtml.TextLen(fitted)
+		if n <= limit {
+			return Fitted{Text: fitted, Action: OverflowShorten, Length: length}
+		}
+		budget -= n - limit
+	}
+	return Fitted{Text: text, Length: length}
+}
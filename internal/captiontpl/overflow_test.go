@@ -0,0 +1,38 @@
+package captiontpl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
+)
+
+func TestFit(t *testing.T) {
+	original := strings.Repeat("palavra ", 10) // 80 unidades
+	compose := func(o string) string { return Compose(o, "<b>legenda do canal</b>", "", PositionAppend) }
+
+	if got := Fit("curto", compose, OverflowShorten, 100); got.Action != "" || got.Text != "curto\n\n<b>legenda do canal</b>" {
+		t.Fatalf("unexpected fit: %+v", got)
+	}
+
+	got := Fit(original, compose, OverflowShorten, 60)
+	if got.Action != OverflowShorten || tghtml.TextLen(got.Text) > 60 || !strings.HasSuffix(got.Text, "…\n\n<b>legenda do canal</b>") {
+		t.Fatalf("unexpected shorten: %+v", got)
+	}
+
+	got = Fit(original, compose, OverflowDrop, 60)
+	if got.Action != OverflowDrop || got.Text != original {
+		t.Fatalf("unexpected drop: %+v", got)
+	}
+
+	got = Fit(original, compose, OverflowReply, 60)
+	if got.Action != OverflowReply || tghtml.TextLen(got.Text) > 60 || !strings.HasSuffix(got.Overflow, "<b>legenda do canal</b>") {
+		t.Fatalf("unexpected reply: %+v", got)
+	}
+
+	// A legenda sozinha não cabe: nada a encurtar no original.
+	replace := func(o string) string { return Compose(o, strings.Repeat("x", 80), "", PositionReplace) }
+	if got := Fit(original, replace, OverflowShorten, 60); got.Action != "" || got.Length != 80 {
+		t.Fatalf("unexpected fit for replace: %+v", got)
+	}
+}
@@ -0,0 +1,351 @@
+// Package captiontpl implementa a linguagem de template das legendas de canal
+// (legenda padrão e legendas customizadas).
+//
+// Sintaxe:
+//   - {variavel} é substituída pelo valor correspondente (ver Variables);
+//   - {if cond}...{else}...{end} inclui um trecho conforme a condição, que pode
+//     ser um ou mais tipos de mensagem separados por | ({if photo|video}) ou
+//     uma variável, verdadeira quando não está vazia ({if file_name}). Um ! no
+//     início nega a condição inteira ({if !text}).
+//
+// O restante do texto segue o Markdown simples das legendas (MarkdownToHTML).
+// Os valores das variáveis são escapados depois da conversão, então não podem
+// injetar HTML nem formatação.
+package captiontpl
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	VarChannelTitle    = "channel_title"
+	VarChannelLink     = "channel_link"
+	VarDate            = "date"
+	VarMessageType     = "message_type"
+	VarFileName        = "file_name"
+	VarDuration        = "duration"
+	VarOriginalCaption = "original_caption"
+	VarAuthor          = "author"
+)
+
+// DefaultSignatureFormat é o rodapé de assinatura usado quando o canal não
+// define um template próprio.
+const DefaultSignatureFormat = "✍️ {author}"
+
+// Variables lista as variáveis aceitas nos templates.
+var Variables = []string{
+	VarChannelTitle,
+	VarChannelLink,
+	VarDate,
+	VarMessageType,
+	VarFileName,
+	VarDuration,
+	VarOriginalCaption,
+	VarAuthor,
+}
+
+// MessageTypes lista os tipos de mensagem aceitos nas condições.
+var MessageTypes = []string{"text", "photo", "video", "animation", "audio", "document", "sticker"}
+
+var messageTypeLabels = map[string]string{
+	"text":      "texto",
+	"photo":     "foto",
+	"video":     "vídeo",
+	"animation": "GIF",
+	"audio":     "áudio",
+	"document":  "documento",
+	"sticker":   "sticker",
+}
+
+// MessageTypeLabel retorna o nome em português do tipo de mensagem.
+func MessageTypeLabel(messageType string) string {
+	if label, ok := messageTypeLabels[messageType]; ok {
+		return label
+	}
+	return messageType
+}
+
+// Data reúne os valores disponíveis para um post.
+type Data struct {
+	ChannelTitle string
+	ChannelLink  string
+	Date         time.Time
+	MessageType  string
+	FileName     string
+	// Duration em segundos (áudio, vídeo e GIF).
+	Duration int
+	// OriginalCaption já em HTML do Telegram; é inserida sem escape.
+	OriginalCaption string
+	// Author é a assinatura de quem publicou o post, já trocada pelo apelido
+	// configurado no canal.
+	Author string
+}
+
+func (d Data) value(name string) string {
+	switch name {
+	case VarChannelTitle:
+		return d.ChannelTitle
+	case VarChannelLink:
+		return d.ChannelLink
+	case VarDate:
+		if d.Date.IsZero() {
+			return ""
+		}
+		return d.Date.Format("02/01/2006")
+	case VarMessageType:
+		return MessageTypeLabel(d.MessageType)
+	case VarFileName:
+		return d.FileName
+	case VarDuration:
+		return formatDuration(d.Duration)
+	case VarOriginalCaption:
+		return d.OriginalCaption
+	case VarAuthor:
+		return d.Author
+	}
+	return ""
+}
+
+func formatDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	varNode
+	ifNode
+)
+
+type condition struct {
+	negate bool
+	terms  []string
+}
+
+type node struct {
+	kind nodeKind
+	// text é o trecho literal (textNode) ou o nome da variável (varNode).
+	text string
+	cond condition
+	then []node
+	els  []node
+}
+
+// Template é um template de legenda já analisado.
+type Template struct {
+	nodes   []node
+	unknown []string
+	uses    map[string]bool
+}
+
+var (
+	tagRegex     = regexp.MustCompile(`\{([^{}\n]*)\}`)
+	varNameRegex = regexp.MustCompile(`^\w+$`)
+)
+
+func isVariable(name string) bool {
+	for _, v := range Variables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isMessageType(name string) bool {
+	_, ok := messageTypeLabels[name]
+	return ok
+}
+
+// Parse analisa o template. Erros indicam blocos {if} mal formados; chaves
+// que não são tags ({texto qualquer}) são mantidas como texto.
+func Parse(tpl string) (*Template, error) {
+	t := &Template{uses: make(map[string]bool)}
+
+	type frame struct {
+		n       *node
+		inElse  bool
+		hasElse bool
+	}
+	root := &node{}
+	stack := []*frame{{n: root}}
+	appendNode := func(n node) {
+		top := stack[len(stack)-1]
+		if top.inElse {
+			top.n.els = append(top.n.els, n)
+		} else {
+			top.n.then = append(top.n.then, n)
+		}
+	}
+
+	last := 0
+	for _, loc := range tagRegex.FindAllStringSubmatchIndex(tpl, -1) {
+		if loc[0] > last {
+			appendNode(node{kind: textNode, text: tpl[last:loc[0]]})
+		}
+		last = loc[1]
+
+		raw := tpl[loc[0]:loc[1]]
+		tag := strings.TrimSpace(tpl[loc[2]:loc[3]])
+		switch {
+		case tag == "if" || strings.HasPrefix(tag, "if "):
+			cond, err := parseCondition(strings.TrimSpace(strings.TrimPrefix(tag, "if")))
+			if err != nil {
+				return nil, err
+			}
+			for _, term := range cond.terms {
+				if isVariable(term) {
+					t.uses[term] = true
+				}
+			}
+			stack = append(stack, &frame{n: &node{kind: ifNode, cond: cond}})
+		case tag == "else":
+			top := stack[len(stack)-1]
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("{else} sem {if} correspondente")
+			}
+			if top.hasElse {
+				return nil, fmt.Errorf("{else} repetido no mesmo {if}")
+			}
+			top.inElse, top.hasElse = true, true
+		case tag == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("{end} sem {if} correspondente")
+			}
+			closed := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			appendNode(*closed.n)
+		case isVariable(tag):
+			t.uses[tag] = true
+			appendNode(node{kind: varNode, text: tag})
+		default:
+			if varNameRegex.MatchString(tag) {
+				t.unknown = append(t.unknown, tag)
+			}
+			appendNode(node{kind: textNode, text: raw})
+		}
+	}
+	if last < len(tpl) {
+		appendNode(node{kind: textNode, text: tpl[last:]})
+	}
+
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("{if} sem {end} correspondente")
+	}
+	t.nodes = root.then
+	return t, nil
+}
+
+func parseCondition(expr string) (condition, error) {
+	var cond condition
+	if strings.HasPrefix(expr, "!") {
+		cond.negate = true
+		expr = strings.TrimSpace(expr[1:])
+	}
+	if expr == "" {
+		return cond, fmt.Errorf("condição vazia em {if}")
+	}
+	for _, term := range strings.Split(expr, "|") {
+		term = strings.TrimSpace(term)
+		if !isVariable(term) && !isMessageType(term) {
+			return cond, fmt.Errorf("condição desconhecida %q em {if}: use um tipo de mensagem (%s) ou uma variável", term, strings.Join(MessageTypes, ", "))
+		}
+		cond.terms = append(cond.terms, term)
+	}
+	return cond, nil
+}
+
+// Validate verifica a estrutura do template e rejeita variáveis desconhecidas.
+func Validate(tpl string) error {
+	t, err := Parse(tpl)
+	if err != nil {
+		return err
+	}
+	if len(t.unknown) > 0 {
+		return fmt.Errorf("variável desconhecida {%s}; disponíveis: {%s}", t.unknown[0], strings.Join(Variables, "}, {"))
+	}
+	return nil
+}
+
+// Uses informa se o template referencia a variável em algum ponto.
+func (t *Template) Uses(name string) bool {
+	return t.uses[name]
+}
+
+// Render gera o HTML final da legenda para os dados informados.
+func (t *Template) Render(data Data) string {
+	var b strings.Builder
+	var values []string
+	writeNodes(&b, t.nodes, data, &values)
+
+	// As variáveis entram como marcadores para não serem interpretadas como
+	// Markdown nem escapadas duas vezes; só depois recebem o valor final.
+	res := MarkdownToHTML(b.String())
+	for i, v := range values {
+		res = strings.ReplaceAll(res, placeholder(i), v)
+	}
+	return res
+}
+
+func writeNodes(b *strings.Builder, nodes []node, data Data, values *[]string) {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			b.WriteString(n.text)
+		case varNode:
+			v := data.value(n.text)
+			if n.text != VarOriginalCaption {
+				v = html.EscapeString(v)
+			}
+			b.WriteString(placeholder(len(*values)))
+			*values = append(*values, v)
+		case ifNode:
+			if n.cond.eval(data) {
+				writeNodes(b, n.then, data, values)
+			} else {
+				writeNodes(b, n.els, data, values)
+			}
+		}
+	}
+}
+
+func (c condition) eval(data Data) bool {
+	match := false
+	for _, term := range c.terms {
+		if isVariable(term) {
+			match = data.value(term) != ""
+		} else {
+			match = data.MessageType == term
+		}
+		if match {
+			break
+		}
+	}
+	return match != c.negate
+}
+
+func placeholder(i int) string {
+	return fmt.Sprintf("FBTPLVAR%dTOKEN", i)
+}
+
+// Render analisa e renderiza o template. Templates inválidos (salvos antes da
+// validação existir) são tratados como texto simples, como antes.
+func Render(tpl string, data Data) string {
+	t, err := Parse(tpl)
+	if err != nil {
+		return MarkdownToHTML(tpl)
+	}
+	return t.Render(data)
+}
@@ -0,0 +1,90 @@
+package captiontpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderVariablesAndConditionals(t *testing.T) {
+	tpl := "*{channel_title}* {if video|animation}⏱ {duration}{else}📄 {message_type}{end}\n[Entrar]({channel_link}) {date}"
+	data := Data{
+		ChannelTitle: "Canal <Teste>",
+		ChannelLink:  "https://t.me/canal",
+		Date:         time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC),
+		MessageType:  "video",
+		Duration:     125,
+	}
+
+	got := Render(tpl, data)
+	want := "<b>Canal &lt;Teste&gt;</b> ⏱ 2:05\n<a href=\"https://t.me/canal\">Entrar</a> 07/03/2026"
+	if got != want {
+		t.Fatalf("unexpected render:\n got: %q\nwant: %q", got, want)
+	}
+
+	data.MessageType = "photo"
+	if got := Render(tpl, data); !strings.Contains(got, "📄 foto") {
+		t.Fatalf("expected else branch for photo, got %q", got)
+	}
+}
+
+func TestRenderDoesNotFormatVariableValues(t *testing.T) {
+	data := Data{FileName: "meu_arquivo_final.*zip*", OriginalCaption: "<b>original</b>", MessageType: "document"}
+
+	got := Render("{original_caption}\n{file_name} {if !text}doc{end}", data)
+	want := "<b>original</b>\nmeu_arquivo_final.*zip* doc"
+	if got != want {
+		t.Fatalf("unexpected render:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestRenderAuthorFooter(t *testing.T) {
+	if got := Render(DefaultSignatureFormat, Data{Author: "Ana <mod>"}); got != "✍️ Ana &lt;mod&gt;" {
+		t.Fatalf("unexpected footer: %q", got)
+	}
+	if got := Render("{if author}_por {author}_{end}", Data{}); got != "" {
+		t.Fatalf("expected empty footer without author, got %q", got)
+	}
+}
+
+func TestParseKeepsUnknownBracesAsText(t *testing.T) {
+	if got := Render("{ olá } {foo}", Data{}); got != "{ olá } {foo}" {
+		t.Fatalf("unexpected render: %q", got)
+	}
+	if err := Validate("{foo}"); err == nil {
+		t.Fatal("expected unknown variable to be rejected")
+	}
+}
+
+func TestValidateStructure(t *testing.T) {
+	cases := map[string]bool{
+		"{if photo}a{end}":                    true,
+		"{if photo}a{else}b{end}":             true,
+		"{if file_name}{if audio}x{end}{end}": true,
+		"{if photo}a":                         false,
+		"a{end}":                              false,
+		"{else}":                              false,
+		"{if photo}a{else}b{else}c{end}":      false,
+		"{if banana}a{end}":                   false,
+		"{if}a{end}":                          false,
+	}
+	for tpl, ok := range cases {
+		err := Validate(tpl)
+		if ok && err != nil {
+			t.Errorf("%q: unexpected error %v", tpl, err)
+		}
+		if !ok && err == nil {
+			t.Errorf("%q: expected error", tpl)
+		}
+	}
+}
+
+func TestTemplateUsesOriginalCaption(t *testing.T) {
+	tpl, err := Parse("{if text}{original_caption}{end}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tpl.Uses(VarOriginalCaption) {
+		t.Fatal("expected template to use original_caption")
+	}
+}
@@ -3,53 +3,81 @@ package container
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/cache"
 	"github.com/leirbagxis/FreddyBot/internal/core/services"
 	"github.com/leirbagxis/FreddyBot/internal/database"
 	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/ratelimit"
+	"github.com/leirbagxis/FreddyBot/internal/translate"
+	"github.com/leirbagxis/FreddyBot/pkg/config"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 	"github.com/mymmrac/telego"
 	"gorm.io/gorm"
 )
 
 type BroadcastButton struct {
-	Text  string
-	Type  string
-	Value string
+	Text  string `json:"text"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type BroadcastJob struct {
-	ChatID   int64
-	Text     string
-	ImageUrl string
-	Buttons  []BroadcastButton
+	ChatID   int64             `json:"chat_id"`
+	Text     string            `json:"text"`
+	ImageUrl string            `json:"image_url,omitempty"`
+	Buttons  []BroadcastButton `json:"buttons,omitempty"`
 }
 
+const (
+	broadcastWorkerCount = 5
+	broadcastPollTimeout = 2 * time.Second
+	eventCleanupInterval = 24 * time.Hour
+	voteFlushInterval    = 2 * time.Second
+	voteFlushTimeout     = 10 * time.Second
+)
+
 type AppContainer struct {
 	DB        *gorm.DB
 	TelegoBot *telego.Bot
 
-	BroadcastQueue chan BroadcastJob
-
 	// ## SERVICES ## \\
-	UserService          *services.UserService
-	ChannelService       *services.ChannelService
-	ButtonService        *services.ButtonService
-	CaptionService       *services.CaptionService
-	PermissionsService   *services.PermissionsService
-	CustomCaptionService *services.CustomCaptionService
-	SeparatorService     *services.SeparatorService
-	VoteService          *services.VoteService
-	ServerService        *services.ServerService
-	ChannelEventService  *services.ChannelEventService
+	UserService            *services.UserService
+	ChannelService         *services.ChannelService
+	ButtonService          *services.ButtonService
+	CaptionService         *services.CaptionService
+	PermissionsService     *services.PermissionsService
+	CustomCaptionService   *services.CustomCaptionService
+	CaptionVariantService  *services.CaptionVariantService
+	LinkSettingsService    *services.LinkSettingsService
+	ContentFilterService   *services.ContentFilterService
+	AuthorSignatureService *services.AuthorSignatureService
+	SeparatorService       *services.SeparatorService
+	VoteService            *services.VoteService
+	ServerService          *services.ServerService
+	ChannelEventService    *services.ChannelEventService
+	ScheduledPostService   *services.ScheduledPostService
+	JobQueueService        *services.JobQueueService
+
+	// Translator traduz os posts dos canais com tradução automática. Nil quando
+	// TRANSLATE_API_URL não está configurada.
+	Translator translate.Translator
 
 	// ## CACHE ## \\
 	CacheService   *cache.Service
 	SessionManager *cache.SessionManager
+
+	// ## LIFECYCLE ## \\
+	// Leader executa as rotinas que rodam em uma única réplica.
+	Leader *cache.Leader
 }
 
+// NewAppContainer monta o container compartilhado pelo bot e pela API. Deve ser
+// criado uma única vez por processo; as rotinas em background só começam em Start.
 func NewAppContainer(db *gorm.DB, telegoClient *telego.Bot) *AppContainer {
 	cacheService := cache.NewService()
 
@@ -59,39 +87,90 @@ func NewAppContainer(db *gorm.DB, telegoClient *telego.Bot) *AppContainer {
 	buttonRepo := repositories.NewButtonRepository(db)
 	separatorRepo := repositories.NewSeparatorRepository(db)
 	voteRepo := repositories.NewVoteRepository(db)
+	reactionCountRepo := repositories.NewReactionCountRepository(db)
+	votePollRepo := repositories.NewVotePollRepository(db)
 	customCaptionRepo := repositories.NewCustomCaptionRepository(db)
+	captionVariantRepo := repositories.NewCaptionVariantRepository(db)
+	linkSettingsRepo := repositories.NewLinkSettingsRepository(db)
+	contentFilterRepo := repositories.NewContentFilterRepository(db)
+	authorSignatureRepo := repositories.NewAuthorSignatureRepository(db)
 	permissionsRepo := repositories.NewPermissionsRepository(db)
 	serverRepo := repositories.NewServerConfigRepository(db)
 	channelEventRepo := repositories.NewChannelEventRepository(db)
+	scheduledPostRepo := repositories.NewScheduledPostRepository(db)
+	queueJobRepo := repositories.NewQueueJobRepository(db)
 
 	container := &AppContainer{
 		DB:        db,
 		TelegoBot: telegoClient,
 
-		BroadcastQueue: make(chan BroadcastJob, 10000),
-
 		// Services
-		UserService:          services.NewUserService(userRepo),
-		ChannelService:       services.NewChannelService(channelRepo, userRepo, separatorRepo, cacheService, telegoClient),
-		ButtonService:        services.NewButtonService(buttonRepo, channelRepo, customCaptionRepo, cacheService),
-		CaptionService:       services.NewCaptionService(channelRepo, buttonRepo, cacheService),
-		PermissionsService:   services.NewPermissionsService(permissionsRepo, channelRepo, cacheService),
-		CustomCaptionService: services.NewCustomCaptionService(customCaptionRepo, channelRepo, cacheService),
-		SeparatorService:     services.NewSeparatorService(separatorRepo),
-		VoteService:          services.NewVoteService(voteRepo),
-		ServerService:        services.NewServerService(serverRepo),
-		ChannelEventService:  services.NewChannelEventService(channelEventRepo),
+		UserService:            services.NewUserService(userRepo),
+		ChannelService:         services.NewChannelService(channelRepo, userRepo, separatorRepo, cacheService, telegoClient),
+		ButtonService:          services.NewButtonService(buttonRepo, channelRepo, customCaptionRepo, cacheService),
+		CaptionService:         services.NewCaptionService(channelRepo, buttonRepo, cacheService),
+		PermissionsService:     services.NewPermissionsService(permissionsRepo, channelRepo, cacheService),
+		CustomCaptionService:   services.NewCustomCaptionService(customCaptionRepo, channelRepo, cacheService),
+		CaptionVariantService:  services.NewCaptionVariantService(captionVariantRepo, cacheService),
+		LinkSettingsService:    services.NewLinkSettingsService(linkSettingsRepo, cacheService),
+		ContentFilterService:   services.NewContentFilterService(contentFilterRepo, cacheService),
+		AuthorSignatureService: services.NewAuthorSignatureService(authorSignatureRepo, cacheService),
+		SeparatorService:       services.NewSeparatorService(separatorRepo, cacheService),
+		VoteService:            services.NewVoteService(voteRepo, reactionCountRepo, votePollRepo, cacheService),
+		ServerService:          services.NewServerService(serverRepo),
+		ChannelEventService:    services.NewChannelEventService(channelEventRepo),
+		ScheduledPostService:   services.NewScheduledPostService(scheduledPostRepo, channelRepo, cacheService),
+		JobQueueService:        services.NewJobQueueService(queueJobRepo),
 
 		CacheService:   cacheService,
 		SessionManager: cache.NewSessionManager(cacheService),
+
+		Leader: cache.NewLeader("freddybot"),
+	}
+
+	if config.TranslateAPIURL != "" {
+		container.Translator = translate.WithCache(translate.NewHTTPTranslator(config.TranslateAPIURL, config.TranslateAPIKey), cacheService)
 	}
 
-	container.syncFixedPostBuilderSession(context.Background())
-	go container.ChannelEventService.CleanupOld(context.Background(), services.ChannelEventRetentionDays)
-	container.startBroadcastWorkers(5)
+	container.Leader.OnElected(container.runEventCleanup)
+	container.Leader.OnElected(container.runBroadcastWorkers)
+	container.Leader.OnElected(container.runVoteFlusher)
 	return container
 }
 
+// Start executa as rotinas de inicialização, passa a escutar as invalidações de
+// cache das outras instâncias e entra na disputa pela liderança, que executa a
+// limpeza de eventos, os workers de broadcast e a gravação dos votos. As
+// rotinas param quando ctx é cancelado.
+func (c *AppContainer) Start(ctx context.Context) {
+	go c.CacheService.ListenInvalidations(ctx)
+	c.syncFixedPostBuilderSession(ctx)
+	go c.Leader.Run(ctx)
+}
+
+// Shutdown aguarda as rotinas da liderança terminarem o envio em andamento (até
+// o ctx expirar) e fecha o Redis e o banco. Deve ser o último passo do
+// encerramento; broadcasts ainda na fila continuam no Redis para o próximo líder.
+func (c *AppContainer) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	select {
+	case <-c.Leader.Done():
+	case <-ctx.Done():
+		logger.Warn("APP", "⚠️ Tempo esgotado aguardando as rotinas da liderança")
+	}
+
+	if err := cache.CloseRedis(); err != nil {
+		errs = append(errs, err)
+	}
+	if sqlDB, err := c.DB.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *AppContainer) syncFixedPostBuilderSession(ctx context.Context) {
 	config, err := c.ServerService.GetConfig(ctx)
 	if err != nil {
@@ -128,66 +207,159 @@ func (c *AppContainer) syncFixedPostBuilderSession(ctx context.Context) {
 	}
 }
 
-func (c *AppContainer) startBroadcastWorkers(workerCount int) {
-	for i := 0; i < workerCount; i++ {
-		go c.broadcastWorker()
+// runEventCleanup remove os eventos de canal antigos ao assumir a liderança e depois uma vez por dia.
+func (c *AppContainer) runEventCleanup(ctx context.Context) {
+	ticker := time.NewTicker(eventCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		c.ChannelEventService.CleanupOld(ctx, services.ChannelEventRetentionDays)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
 }
 
-func (c *AppContainer) broadcastWorker() {
-	for job := range c.BroadcastQueue {
-		var keyboard [][]telego.InlineKeyboardButton
-		var replyMarkup *telego.InlineKeyboardMarkup
+// runVoteFlusher grava no banco, a cada poucos segundos, os votos aceitos no
+// Redis. Ao perder a liderança faz uma última rodada para não deixar votos
+// pendentes no encerramento.
+func (c *AppContainer) runVoteFlusher(ctx context.Context) {
+	ticker := time.NewTicker(voteFlushInterval)
+	defer ticker.Stop()
 
-		if len(job.Buttons) > 0 {
-			for _, btn := range job.Buttons {
-				button := telego.InlineKeyboardButton{
-					Text: btn.Text,
-				}
+	flush := func(ctx context.Context) {
+		if n, err := c.VoteService.FlushVotes(ctx); err != nil {
+			logger.Error("APP", "Erro ao gravar votos pendentes: %v", err)
+		} else if n > 0 {
+			logger.Debug("APP", "🗳️ %d votante(s) gravados no banco", n)
+		}
+	}
 
-				if btn.Type == "url" {
-					button.URL = btn.Value
-				} else if btn.Type == "callback" {
-					button.CallbackData = btn.Value
-				}
+	for {
+		select {
+		case <-ctx.Done():
+			finalCtx, cancel := context.WithTimeout(context.Background(), voteFlushTimeout)
+			flush(finalCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
 
-				keyboard = append(keyboard, []telego.InlineKeyboardButton{button})
-			}
-			replyMarkup = &telego.InlineKeyboardMarkup{
-				InlineKeyboard: keyboard,
-			}
+// EnqueueBroadcast coloca os envios na fila compartilhada do Redis.
+func (c *AppContainer) EnqueueBroadcast(ctx context.Context, jobs ...BroadcastJob) error {
+	payloads := make([][]byte, 0, len(jobs))
+	for _, job := range jobs {
+		data, err := json.Marshal(job)
+		if err != nil {
+			return err
 		}
+		payloads = append(payloads, data)
+	}
+	return c.CacheService.PushBroadcastJobs(ctx, payloads...)
+}
+
+// runBroadcastWorkers consome a fila de broadcast enquanto a instância for líder.
+func (c *AppContainer) runBroadcastWorkers(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < broadcastWorkerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			c.broadcastWorker(ctx)
+		}()
+	}
+	workers.Wait()
+}
+
+func (c *AppContainer) broadcastWorker(ctx context.Context) {
+	// Broadcasts cedem espaço no limiter para a edição de posts e respostas a usuários.
+	// O envio em andamento não é cancelado junto com a liderança.
+	sendCtx := ratelimit.WithPriority(context.Background(), ratelimit.PriorityLow)
 
-		var err error
-		if job.ImageUrl != "" {
-			params := &telego.SendPhotoParams{
-				ChatID:    telego.ChatID{ID: job.ChatID},
-				Photo:     telego.InputFile{URL: job.ImageUrl},
-				Caption:   job.Text,
-				ParseMode: telego.ModeHTML,
+	for ctx.Err() == nil {
+		data, err := c.CacheService.PopBroadcastJob(ctx, broadcastPollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
 			}
-			if replyMarkup != nil {
-				params.ReplyMarkup = replyMarkup
+			logger.Error("APP", "Erro ao ler fila de broadcast: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
 			}
-			_, err = c.TelegoBot.SendPhoto(context.Background(), params)
-		} else {
-			params := &telego.SendMessageParams{
-				ChatID:    telego.ChatID{ID: job.ChatID},
-				Text:      job.Text,
-				ParseMode: telego.ModeHTML,
+			continue
+		}
+		if data == nil {
+			continue
+		}
+
+		var job BroadcastJob
+		if err := json.Unmarshal(data, &job); err != nil {
+			logger.Error("APP", "Broadcast inválido descartado: %v", err)
+			continue
+		}
+		c.sendBroadcast(sendCtx, job)
+	}
+}
+
+func (c *AppContainer) sendBroadcast(ctx context.Context, job BroadcastJob) {
+	var keyboard [][]telego.InlineKeyboardButton
+	var replyMarkup *telego.InlineKeyboardMarkup
+
+	if len(job.Buttons) > 0 {
+		for _, btn := range job.Buttons {
+			button := telego.InlineKeyboardButton{
+				Text: btn.Text,
 			}
-			if replyMarkup != nil {
-				params.ReplyMarkup = replyMarkup
+
+			if btn.Type == "url" {
+				button.URL = btn.Value
+			} else if btn.Type == "callback" {
+				button.CallbackData = btn.Value
 			}
-			_, err = c.TelegoBot.SendMessage(context.Background(), params)
+
+			keyboard = append(keyboard, []telego.InlineKeyboardButton{button})
+		}
+		replyMarkup = &telego.InlineKeyboardMarkup{
+			InlineKeyboard: keyboard,
 		}
+	}
 
-		if err != nil {
-			logger.Error("APP", "Erro ao enviar para %d: %v", job.ChatID, err)
-			continue
+	var err error
+	kind := "text"
+	if job.ImageUrl != "" {
+		kind = "photo"
+		params := &telego.SendPhotoParams{
+			ChatID:    telego.ChatID{ID: job.ChatID},
+			Photo:     telego.InputFile{URL: job.ImageUrl},
+			Caption:   job.Text,
+			ParseMode: telego.ModeHTML,
+		}
+		if replyMarkup != nil {
+			params.ReplyMarkup = replyMarkup
+		}
+		_, err = c.TelegoBot.SendPhoto(ctx, params)
+	} else {
+		params := &telego.SendMessageParams{
+			ChatID:    telego.ChatID{ID: job.ChatID},
+			Text:      job.Text,
+			ParseMode: telego.ModeHTML,
+		}
+		if replyMarkup != nil {
+			params.ReplyMarkup = replyMarkup
 		}
+		_, err = c.TelegoBot.SendMessage(ctx, params)
+	}
 
-		// 🔥 Controle de rate limit global
-		time.Sleep(35 * time.Millisecond)
+	if err != nil {
+		logger.Error("APP", "Erro ao enviar para %d: %v", job.ChatID, err)
+		metrics.BroadcastJobs.WithLabelValues(kind, "failed").Inc()
+		return
 	}
+	metrics.BroadcastJobs.WithLabelValues(kind, "sent").Inc()
 }
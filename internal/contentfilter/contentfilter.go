@@ -0,0 +1,318 @@
+// Package contentfilter aplica as regras de conteúdo do canal aos posts:
+// palavras proibidas, expressões regulares, domínios bloqueados e tipos de
+// mensagem proibidos.
+package contentfilter
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+// Ações aplicadas quando um post viola as regras.
+const (
+	// ActionStrip remove os trechos proibidos do texto e segue com o post.
+	ActionStrip = "strip"
+	// ActionBlock não edita o post: legenda e botões do canal não são aplicados.
+	ActionBlock = "block"
+	// ActionDelete apaga o post e avisa o dono do canal.
+	ActionDelete = "delete"
+)
+
+// Limites das listas salvas por canal.
+const (
+	MaxWords          = 200
+	MaxWordLength     = 64
+	MaxPatterns       = 20
+	MaxPatternLength  = 512
+	maxStripPasses    = 5
+	wordBoundaryClass = `[^\p{L}\p{N}_]`
+)
+
+// IsValidAction indica se a ação é uma das aceitas.
+func IsValidAction(action string) bool {
+	return action == ActionStrip || action == ActionBlock || action == ActionDelete
+}
+
+// Rules são as regras de conteúdo de um canal já interpretadas.
+type Rules struct {
+	Action   string
+	words    *regexp.Regexp
+	patterns []*regexp.Regexp
+	domains  []string
+	mentions *regexp.Regexp
+	types    []string
+}
+
+// Violation descreve o que o post tem de proibido.
+type Violation struct {
+	Words       []string `json:"words,omitempty"`
+	Patterns    []string `json:"patterns,omitempty"`
+	Domains     []string `json:"domains,omitempty"`
+	MessageType string   `json:"messageType,omitempty"`
+}
+
+// Reasons lista os tipos de violação encontrados (words, patterns, domains e
+// type), na ordem em que são avaliados.
+func (v *Violation) Reasons() []string {
+	if v == nil {
+		return nil
+	}
+	var reasons []string
+	if len(v.Words) > 0 {
+		reasons = append(reasons, "words")
+	}
+	if len(v.Patterns) > 0 {
+		reasons = append(reasons, "patterns")
+	}
+	if len(v.Domains) > 0 {
+		reasons = append(reasons, "domains")
+	}
+	if v.MessageType != "" {
+		reasons = append(reasons, "type")
+	}
+	return reasons
+}
+
+// FromSettings interpreta as configurações do canal. Retorna nil quando não
+// há configuração, ela está desativada ou não tem nenhuma regra; nil é um
+// *Rules válido que não barra nada. Expressões inválidas são ignoradas.
+func FromSettings(settings *models.ContentFilter) *Rules {
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+
+	r := &Rules{
+		Action:  settings.Action,
+		domains: linkrewrite.SplitDomains(settings.BlockedDomains),
+		types:   SplitList(settings.BlockedTypes),
+	}
+	if !IsValidAction(r.Action) {
+		r.Action = ActionStrip
+	}
+
+	if words := SplitLines(settings.BannedWords); len(words) > 0 {
+		quoted := make([]string, len(words))
+		for i, w := range words {
+			quoted[i] = regexp.QuoteMeta(w)
+		}
+		r.words = regexp.MustCompile(`(?i)(^|` + wordBoundaryClass + `)(` + strings.Join(quoted, "|") + `)($|` + wordBoundaryClass + `)`)
+	}
+	for _, p := range SplitLines(settings.Patterns) {
+		if re, err := regexp.Compile(p); err == nil {
+			r.patterns = append(r.patterns, re)
+		}
+	}
+	if len(r.domains) > 0 {
+		quoted := make([]string, len(r.domains))
+		for i, d := range r.domains {
+			quoted[i] = regexp.QuoteMeta(d)
+		}
+		// Menções ao domínio no texto, com ou sem esquema e caminho. Um ponto
+		// logo depois só encerra a menção se não continuar o host (spam.com.br).
+		r.mentions = regexp.MustCompile(`(?i)(^|[^a-z0-9._@/-])((?:https?://)?(?:[a-z0-9-]+\.)*(?:` + strings.Join(quoted, "|") +
+			`)(?:[:/?#][^\s<>"]*)?)($|[^a-z0-9_.-]|\.(?:$|[^a-z0-9_-]))`)
+	}
+
+	if r.words == nil && len(r.patterns) == 0 && len(r.domains) == 0 && len(r.types) == 0 {
+		return nil
+	}
+	return r
+}
+
+var hrefRegex = regexp.MustCompile(`(?i)<a\s+href\s*=\s*"([^"]*)"`)
+
+// Check avalia o texto do post (HTML do Telegram) e o tipo da mensagem.
+// Retorna nil quando o post está liberado.
+func (r *Rules) Check(text, messageType string) *Violation {
+	if r == nil {
+		return nil
+	}
+
+	v := &Violation{}
+	if slices.Contains(r.types, messageType) {
+		v.MessageType = messageType
+	}
+
+	plain := html.UnescapeString(utils.RemoveHTMLTags(text))
+	if r.words != nil {
+		for _, m := range r.words.FindAllStringSubmatch(plain, -1) {
+			if w := strings.ToLower(m[2]); !slices.Contains(v.Words, w) {
+				v.Words = append(v.Words, w)
+			}
+		}
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(plain) {
+			v.Patterns = append(v.Patterns, re.String())
+		}
+	}
+	if r.mentions != nil {
+		var hosts []string
+		for _, m := range hrefRegex.FindAllStringSubmatch(text, -1) {
+			hosts = append(hosts, hostOf(html.UnescapeString(m[1])))
+		}
+		for _, m := range r.mentions.FindAllStringSubmatch(plain, -1) {
+			hosts = append(hosts, hostOf(m[2]))
+		}
+		for _, host := range hosts {
+			if host == "" || !linkrewrite.MatchDomain(host, r.domains) {
+				continue
+			}
+			if d := linkrewrite.NormalizeDomain(host); !slices.Contains(v.Domains, d) {
+				v.Domains = append(v.Domains, d)
+			}
+		}
+	}
+
+	if len(v.Reasons()) == 0 {
+		return nil
+	}
+	return v
+}
+
+func hostOf(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(utils.NormalizeTelegramURL(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+var (
+	tagRegex    = regexp.MustCompile(`<[^>]*>`)
+	spacesRegex = regexp.MustCompile(`[ \t]{2,}`)
+)
+
+// Strip remove do texto (HTML do Telegram) as palavras proibidas, os trechos
+// que casam com as expressões e os links e menções a domínios bloqueados; links
+// em tags <a> perdem a tag e mantêm o texto. Expressões só alcançam trechos sem
+// formatação no meio. Retorna também quantos trechos foram removidos.
+func (r *Rules) Strip(text string) (string, int) {
+	if r == nil || text == "" {
+		return text, 0
+	}
+
+	text, removed := linkrewrite.Deny(r.domains).HTML(text)
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range tagRegex.FindAllStringIndex(text, -1) {
+		segment, n := r.stripSegment(text[last:loc[0]])
+		b.WriteString(segment)
+		b.WriteString(text[loc[0]:loc[1]])
+		removed += n
+		last = loc[1]
+	}
+	segment, n := r.stripSegment(text[last:])
+	b.WriteString(segment)
+	removed += n
+
+	return b.String(), removed
+}
+
+func (r *Rules) stripSegment(segment string) (string, int) {
+	if segment == "" {
+		return segment, 0
+	}
+	plain := html.UnescapeString(segment)
+
+	removed := 0
+	for _, re := range []*regexp.Regexp{r.words, r.mentions} {
+		if re == nil {
+			continue
+		}
+		// As bordas fazem parte do match: repetir pega ocorrências vizinhas.
+		for pass := 0; pass < maxStripPasses; pass++ {
+			n := len(re.FindAllStringIndex(plain, -1))
+			if n == 0 {
+				break
+			}
+			plain = re.ReplaceAllString(plain, "$1$3")
+			removed += n
+		}
+	}
+	for _, re := range r.patterns {
+		n := len(re.FindAllStringIndex(plain, -1))
+		if n > 0 {
+			plain = re.ReplaceAllString(plain, "")
+			removed += n
+		}
+	}
+
+	if removed == 0 {
+		return segment, 0
+	}
+	return html.EscapeString(spacesRegex.ReplaceAllString(plain, " ")), removed
+}
+
+// SplitLines separa uma lista por quebras de linha, descartando itens vazios.
+func SplitLines(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, "\n") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SplitList separa uma lista por vírgulas, descartando itens vazios.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ValidateWord confere uma palavra ou expressão proibida.
+func ValidateWord(word string) error {
+	if strings.ContainsAny(word, "\n\r") {
+		return fmt.Errorf("palavra proibida não pode ter quebra de linha")
+	}
+	if utf8.RuneCountInString(word) > MaxWordLength {
+		return fmt.Errorf("palavra proibida muito longa (máximo %d caracteres)", MaxWordLength)
+	}
+	return nil
+}
+
+// ValidatePattern confere uma expressão regular proibida.
+func ValidatePattern(pattern string) error {
+	if strings.ContainsAny(pattern, "\n\r") {
+		return fmt.Errorf("expressão regular não pode ter quebra de linha")
+	}
+	if len(pattern) > MaxPatternLength {
+		return fmt.Errorf("expressão regular muito longa (máximo %d caracteres)", MaxPatternLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("expressão regular inválida: %v", err)
+	}
+	if re.MatchString("") {
+		return fmt.Errorf("expressão regular %q casa com texto vazio", pattern)
+	}
+	return nil
+}
+
+// ValidateType confere um tipo de mensagem proibido.
+func ValidateType(messageType string) error {
+	if !slices.Contains(captiontpl.MessageTypes, messageType) {
+		return fmt.Errorf("tipo de mensagem desconhecido %q (use %s)", messageType, strings.Join(captiontpl.MessageTypes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package contentfilter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func testRules(action string) *Rules {
+	return FromSettings(&models.ContentFilter{
+		Enabled:        true,
+		Action:         action,
+		BannedWords:    "golpe\nPix Grátis",
+		Patterns:       `\d{3}\.\d{3}\.\d{3}-\d{2}`,
+		BlockedDomains: "spam.com",
+		BlockedTypes:   "sticker",
+	})
+}
+
+func TestCheck(t *testing.T) {
+	r := testRules(ActionBlock)
+
+	if v := r.Check("Promoção do dia, sem <b>golpes</b>", "text"); v != nil {
+		t.Fatalf("expected no violation, got %+v", v)
+	}
+
+	v := r.Check(`Cuidado: GOLPE! <a href="https://www.spam.com/x">veja</a> e pix grátis`, "photo")
+	if v == nil || !slices.Equal(v.Words, []string{"golpe", "pix grátis"}) || !slices.Equal(v.Domains, []string{"spam.com"}) {
+		t.Fatalf("unexpected violation %+v", v)
+	}
+
+	v = r.Check("CPF 123.456.789-00, fale em promo.spam.com.", "sticker")
+	if v == nil || !slices.Equal(v.Reasons(), []string{"patterns", "domains", "type"}) {
+		t.Fatalf("unexpected reasons %v", v.Reasons())
+	}
+
+	if v := r.Check("veja spam.com.br", "text"); v != nil {
+		t.Fatalf("expected other domain to pass, got %+v", v)
+	}
+
+	if FromSettings(&models.ContentFilter{Enabled: false, BannedWords: "golpe"}) != nil {
+		t.Fatal("expected disabled filter to be nil")
+	}
+	var nilRules *Rules
+	if nilRules.Check("golpe", "text") != nil {
+		t.Fatal("expected nil rules to pass everything")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	r := testRules(ActionStrip)
+
+	cases := []struct {
+		in, want string
+	}{
+		{"golpe golpe no canal", " no canal"},
+		{"<b>Oferta</b> golpe aqui", "<b>Oferta</b> aqui"},
+		{`leia <a href="https://spam.com/a">aqui</a>`, "leia aqui"},
+		{"acesse spam.com/promo hoje", "acesse hoje"},
+		{"CPF 123.456.789-00 ok", "CPF ok"},
+		{"texto limpo", "texto limpo"},
+	}
+	for _, tc := range cases {
+		got, _ := r.Strip(tc.in)
+		if got != tc.want {
+			t.Errorf("Strip(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+		if r.Check(got, "text") != nil {
+			t.Errorf("Strip(%q) left a violation: %q", tc.in, got)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	for _, pattern := range []string{"(", ".*", "a\nb"} {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("expected error for %q", pattern)
+		}
+	}
+	if err := ValidatePattern(`(?i)compre\s+já`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
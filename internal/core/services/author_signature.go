@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+const (
+	maxAuthorSignatures      = 50
+	maxAuthorSignatureLength = 128
+	maxAuthorLabelLength     = 64
+	maxSignatureFormatLength = 256
+)
+
+// AuthorSignatureService gerencia o rodapé de assinatura do canal e o
+// mapeamento das assinaturas dos admins para apelidos ou emojis.
+type AuthorSignatureService struct {
+	signatureRepo *repositories.AuthorSignatureRepository
+	cache         *cache.Service
+}
+
+func NewAuthorSignatureService(signatureRepo *repositories.AuthorSignatureRepository, cache *cache.Service) *AuthorSignatureService {
+	return &AuthorSignatureService{
+		signatureRepo: signatureRepo,
+		cache:         cache,
+	}
+}
+
+func (s *AuthorSignatureService) ListSignatures(ctx context.Context, channelID int64) ([]models.AuthorSignature, error) {
+	signatures, err := s.signatureRepo.ListSignatures(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return signatures, nil
+}
+
+func (s *AuthorSignatureService) CreateSignature(ctx context.Context, channelID int64, body types.AuthorSignatureRequest) (*models.AuthorSignature, error) {
+	existing, err := s.signatureRepo.ListSignatures(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if len(existing) >= maxAuthorSignatures {
+		return nil, errors.BadRequest("Limite de 50 assinaturas por canal atingido")
+	}
+
+	signature := &models.AuthorSignature{
+		SignatureID:    uuid.NewString(),
+		OwnerChannelID: channelID,
+	}
+	if err := applyAuthorSignature(signature, body, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.signatureRepo.CreateSignature(ctx, signature); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Assinatura de autor criada: %s (Canal: %d)", signature.SignatureID, channelID)
+	return signature, nil
+}
+
+func (s *AuthorSignatureService) UpdateSignature(ctx context.Context, channelID int64, signatureID string, body types.AuthorSignatureRequest) (*models.AuthorSignature, error) {
+	signature, err := s.signatureRepo.GetSignatureByID(ctx, channelID, signatureID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	existing, err := s.signatureRepo.ListSignatures(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if err := applyAuthorSignature(signature, body, existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.signatureRepo.SaveSignature(ctx, signature); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Assinatura de autor atualizada: %s (Canal: %d)", signatureID, channelID)
+	return signature, nil
+}
+
+func (s *AuthorSignatureService) DeleteSignature(ctx context.Context, channelID int64, signatureID string) error {
+	rowsAffected, err := s.signatureRepo.DeleteSignature(ctx, channelID, signatureID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return nil
+}
+
+// UpdateFooter liga ou desliga o rodapé de assinatura. O template precisa usar
+// {author}; vazio volta ao padrão.
+func (s *AuthorSignatureService) UpdateFooter(ctx context.Context, channelID int64, body types.SignatureFooterUpdateRequest) (int64, error) {
+	format := strings.TrimSpace(body.Format)
+	if format == captiontpl.DefaultSignatureFormat {
+		format = ""
+	}
+	if format != "" {
+		if utf8.RuneCountInString(format) > maxSignatureFormatLength {
+			return 0, errors.BadRequest("Rodapé muito longo (máximo 256 caracteres)")
+		}
+		if err := validateCaptionTemplate(format); err != nil {
+			return 0, err
+		}
+		tpl, _ := captiontpl.Parse(format)
+		if !tpl.Uses(captiontpl.VarAuthor) {
+			return 0, errors.BadRequest("O rodapé precisa usar a variável {author}")
+		}
+		if tpl.Uses(captiontpl.VarOriginalCaption) {
+			return 0, errors.BadRequest("O rodapé não pode usar a variável {original_caption}")
+		}
+	}
+
+	rowsAffected, err := s.signatureRepo.UpdateFooter(ctx, channelID, body.Enabled, format)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return rowsAffected, nil
+}
+
+func applyAuthorSignature(signature *models.AuthorSignature, body types.AuthorSignatureRequest, existing []models.AuthorSignature) error {
+	name := strings.TrimSpace(body.Signature)
+	label := strings.TrimSpace(body.Label)
+	if name == "" || label == "" {
+		return errors.BadRequest("Informe a assinatura e o apelido")
+	}
+	if utf8.RuneCountInString(name) > maxAuthorSignatureLength {
+		return errors.BadRequest("Assinatura muito longa (máximo 128 caracteres)")
+	}
+	if utf8.RuneCountInString(label) > maxAuthorLabelLength {
+		return errors.BadRequest("Apelido muito longo (máximo 64 caracteres)")
+	}
+	for _, other := range existing {
+		if other.SignatureID != signature.SignatureID && strings.EqualFold(other.Signature, name) {
+			return errors.BadRequest("Já existe um apelido para a assinatura " + name)
+		}
+	}
+
+	signature.Signature = name
+	signature.Label = label
+	return nil
+}
@@ -325,6 +325,10 @@ func (s *ButtonService) getGridPositionGeneric(buttons []models.CustomCaptionBut
 }
 
 func (s *ButtonService) validateButtonData(data types.ButtonCreateRequest) error {
+	return validateButtonRequest(data)
+}
+
+func validateButtonRequest(data types.ButtonCreateRequest) error {
 	if strings.TrimSpace(data.NameButton) == "" {
 		return errors.BadRequest("O nome do botão é obrigatório")
 	}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/captionpool"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+const maxCaptionVariants = 20
+
+// CaptionVariantService gerencia o pool de legendas (variantes A/B) do canal.
+type CaptionVariantService struct {
+	variantRepo *repositories.CaptionVariantRepository
+	cache       *cache.Service
+}
+
+func NewCaptionVariantService(variantRepo *repositories.CaptionVariantRepository, cache *cache.Service) *CaptionVariantService {
+	return &CaptionVariantService{
+		variantRepo: variantRepo,
+		cache:       cache,
+	}
+}
+
+func (s *CaptionVariantService) ListVariants(ctx context.Context, channelID int64) ([]models.CaptionVariant, error) {
+	variants, err := s.variantRepo.ListVariants(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return variants, nil
+}
+
+func (s *CaptionVariantService) CreateVariant(ctx context.Context, channelID int64, body types.CaptionVariantRequest) (*models.CaptionVariant, error) {
+	existing, err := s.variantRepo.ListVariants(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if len(existing) >= maxCaptionVariants {
+		return nil, errors.BadRequest("Limite de 20 variantes por canal atingido")
+	}
+
+	variant := &models.CaptionVariant{
+		VariantID:      uuid.NewString(),
+		OwnerChannelID: channelID,
+		Enabled:        true,
+	}
+	if err := applyCaptionVariant(variant, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.variantRepo.CreateVariant(ctx, variant); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Variante de legenda criada: %s (Canal: %d)", variant.VariantID, channelID)
+	return variant, nil
+}
+
+func (s *CaptionVariantService) UpdateVariant(ctx context.Context, channelID int64, variantID string, body types.CaptionVariantRequest) (*models.CaptionVariant, error) {
+	variant, err := s.variantRepo.GetVariantByID(ctx, channelID, variantID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	if err := applyCaptionVariant(variant, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.variantRepo.SaveVariant(ctx, variant); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Variante de legenda atualizada: %s (Canal: %d)", variantID, channelID)
+	return variant, nil
+}
+
+func (s *CaptionVariantService) DeleteVariant(ctx context.Context, channelID int64, variantID string) error {
+	rowsAffected, err := s.variantRepo.DeleteVariant(ctx, channelID, variantID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return nil
+}
+
+func (s *CaptionVariantService) UpdateRotationMode(ctx context.Context, channelID int64, mode string) (int64, error) {
+	mode = strings.TrimSpace(mode)
+	if !captionpool.IsValidMode(mode) {
+		return 0, errors.BadRequest("Modo de rotação inválido (use round_robin, weighted, weekday ou vazio para desativar)")
+	}
+
+	rowsAffected, err := s.variantRepo.UpdateRotationMode(ctx, channelID, mode)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return rowsAffected, nil
+}
+
+// RecordUsage contabiliza o uso de uma variante. Falhas só são registradas no
+// log para não interromper o envio do post.
+func (s *CaptionVariantService) RecordUsage(ctx context.Context, variantID string) {
+	if err := s.variantRepo.IncrementUsage(ctx, variantID, time.Now()); err != nil {
+		logger.Error("DB", "Erro ao contabilizar uso da variante %s: %v", variantID, err)
+	}
+}
+
+// Stats resume quantas vezes cada variante foi usada desde o último reset.
+func (s *CaptionVariantService) Stats(ctx context.Context, channelID int64) (*types.CaptionPoolStatsResponse, error) {
+	mode, err := s.variantRepo.GetRotationMode(ctx, channelID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	variants, err := s.variantRepo.ListVariants(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	stats := &types.CaptionPoolStatsResponse{Mode: mode, Variants: make([]types.CaptionVariantUsage, 0, len(variants))}
+	for _, v := range variants {
+		stats.Total += v.UsageCount
+	}
+	for _, v := range variants {
+		usage := types.CaptionVariantUsage{
+			VariantID:  v.VariantID,
+			Name:       v.Name,
+			Enabled:    v.Enabled,
+			UsageCount: v.UsageCount,
+			LastUsedAt: v.LastUsedAt,
+		}
+		if stats.Total > 0 {
+			usage.Share = math.Round(float64(v.UsageCount)/float64(stats.Total)*1000) / 10
+		}
+		stats.Variants = append(stats.Variants, usage)
+	}
+	return stats, nil
+}
+
+func (s *CaptionVariantService) ResetStats(ctx context.Context, channelID int64) (int64, error) {
+	rowsAffected, err := s.variantRepo.ResetUsage(ctx, channelID)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+	return rowsAffected, nil
+}
+
+// applyCaptionVariant copia o corpo da requisição para a variante, validando a
+// legenda, o peso, os dias e os botões.
+func applyCaptionVariant(variant *models.CaptionVariant, body types.CaptionVariantRequest) error {
+	if err := validateCaptionTemplate(body.Caption); err != nil {
+		return err
+	}
+
+	variant.Name = strings.TrimSpace(body.Name)
+	variant.Caption = body.Caption
+	variant.Weight = body.Weight
+	if variant.Weight == 0 {
+		variant.Weight = 1
+	}
+	variant.Weekdays = strings.Join(strings.Fields(strings.ReplaceAll(body.Weekdays, ",", " ")), ",")
+	if body.Enabled != nil {
+		variant.Enabled = *body.Enabled
+	}
+	if err := captionpool.Validate(variant); err != nil {
+		return errors.BadRequest("Variante inválida: " + err.Error())
+	}
+
+	variant.Buttons = make([]models.CaptionVariantButton, 0, len(body.Buttons))
+	for _, b := range body.Buttons {
+		b.ButtonURL = utils.NormalizeTelegramURL(b.ButtonURL)
+		if err := validateButtonRequest(b.ButtonCreateRequest); err != nil {
+			return err
+		}
+		variant.Buttons = append(variant.Buttons, models.CaptionVariantButton{
+			ButtonID:       uuid.NewString(),
+			NameButton:     b.NameButton,
+			ButtonURL:      b.ButtonURL,
+			PositionX:      b.PositionX,
+			PositionY:      b.PositionY,
+			OwnerVariantID: variant.VariantID,
+		})
+	}
+	return nil
+}
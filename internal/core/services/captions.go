@@ -2,16 +2,70 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/leirbagxis/FreddyBot/internal/api/types"
 	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
 	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
+	"github.com/leirbagxis/FreddyBot/internal/translate"
+	"github.com/leirbagxis/FreddyBot/pkg/config"
 	"github.com/leirbagxis/FreddyBot/pkg/errors"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
+const maxCaptionSeparatorLength = 64
+
+// validateCaptionTemplate confere o template da legenda e o HTML que ele gera
+// para cada tipo de mensagem, para que erros de formatação apareçam ao salvar
+// e não na hora de editar o post.
+func validateCaptionTemplate(caption string) error {
+	if len(caption) > 4096 {
+		return errors.BadRequest("Caption muito longa (máximo 4096 caracteres)")
+	}
+	if err := captiontpl.Validate(caption); err != nil {
+		return errors.BadRequest("Template inválido: " + err.Error())
+	}
+
+	for _, messageType := range captiontpl.MessageTypes {
+		rendered := captiontpl.Render(caption, captiontpl.Data{
+			ChannelTitle: "Canal",
+			ChannelLink:  "https://t.me/canal",
+			Date:         time.Now(),
+			MessageType:  messageType,
+			FileName:     "arquivo.mp4",
+			Duration:     90,
+			Author:       "Admin",
+		})
+		if err := tghtml.Validate(rendered); err != nil {
+			return errors.BadRequest("Formatação inválida: " + err.Error())
+		}
+		if tghtml.TextLen(rendered) > tghtml.MaxTextLength {
+			return errors.BadRequest("Caption muito longa (máximo 4096 caracteres)")
+		}
+	}
+	return nil
+}
+
+// validateCaptionLayout confere posição e separador. Valores vazios são aceitos
+// quando allowInherit é true (legendas customizadas herdam do canal).
+func validateCaptionLayout(position, separator string, allowInherit bool) error {
+	if !(allowInherit && position == "") && !captiontpl.IsValidPosition(position) {
+		return errors.BadRequest("Posição da legenda inválida (use append, prepend, wrap ou replace)")
+	}
+	if utf8.RuneCountInString(separator) > maxCaptionSeparatorLength {
+		return errors.BadRequest("Separador muito longo (máximo 64 caracteres)")
+	}
+	return nil
+}
+
 type CaptionService struct {
 	channelRepo *repositories.ChannelRepository
 	buttonRepo  *repositories.ButtonRepository
@@ -36,8 +90,8 @@ func (s *CaptionService) isEmoji(s_str string) bool {
 }
 
 func (s *CaptionService) UpdateDefaultCaption(ctx context.Context, channelID int64, captionData types.CaptionDefaultUpdateRequest) (int64, error) {
-	if len(captionData.Caption) > 4096 {
-		return 0, errors.BadRequest("Caption muito longa (máximo 4096 caracteres)")
+	if err := validateCaptionTemplate(captionData.Caption); err != nil {
+		return 0, err
 	}
 
 	rowsAffected, err := s.channelRepo.UpdateDefaultCaption(ctx, channelID, captionData.Caption)
@@ -51,6 +105,153 @@ func (s *CaptionService) UpdateDefaultCaption(ctx context.Context, channelID int
 	return rowsAffected, nil
 }
 
+// PreviewCaption renderiza o template como ficaria em um post do canal, usando
+// os dados de exemplo enviados (tipo de mensagem, legenda original, arquivo).
+func (s *CaptionService) PreviewCaption(ctx context.Context, channelID int64, req types.CaptionPreviewRequest) (*types.CaptionPreviewResponse, error) {
+	if err := captiontpl.Validate(req.Caption); err != nil {
+		return nil, errors.BadRequest("Template inválido: " + err.Error())
+	}
+	tpl, _ := captiontpl.Parse(req.Caption)
+
+	messageType := strings.TrimSpace(req.MessageType)
+	if messageType == "" {
+		messageType = "photo"
+	}
+	if !slices.Contains(captiontpl.MessageTypes, messageType) {
+		return nil, errors.BadRequest("Tipo de mensagem inválido")
+	}
+
+	channel, err := s.channelRepo.GetChannelByIDLight(ctx, channelID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	original := captiontpl.MarkdownToHTML(req.OriginalCaption)
+	compose := func(original string) string {
+		rendered := tpl.Render(captiontpl.Data{
+			ChannelTitle:    channel.Title,
+			ChannelLink:     channel.InviteURL,
+			Date:            time.Now().In(config.Location),
+			MessageType:     messageType,
+			FileName:        req.FileName,
+			Duration:        req.Duration,
+			OriginalCaption: original,
+		})
+		// Com {original_caption} o próprio template já posiciona o texto original.
+		if !tpl.Uses(captiontpl.VarOriginalCaption) {
+			rendered = captiontpl.Compose(original, rendered, channel.CaptionSeparator, channel.CaptionPosition)
+		}
+		return rendered
+	}
+
+	// O preview mostra o texto como seria enviado: ajustado pela política de
+	// legendas longas do canal, com HTML corrigido e no limite do tipo de mensagem.
+	limit := tghtml.MaxCaptionLength
+	if messageType == "text" {
+		limit = tghtml.MaxTextLength
+	}
+	policy := channel.CaptionOverflow
+	if !captiontpl.IsValidOverflow(policy) {
+		policy = captiontpl.OverflowShorten
+	}
+	fitted := captiontpl.Fit(original, compose, policy, limit)
+
+	rendered, warnings := tghtml.Sanitize(fitted.Text)
+	switch fitted.Action {
+	case captiontpl.OverflowShorten:
+		warnings = append(warnings, fmt.Sprintf("texto com %d caracteres: o original será encurtado para caber em %d", fitted.Length, limit))
+	case captiontpl.OverflowDrop:
+		warnings = append(warnings, fmt.Sprintf("texto com %d caracteres: a legenda será descartada", fitted.Length))
+	case captiontpl.OverflowReply:
+		warnings = append(warnings, fmt.Sprintf("texto com %d caracteres: o excedente será enviado como resposta", fitted.Length))
+	}
+	if truncated, ok := tghtml.Truncate(rendered, limit); ok {
+		rendered = truncated
+		warnings = append(warnings, fmt.Sprintf("texto excede %d caracteres e será truncado", limit))
+	}
+
+	return &types.CaptionPreviewResponse{
+		HTML:        rendered,
+		Variables:   captiontpl.Variables,
+		MessageType: messageType,
+		Overflow:    fitted.Overflow,
+		Warnings:    warnings,
+	}, nil
+}
+
+// UpdateCaptionLayout define onde a legenda padrão entra no post e o separador
+// usado entre ela e o texto original.
+func (s *CaptionService) UpdateCaptionLayout(ctx context.Context, channelID int64, layout types.CaptionLayoutUpdateRequest) (int64, error) {
+	position := strings.TrimSpace(layout.Position)
+	if err := validateCaptionLayout(position, layout.Separator, false); err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := s.channelRepo.UpdateCaptionLayout(ctx, channelID, position, layout.Separator)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return 0, errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Posição da legenda atualizada para %s (Canal: %d)", position, channelID)
+
+	return rowsAffected, nil
+}
+
+// UpdateCaptionOverflow define o que fazer quando o texto original somado à
+// legenda passa do limite do Telegram (encurtar, descartar ou responder).
+func (s *CaptionService) UpdateCaptionOverflow(ctx context.Context, channelID int64, policy string) (int64, error) {
+	policy = strings.TrimSpace(policy)
+	if !captiontpl.IsValidOverflow(policy) {
+		return 0, errors.BadRequest("Política inválida (use shorten, drop ou reply)")
+	}
+
+	rowsAffected, err := s.channelRepo.UpdateCaptionOverflow(ctx, channelID, policy)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return 0, errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Política de legendas longas atualizada para %s (Canal: %d)", policy, channelID)
+
+	return rowsAffected, nil
+}
+
+// UpdateTranslation define o idioma da tradução automática dos posts e se ela
+// é acrescentada ao texto original ou o substitui.
+func (s *CaptionService) UpdateTranslation(ctx context.Context, channelID int64, req types.TranslationUpdateRequest) (int64, error) {
+	language := strings.TrimSpace(req.Language)
+	mode := strings.TrimSpace(req.Mode)
+	if mode == "" {
+		mode = translate.ModeAppend
+	}
+	if language != "" && !translate.IsValidLanguage(language) {
+		return 0, errors.BadRequest("Idioma inválido (use o código ISO, ex: en, es, pt)")
+	}
+	if !translate.IsValidMode(mode) {
+		return 0, errors.BadRequest("Modo de tradução inválido (use append ou replace)")
+	}
+
+	rowsAffected, err := s.channelRepo.UpdateTranslation(ctx, channelID, language, mode)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return 0, errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Tradução automática atualizada para %q/%s (Canal: %d)", language, mode, channelID)
+
+	return rowsAffected, nil
+}
+
 func (s *CaptionService) UpdateNewPackCaption(ctx context.Context, channelID int64, captionData types.NewPackCaptionUpdateRequest) (int64, error) {
 	caption := captionData.Text()
 	if strings.TrimSpace(caption) == "" {
@@ -93,6 +294,16 @@ func (s *CaptionService) UpdateReactions(ctx context.Context, channelID int64, r
 		}
 	}
 
+	channel, err := s.channelRepo.GetChannelByIDLight(ctx, channelID)
+	if err != nil {
+		return 0, errors.ErrNotFound
+	}
+	if reactions.ModeOf(channel.ReactionMode) == reactions.ModeNative {
+		if err := validateNativeReactions(reactionsData.Reactions); err != nil {
+			return 0, err
+		}
+	}
+
 	rowsAffected, err := s.channelRepo.UpdateReactions(ctx, channelID, reactionsData.Reactions)
 	if err != nil {
 		return 0, errors.Internal(err)
@@ -103,6 +314,45 @@ func (s *CaptionService) UpdateReactions(ctx context.Context, channelID int64, r
 	return rowsAffected, nil
 }
 
+// UpdateReactionMode escolhe entre os botões de voto do bot e as reações
+// nativas do Telegram. No modo nativo todas as reações do canal precisam ser
+// aceitas pelo Telegram.
+func (s *CaptionService) UpdateReactionMode(ctx context.Context, channelID int64, mode string) (int64, error) {
+	mode = strings.TrimSpace(mode)
+	if !reactions.IsValidMode(mode) {
+		return 0, errors.BadRequest("Modo inválido (use buttons ou native)")
+	}
+
+	channel, err := s.channelRepo.GetChannelByIDLight(ctx, channelID)
+	if err != nil {
+		return 0, errors.ErrNotFound
+	}
+	if mode == reactions.ModeNative {
+		if err := validateNativeReactions(channel.Reactions); err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := s.channelRepo.UpdateReactionMode(ctx, channelID, mode)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Modo de reações atualizado para %s (Canal: %d)", mode, channelID)
+
+	return rowsAffected, nil
+}
+
+func validateNativeReactions(list string) error {
+	for _, emoji := range reactions.Split(list) {
+		if !reactions.IsNative(emoji) {
+			return errors.BadRequest(fmt.Sprintf("%s não é uma reação nativa do Telegram", emoji))
+		}
+	}
+	return nil
+}
+
 func (s *CaptionService) UpdateReactionPosition(ctx context.Context, channelID int64, posData types.ReactionPositionUpdateRequest) (int64, error) {
 	occupied, err := s.buttonRepo.IsRowOccupiedByButtons(ctx, channelID, posData.ReactionPosition)
 	if err != nil {
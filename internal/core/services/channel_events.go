@@ -16,10 +16,11 @@ const (
 	ChannelEventSourceChannelPost = "channel_post"
 	ChannelEventSourcePostBuilder = "post_builder"
 
-	ChannelEventStatusSuccess = "success"
-	ChannelEventStatusError   = "error"
-	ChannelEventStatusSkipped = "skipped"
-	ChannelEventStatusInfo    = "info"
+	ChannelEventStatusSuccess  = "success"
+	ChannelEventStatusError    = "error"
+	ChannelEventStatusSkipped  = "skipped"
+	ChannelEventStatusInfo     = "info"
+	ChannelEventStatusFiltered = "filtered"
 
 	ChannelEventRetentionDays  = 90
 	maxChannelEventMetadataLen = 12000
@@ -36,6 +37,7 @@ type ChannelEventRecordInput struct {
 	MessageType       string
 	TelegramMessageID int
 	SessionID         string
+	CorrelationID     string // preenchido a partir do ctx quando vazio
 	Error             error
 	ErrorMessage      string
 	Metadata          map[string]any
@@ -68,6 +70,9 @@ func (s *ChannelEventService) Record(ctx context.Context, input ChannelEventReco
 	if input.Status == "" {
 		input.Status = ChannelEventStatusInfo
 	}
+	if input.CorrelationID == "" && ctx != nil {
+		input.CorrelationID = logger.CorrelationID(ctx)
+	}
 
 	metadata := ""
 	if len(input.Metadata) > 0 {
@@ -101,6 +106,7 @@ func (s *ChannelEventService) Record(ctx context.Context, input ChannelEventReco
 		MessageType:       input.MessageType,
 		TelegramMessageID: input.TelegramMessageID,
 		SessionID:         input.SessionID,
+		CorrelationID:     input.CorrelationID,
 		ErrorMessage:      errorMessage,
 		Metadata:          metadata,
 	}
@@ -224,3 +224,15 @@ func (s *ChannelService) UpdateDynamicLinks(ctx context.Context, channelID int64
 	s.cache.InvalidateChannel(ctx, channelID)
 	return nil
 }
+
+func (s *ChannelService) UpdateProcessEdits(ctx context.Context, channelID int64, enabled bool) error {
+	rowsAffected, err := s.channelRepo.UpdateProcessEdits(ctx, channelID, enabled)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	s.cache.InvalidateChannel(ctx, channelID)
+	return nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/contentfilter"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+type ContentFilterService struct {
+	filterRepo *repositories.ContentFilterRepository
+	cache      *cache.Service
+}
+
+func NewContentFilterService(filterRepo *repositories.ContentFilterRepository, cache *cache.Service) *ContentFilterService {
+	return &ContentFilterService{filterRepo: filterRepo, cache: cache}
+}
+
+// GetContentFilter retorna nil quando o canal ainda não configurou o filtro.
+func (s *ContentFilterService) GetContentFilter(ctx context.Context, channelID int64) (*models.ContentFilter, error) {
+	filter, err := s.filterRepo.GetByOwnerChannelID(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return filter, nil
+}
+
+// UpdateContentFilter substitui as regras de conteúdo do canal: palavras e
+// expressões proibidas, domínios bloqueados, tipos de mensagem proibidos e a
+// ação aplicada aos posts que as violam.
+func (s *ContentFilterService) UpdateContentFilter(ctx context.Context, channelID int64, body types.ContentFilterRequest) (*models.ContentFilter, error) {
+	action := strings.TrimSpace(body.Action)
+	if action == "" {
+		action = contentfilter.ActionStrip
+	}
+	if !contentfilter.IsValidAction(action) {
+		return nil, errors.BadRequest("Ação inválida: use strip, block ou delete")
+	}
+
+	if len(body.BannedWords) > contentfilter.MaxWords {
+		return nil, errors.BadRequest("Limite de 200 palavras proibidas atingido")
+	}
+	words := make([]string, 0, len(body.BannedWords))
+	for _, word := range body.BannedWords {
+		word = strings.TrimSpace(word)
+		if word == "" || slices.ContainsFunc(words, func(w string) bool { return strings.EqualFold(w, word) }) {
+			continue
+		}
+		if err := contentfilter.ValidateWord(word); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+		words = append(words, word)
+	}
+
+	if len(body.Patterns) > contentfilter.MaxPatterns {
+		return nil, errors.BadRequest("Limite de 20 expressões regulares atingido")
+	}
+	patterns := make([]string, 0, len(body.Patterns))
+	for _, pattern := range body.Patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if err := contentfilter.ValidatePattern(pattern); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	domains, err := normalizeDomainList(body.BlockedDomains)
+	if err != nil {
+		return nil, err
+	}
+
+	messageTypes := make([]string, 0, len(body.BlockedTypes))
+	for _, messageType := range body.BlockedTypes {
+		messageType = strings.ToLower(strings.TrimSpace(messageType))
+		if messageType == "" || slices.Contains(messageTypes, messageType) {
+			continue
+		}
+		if err := contentfilter.ValidateType(messageType); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+		messageTypes = append(messageTypes, messageType)
+	}
+
+	filter, err := s.filterRepo.GetByOwnerChannelID(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if filter == nil {
+		filter = &models.ContentFilter{OwnerChannelID: channelID}
+	}
+
+	filter.Enabled = body.Enabled
+	filter.Action = action
+	filter.BannedWords = strings.Join(words, "\n")
+	filter.Patterns = strings.Join(patterns, "\n")
+	filter.BlockedDomains = strings.Join(domains, ",")
+	filter.BlockedTypes = strings.Join(messageTypes, ",")
+
+	if err := s.filterRepo.Save(ctx, filter); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Filtro de conteúdo atualizado (Canal: %d, ativo: %v, ação: %s)", channelID, filter.Enabled, filter.Action)
+
+	return filter, nil
+}
@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/leirbagxis/FreddyBot/internal/api/types"
 	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/captionrule"
 	"github.com/leirbagxis/FreddyBot/internal/database/models"
 	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
 	"github.com/leirbagxis/FreddyBot/pkg/errors"
@@ -32,12 +34,20 @@ func (s *CustomCaptionService) CreateCustomCaption(ctx context.Context, channelI
 	if err != nil {
 		return nil, errors.ErrNotFound
 	}
+	if err := validateCaptionTemplate(body.Caption); err != nil {
+		return nil, err
+	}
+	if err := validateCaptionLayout(body.Position, body.Separator, true); err != nil {
+		return nil, err
+	}
 
 	newCaption := &models.CustomCaption{
 		CaptionID:      uuid.NewString(),
 		Code:           body.Code,
 		Caption:        body.Caption,
 		LinkPreview:    body.LinkPreview,
+		Position:       body.Position,
+		Separator:      body.Separator,
 		OwnerChannelID: channelID,
 	}
 
@@ -50,9 +60,18 @@ func (s *CustomCaptionService) CreateCustomCaption(ctx context.Context, channelI
 }
 
 func (s *CustomCaptionService) UpdateCustomCaption(ctx context.Context, channelID int64, captionID string, body types.CreateCustomCaptionRequest) (int64, error) {
+	if err := validateCaptionTemplate(body.Caption); err != nil {
+		return 0, err
+	}
+	if err := validateCaptionLayout(body.Position, body.Separator, true); err != nil {
+		return 0, err
+	}
+
 	updates := map[string]interface{}{
 		"caption":      body.Caption,
 		"link_preview": body.LinkPreview,
+		"position":     body.Position,
+		"separator":    body.Separator,
 		"updated_at":   time.Now(),
 	}
 
@@ -81,6 +100,102 @@ func (s *CustomCaptionService) DeleteCustomCaption(ctx context.Context, channelI
 		return errors.ErrNotFound
 	}
 
+	if err := s.customCaptionRepo.DeleteCaptionRulesByCaptionID(ctx, channelID, captionID); err != nil {
+		logger.Error("API", "Erro ao remover regras da legenda %s: %v", captionID, err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return nil
+}
+
+// ### REGRAS DE LEGENDA ### \\
+
+func (s *CustomCaptionService) ListCaptionRules(ctx context.Context, channelID int64) ([]models.CaptionRule, error) {
+	rules, err := s.customCaptionRepo.ListCaptionRules(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return rules, nil
+}
+
+func (s *CustomCaptionService) CreateCaptionRule(ctx context.Context, channelID int64, body types.CaptionRuleRequest) (*models.CaptionRule, error) {
+	rule := &models.CaptionRule{
+		RuleID:         uuid.NewString(),
+		OwnerChannelID: channelID,
+		Enabled:        true,
+	}
+	if err := s.applyCaptionRule(ctx, channelID, rule, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.customCaptionRepo.CreateCaptionRule(ctx, rule); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Regra de legenda criada: %s (Canal: %d)", rule.RuleID, channelID)
+	return rule, nil
+}
+
+func (s *CustomCaptionService) UpdateCaptionRule(ctx context.Context, channelID int64, ruleID string, body types.CaptionRuleRequest) (*models.CaptionRule, error) {
+	rule, err := s.customCaptionRepo.GetCaptionRuleByID(ctx, channelID, ruleID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	if err := s.applyCaptionRule(ctx, channelID, rule, body); err != nil {
+		return nil, err
+	}
+
+	if err := s.customCaptionRepo.SaveCaptionRule(ctx, rule); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Regra de legenda atualizada: %s (Canal: %d)", ruleID, channelID)
+	return rule, nil
+}
+
+func (s *CustomCaptionService) DeleteCaptionRule(ctx context.Context, channelID int64, ruleID string) error {
+	rowsAffected, err := s.customCaptionRepo.DeleteCaptionRule(ctx, channelID, ruleID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
 	s.cache.InvalidateChannel(ctx, channelID)
 	return nil
 }
+
+// applyCaptionRule copia o corpo da requisição para a regra, normalizando as
+// listas, e valida as condições e a legenda de destino.
+func (s *CustomCaptionService) applyCaptionRule(ctx context.Context, channelID int64, rule *models.CaptionRule, body types.CaptionRuleRequest) error {
+	if _, err := s.customCaptionRepo.GetCustomCaptionByID(ctx, channelID, body.CaptionID); err != nil {
+		return errors.BadRequest("Legenda customizada não encontrada neste canal")
+	}
+
+	hashtags := captionrule.SplitList(body.Hashtags)
+	for i, tag := range hashtags {
+		hashtags[i] = strings.TrimPrefix(tag, "#")
+	}
+
+	rule.CaptionID = body.CaptionID
+	rule.Name = strings.TrimSpace(body.Name)
+	rule.Priority = body.Priority
+	if body.Enabled != nil {
+		rule.Enabled = *body.Enabled
+	}
+	rule.Hashtags = strings.Join(hashtags, ",")
+	rule.Keywords = strings.Join(captionrule.SplitList(body.Keywords), ",")
+	rule.Pattern = strings.TrimSpace(body.Pattern)
+	rule.MessageTypes = strings.ToLower(strings.Join(captionrule.SplitList(body.MessageTypes), ","))
+	rule.ForwardedFrom = strings.Join(captionrule.SplitList(body.ForwardedFrom), ",")
+	rule.StartTime = strings.TrimSpace(body.StartTime)
+	rule.EndTime = strings.TrimSpace(body.EndTime)
+
+	if err := captionrule.Validate(rule); err != nil {
+		return errors.BadRequest("Regra inválida: " + err.Error())
+	}
+	return nil
+}
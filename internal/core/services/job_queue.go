@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+const (
+	QueueChannelPost = "channel_post"
+
+	QueueJobStatusPending    = repositories.QueueJobStatusPending
+	QueueJobStatusProcessing = repositories.QueueJobStatusProcessing
+	QueueJobStatusDead       = repositories.QueueJobStatusDead
+
+	QueueJobMaxAttempts = 5
+	// QueueJobHandoffDelay é quanto um job recém-enfileirado espera antes de
+	// poder ser assumido pelo poller, caso o worker em memória não o processe.
+	QueueJobHandoffDelay = 30 * time.Second
+	// QueueJobProcessingTimeout é quanto um job pode ficar em processamento antes
+	// de ser considerado abandonado (réplica que caiu) e voltar para a fila.
+	QueueJobProcessingTimeout = 5 * time.Minute
+	// QueueJobHeartbeatInterval é de quanto em quanto tempo um job em execução
+	// renova o updated_at, bem abaixo do QueueJobProcessingTimeout.
+	QueueJobHeartbeatInterval = time.Minute
+
+	queueJobBaseBackoff = 5 * time.Second
+	queueJobMaxBackoff  = 5 * time.Minute
+	maxQueueJobErrorLen = 4000
+)
+
+type QueueJobInput struct {
+	Queue     string
+	Kind      string
+	ChannelID int64
+	MessageID int
+	Payload   string
+}
+
+type QueueJobListFilters = repositories.QueueJobFilters
+
+type QueueJobListResult struct {
+	Jobs   []models.QueueJob `json:"jobs"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type JobQueueService struct {
+	repo *repositories.QueueJobRepository
+}
+
+func NewJobQueueService(repo *repositories.QueueJobRepository) *JobQueueService {
+	return &JobQueueService{repo: repo}
+}
+
+func (s *JobQueueService) Enqueue(ctx context.Context, input QueueJobInput) (*models.QueueJob, error) {
+	job := &models.QueueJob{
+		Queue:         input.Queue,
+		Kind:          input.Kind,
+		ChannelID:     input.ChannelID,
+		MessageID:     input.MessageID,
+		Payload:       input.Payload,
+		Status:        QueueJobStatusPending,
+		NextAttemptAt: time.Now().Add(QueueJobHandoffDelay),
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ErrQueueJobLost indica que o job foi liberado como abandonado e assumido por
+// outra execução enquanto esta rodava; o resultado desta execução é descartado.
+var ErrQueueJobLost = errors.New(http.StatusConflict, "Job assumido por outra execução")
+
+// Claim assume o job e devolve a linha atual do banco, com o token da execução.
+// Retorna nil quando o job não está mais pendente.
+func (s *JobQueueService) Claim(ctx context.Context, id string) (*models.QueueJob, error) {
+	return s.repo.Claim(ctx, id)
+}
+
+// Ack remove o job concluído. Retorna ErrQueueJobLost quando o job não pertence
+// mais a esta execução.
+func (s *JobQueueService) Ack(ctx context.Context, job *models.QueueJob) error {
+	rowsAffected, err := s.repo.Ack(ctx, job.ID, job.ClaimToken)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrQueueJobLost
+	}
+	return nil
+}
+
+// Heartbeat mantém o job em processamento fora do ReleaseProcessing enquanto
+// ele roda. Retorna ErrQueueJobLost quando o job não pertence mais a esta execução.
+func (s *JobQueueService) Heartbeat(ctx context.Context, job *models.QueueJob) error {
+	rowsAffected, err := s.repo.Touch(ctx, job.ID, job.ClaimToken, time.Now())
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrQueueJobLost
+	}
+	return nil
+}
+
+// CompleteStep registra no job um passo que não pode ser repetido em uma nova
+// tentativa. Retorna ErrQueueJobLost quando o job não pertence mais a esta execução.
+func (s *JobQueueService) CompleteStep(ctx context.Context, job *models.QueueJob, step string) error {
+	if QueueJobCompletedSteps(job)[step] {
+		return nil
+	}
+	steps := step
+	if job.CompletedSteps != "" {
+		steps = job.CompletedSteps + "," + step
+	}
+	rowsAffected, err := s.repo.SaveCompletedSteps(ctx, job.ID, job.ClaimToken, steps)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrQueueJobLost
+	}
+	job.CompletedSteps = steps
+	return nil
+}
+
+// QueueJobCompletedSteps retorna os passos já concluídos do job.
+func QueueJobCompletedSteps(job *models.QueueJob) map[string]bool {
+	steps := make(map[string]bool)
+	for _, step := range strings.Split(job.CompletedSteps, ",") {
+		if step != "" {
+			steps[step] = true
+		}
+	}
+	return steps
+}
+
+// Fail agenda uma nova tentativa com backoff exponencial ou move o job para a
+// dead-letter quando as tentativas acabam ou o erro é permanente.
+// Retorna true quando o job foi para a dead-letter e ErrQueueJobLost quando o
+// job não pertence mais a esta execução.
+func (s *JobQueueService) Fail(ctx context.Context, job *models.QueueJob, cause error, permanent bool) (bool, error) {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+	if len(message) > maxQueueJobErrorLen {
+		message = message[:maxQueueJobErrorLen]
+	}
+
+	dead := permanent || job.Attempts >= QueueJobMaxAttempts
+	var rowsAffected int64
+	var err error
+	if dead {
+		rowsAffected, err = s.repo.MarkDead(ctx, job.ID, job.ClaimToken, message)
+	} else {
+		rowsAffected, err = s.repo.ScheduleRetry(ctx, job.ID, job.ClaimToken, time.Now().Add(QueueJobBackoff(job.Attempts)), message)
+	}
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, ErrQueueJobLost
+	}
+	return dead, nil
+}
+
+func (s *JobQueueService) ListReady(ctx context.Context, queue string, limit int) ([]models.QueueJob, error) {
+	return s.repo.ListReady(ctx, queue, time.Now(), limit)
+}
+
+// ReleaseProcessing devolve para a fila os jobs abandonados em processamento.
+// Jobs recentes são mantidos, pois podem estar rodando em outra réplica.
+func (s *JobQueueService) ReleaseProcessing(ctx context.Context, queue string) (int64, error) {
+	now := time.Now()
+	return s.repo.ReleaseProcessing(ctx, queue, now.Add(-QueueJobProcessingTimeout), now)
+}
+
+func (s *JobQueueService) ListDead(ctx context.Context, filters QueueJobListFilters) (*QueueJobListResult, error) {
+	jobs, total, err := s.repo.ListDead(ctx, filters)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	limit := filters.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset := filters.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	return &QueueJobListResult{Jobs: jobs, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+func (s *JobQueueService) RetryDead(ctx context.Context, id string) error {
+	rowsAffected, err := s.repo.Requeue(ctx, id, time.Now())
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func (s *JobQueueService) DeleteDead(ctx context.Context, id string) error {
+	rowsAffected, err := s.repo.DeleteDead(ctx, id)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// QueueJobBackoff retorna o atraso da próxima tentativa: 5s, 10s, 20s... até 5min.
+func QueueJobBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := queueJobBaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= queueJobMaxBackoff {
+			return queueJobMaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+const maxUTMValueLength = 128
+
+type LinkSettingsService struct {
+	linkRepo *repositories.LinkSettingsRepository
+	cache    *cache.Service
+}
+
+func NewLinkSettingsService(linkRepo *repositories.LinkSettingsRepository, cache *cache.Service) *LinkSettingsService {
+	return &LinkSettingsService{linkRepo: linkRepo, cache: cache}
+}
+
+// GetLinkSettings retorna nil quando o canal ainda não configurou links.
+func (s *LinkSettingsService) GetLinkSettings(ctx context.Context, channelID int64) (*models.LinkSettings, error) {
+	settings, err := s.linkRepo.GetByOwnerChannelID(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return settings, nil
+}
+
+// UpdateLinkSettings substitui as regras de links do canal: parâmetros UTM,
+// domínios permitidos/bloqueados e troca de hosts.
+func (s *LinkSettingsService) UpdateLinkSettings(ctx context.Context, channelID int64, body types.LinkSettingsRequest) (*models.LinkSettings, error) {
+	settings, err := s.linkRepo.GetByOwnerChannelID(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if settings == nil {
+		settings = &models.LinkSettings{OwnerChannelID: channelID}
+	}
+
+	settings.Enabled = body.Enabled
+	for _, f := range []struct {
+		name  string
+		value string
+		dst   *string
+	}{
+		{"utmSource", body.UTMSource, &settings.UTMSource},
+		{"utmMedium", body.UTMMedium, &settings.UTMMedium},
+		{"utmCampaign", body.UTMCampaign, &settings.UTMCampaign},
+		{"utmContent", body.UTMContent, &settings.UTMContent},
+	} {
+		value := strings.TrimSpace(f.value)
+		if len(value) > maxUTMValueLength {
+			return nil, errors.BadRequest(f.name + " muito longo (máximo 128 caracteres)")
+		}
+		*f.dst = value
+	}
+
+	allow, err := normalizeDomainList(body.AllowDomains)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := normalizeDomainList(body.DenyDomains)
+	if err != nil {
+		return nil, err
+	}
+	settings.AllowDomains = strings.Join(allow, ",")
+	settings.DenyDomains = strings.Join(deny, ",")
+
+	if len(body.Rewrites) > linkrewrite.MaxRewrites {
+		return nil, errors.BadRequest("Limite de 20 regras de troca de domínio atingido")
+	}
+	rewrites := make([]linkrewrite.Rewrite, 0, len(body.Rewrites))
+	seen := map[string]bool{}
+	for _, rw := range body.Rewrites {
+		from := linkrewrite.NormalizeDomain(rw.From)
+		to := strings.ToLower(strings.TrimSpace(rw.To))
+		if err := linkrewrite.ValidateDomain(from); err != nil {
+			return nil, errors.BadRequest("Regra de troca inválida: " + err.Error())
+		}
+		if err := linkrewrite.ValidateDomain(to); err != nil {
+			return nil, errors.BadRequest("Regra de troca inválida: " + err.Error())
+		}
+		if seen[from] {
+			return nil, errors.BadRequest("Domínio repetido nas regras de troca: " + from)
+		}
+		seen[from] = true
+		rewrites = append(rewrites, linkrewrite.Rewrite{From: from, To: to})
+	}
+	settings.Rewrites = linkrewrite.FormatRewrites(rewrites)
+
+	if err := s.linkRepo.Save(ctx, settings); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Regras de links atualizadas (Canal: %d, ativas: %v)", channelID, settings.Enabled)
+
+	return settings, nil
+}
+
+func normalizeDomainList(values []string) ([]string, error) {
+	if len(values) > linkrewrite.MaxDomains {
+		return nil, errors.BadRequest("Limite de 50 domínios por lista atingido")
+	}
+	domains := make([]string, 0, len(values))
+	for _, value := range values {
+		domain := linkrewrite.NormalizeDomain(value)
+		if domain == "" {
+			continue
+		}
+		if err := linkrewrite.ValidateDomain(domain); err != nil {
+			return nil, errors.BadRequest(err.Error())
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
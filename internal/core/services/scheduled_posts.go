@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/pkg/errors"
+)
+
+const (
+	ScheduledPostStatusPending  = repositories.ScheduledPostStatusPending
+	ScheduledPostStatusSending  = repositories.ScheduledPostStatusSending
+	ScheduledPostStatusSent     = repositories.ScheduledPostStatusSent
+	ScheduledPostStatusFailed   = repositories.ScheduledPostStatusFailed
+	ScheduledPostStatusCanceled = repositories.ScheduledPostStatusCanceled
+
+	ScheduledPostMinDelay    = time.Minute
+	ScheduledPostMaxDelay    = 60 * 24 * time.Hour
+	ScheduledPostStaleAfter  = 10 * time.Minute
+	scheduledPostDueBatchLen = 20
+)
+
+type ScheduledPostService struct {
+	repo        *repositories.ScheduledPostRepository
+	channelRepo *repositories.ChannelRepository
+	cache       *cache.Service
+}
+
+func NewScheduledPostService(repo *repositories.ScheduledPostRepository, channelRepo *repositories.ChannelRepository, cache *cache.Service) *ScheduledPostService {
+	return &ScheduledPostService{
+		repo:        repo,
+		channelRepo: channelRepo,
+		cache:       cache,
+	}
+}
+
+// Schedule grava uma cópia do PostBuilderState no banco, assim o envio não
+// depende da sessão no Redis, que expira em 24h.
+func (s *ScheduledPostService) Schedule(ctx context.Context, channelID, createdBy int64, sessionID string, state *cache.PostBuilderState, scheduledAt time.Time) (*models.ScheduledPost, error) {
+	if state == nil {
+		return nil, errors.BadRequest("postagem vazia")
+	}
+	if strings.TrimSpace(state.MediaFileID) == "" && strings.TrimSpace(state.Title+state.Body+state.Footer) == "" {
+		return nil, errors.BadRequest("postagem sem conteúdo")
+	}
+
+	now := time.Now()
+	if scheduledAt.Before(now.Add(ScheduledPostMinDelay)) {
+		return nil, errors.BadRequest("o horário precisa estar pelo menos 1 minuto no futuro")
+	}
+	if scheduledAt.After(now.Add(ScheduledPostMaxDelay)) {
+		return nil, errors.BadRequest("o horário não pode passar de 60 dias")
+	}
+
+	if _, err := s.channelRepo.GetChannelByIDLight(ctx, channelID); err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	snapshot := *state
+	snapshot.Step = ""
+	snapshot.MenuMessageID = 0
+	snapshot.PromptMessageID = 0
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	post := &models.ScheduledPost{
+		ChannelID:   channelID,
+		CreatedBy:   createdBy,
+		SessionID:   sessionID,
+		Payload:     string(payload),
+		MediaType:   snapshot.MediaType,
+		ScheduledAt: scheduledAt.UTC(),
+		Status:      ScheduledPostStatusPending,
+	}
+	if err := s.repo.Create(ctx, post); err != nil {
+		return nil, errors.Internal(err)
+	}
+	return post, nil
+}
+
+// ScheduleFromSession agenda uma postagem salva pelo PostBuilder (pb-save).
+// Só quem criou a postagem pode agendá-la; o PostBuilder fixo, sem dono, vale
+// para todos.
+func (s *ScheduledPostService) ScheduleFromSession(ctx context.Context, channelID, createdBy int64, sessionID string, scheduledAt time.Time) (*models.ScheduledPost, error) {
+	state, err := s.cache.GetPostBuilderSession(ctx, sessionID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if state == nil {
+		return nil, errors.BadRequest("sessão do PostBuilder não encontrada ou expirada")
+	}
+	if state.OwnerID != 0 && state.OwnerID != createdBy {
+		return nil, errors.ErrForbidden
+	}
+	return s.Schedule(ctx, channelID, createdBy, sessionID, state, scheduledAt)
+}
+
+func (s *ScheduledPostService) GetByID(ctx context.Context, id string) (*models.ScheduledPost, error) {
+	post, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if post == nil {
+		return nil, errors.ErrNotFound
+	}
+	return post, nil
+}
+
+func (s *ScheduledPostService) ListByChannel(ctx context.Context, channelID int64, status string) ([]models.ScheduledPost, error) {
+	posts, err := s.repo.ListByChannel(ctx, channelID, status)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return posts, nil
+}
+
+func (s *ScheduledPostService) ListPendingByCreator(ctx context.Context, userID int64) ([]models.ScheduledPost, error) {
+	posts, err := s.repo.ListPendingByCreator(ctx, userID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return posts, nil
+}
+
+func (s *ScheduledPostService) Cancel(ctx context.Context, channelID int64, id string) error {
+	rowsAffected, err := s.repo.Cancel(ctx, channelID, id)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// ClaimDue reserva os agendamentos vencidos para envio. Apenas os registros
+// efetivamente movidos para sending são retornados.
+func (s *ScheduledPostService) ClaimDue(ctx context.Context, now time.Time) ([]models.ScheduledPost, error) {
+	due, err := s.repo.ListDue(ctx, now.UTC(), scheduledPostDueBatchLen)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.ScheduledPost, 0, len(due))
+	for _, post := range due {
+		ok, err := s.repo.Claim(ctx, post.ID)
+		if err != nil {
+			return claimed, err
+		}
+		if ok {
+			post.Status = ScheduledPostStatusSending
+			post.Attempts++
+			claimed = append(claimed, post)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *ScheduledPostService) MarkSent(ctx context.Context, id string, messageID int) error {
+	return s.repo.MarkSent(ctx, id, messageID, time.Now().UTC())
+}
+
+func (s *ScheduledPostService) MarkFailed(ctx context.Context, id string, cause error) error {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+	if len(message) > 4000 {
+		message = message[:4000]
+	}
+	return s.repo.MarkFailed(ctx, id, message)
+}
+
+func (s *ScheduledPostService) ReleaseStale(ctx context.Context) (int64, error) {
+	return s.repo.ReleaseStale(ctx, time.Now().Add(-ScheduledPostStaleAfter))
+}
+
+func DecodeScheduledPostState(post *models.ScheduledPost) (*cache.PostBuilderState, error) {
+	var state cache.PostBuilderState
+	if err := json.Unmarshal([]byte(post.Payload), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -2,18 +2,25 @@ package services
 
 import (
 	"context"
+	"strings"
 
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
 	"github.com/leirbagxis/FreddyBot/internal/database/models"
 	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/separatorpool"
 	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
 type SeparatorService struct {
 	separatorRepo *repositories.SeparatorRepository
+	cache         *cache.Service
 }
 
-func NewSeparatorService(separatorRepo *repositories.SeparatorRepository) *SeparatorService {
-	return &SeparatorService{separatorRepo: separatorRepo}
+func NewSeparatorService(separatorRepo *repositories.SeparatorRepository, cache *cache.Service) *SeparatorService {
+	return &SeparatorService{separatorRepo: separatorRepo, cache: cache}
 }
 
 func (s *SeparatorService) GetSeparatorByTwoID(ctx context.Context, channelId int64, separatorId string) (*models.Separator, error) {
@@ -28,6 +35,7 @@ func (s *SeparatorService) SaveSeparator(ctx context.Context, separator *models.
 	if err := s.separatorRepo.SaveSeparator(ctx, separator); err != nil {
 		return errors.Internal(err)
 	}
+	s.cache.InvalidateChannel(ctx, separator.OwnerChannelID)
 	return nil
 }
 
@@ -43,5 +51,119 @@ func (s *SeparatorService) DeleteSeparatorByOwnerChannelId(ctx context.Context,
 	if err := s.separatorRepo.DeleteSeparatorByOwnerChannelId(ctx, channelID); err != nil {
 		return errors.Internal(err)
 	}
+	s.cache.InvalidateChannel(ctx, channelID)
 	return nil
 }
+
+// UpdateSettings grava o modo de rodízio e o intervalo de silêncio do
+// separador. O canal precisa ter um sticker principal.
+func (s *SeparatorService) UpdateSettings(ctx context.Context, channelID int64, body types.SeparatorSettingsRequest) (*models.Separator, error) {
+	rotation := strings.TrimSpace(body.Rotation)
+	if !separatorpool.IsValidMode(rotation) {
+		return nil, errors.BadRequest("Modo de rodízio inválido (use round_robin, random ou vazio para desativar)")
+	}
+	if err := separatorpool.ValidateCooldown(body.CooldownMinutes); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	rowsAffected, err := s.separatorRepo.UpdateSettings(ctx, channelID, rotation, body.CooldownMinutes)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return nil, errors.BadRequest("Configure um sticker separador pelo bot antes de ajustar o rodízio")
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return s.GetSeparatorByOwnerChannelID(ctx, channelID)
+}
+
+func (s *SeparatorService) ListVariants(ctx context.Context, channelID int64) ([]models.SeparatorVariant, error) {
+	variants, err := s.separatorRepo.ListVariants(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return variants, nil
+}
+
+// AddVariant inclui um sticker no pool do separador. As variantes só são usadas
+// junto com o sticker principal, então ele precisa existir.
+func (s *SeparatorService) AddVariant(ctx context.Context, variant *models.SeparatorVariant) error {
+	variant.Hashtag = separatorpool.NormalizeHashtag(variant.Hashtag)
+	if err := separatorpool.ValidateVariant(variant); err != nil {
+		return errors.BadRequest(err.Error())
+	}
+
+	sep, err := s.separatorRepo.GetSeparatorByOwnerChannelID(ctx, variant.OwnerChannelID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if sep == nil {
+		return errors.BadRequest("Configure o sticker separador principal antes de adicionar variantes")
+	}
+
+	existing, err := s.separatorRepo.ListVariants(ctx, variant.OwnerChannelID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if len(existing) >= separatorpool.MaxVariants {
+		return errors.BadRequest("Limite de 20 variantes de separador por canal atingido")
+	}
+
+	if variant.VariantID == "" {
+		variant.VariantID = uuid.NewString()
+	}
+	if err := s.separatorRepo.CreateVariant(ctx, variant); err != nil {
+		return errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, variant.OwnerChannelID)
+	logger.Bot("✅ Variante de separador criada: %s (Canal: %d)", variant.VariantID, variant.OwnerChannelID)
+	return nil
+}
+
+// UpdateVariant troca o filtro da variante (tipo de mensagem ou hashtag); os
+// dois vazios a colocam no rodízio geral.
+func (s *SeparatorService) UpdateVariant(ctx context.Context, channelID int64, variantID string, body types.SeparatorVariantRequest) (*models.SeparatorVariant, error) {
+	variant, err := s.separatorRepo.GetVariantByID(ctx, channelID, variantID)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+
+	variant.MessageType = strings.TrimSpace(body.MessageType)
+	variant.Hashtag = separatorpool.NormalizeHashtag(body.Hashtag)
+	if err := separatorpool.ValidateVariant(variant); err != nil {
+		return nil, errors.BadRequest(err.Error())
+	}
+
+	if err := s.separatorRepo.SaveVariant(ctx, variant); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return variant, nil
+}
+
+func (s *SeparatorService) DeleteVariant(ctx context.Context, channelID int64, variantID string) error {
+	rowsAffected, err := s.separatorRepo.DeleteVariant(ctx, channelID, variantID)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return nil
+}
+
+// DeleteVariants remove todas as variantes do canal e retorna quantas eram.
+func (s *SeparatorService) DeleteVariants(ctx context.Context, channelID int64) (int64, error) {
+	rowsAffected, err := s.separatorRepo.DeleteVariants(ctx, channelID)
+	if err != nil {
+		return 0, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	return rowsAffected, nil
+}
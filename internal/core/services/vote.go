@@ -2,31 +2,514 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/cache"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
 	"github.com/leirbagxis/FreddyBot/internal/database/repositories"
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
 	"github.com/leirbagxis/FreddyBot/pkg/errors"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
 type VoteService struct {
-	voteRepo *repositories.VoteRepository
+	voteRepo     *repositories.VoteRepository
+	reactionRepo *repositories.ReactionCountRepository
+	pollRepo     *repositories.VotePollRepository
+	cache        *cache.Service
 }
 
-func NewVoteService(voteRepo *repositories.VoteRepository) *VoteService {
-	return &VoteService{voteRepo: voteRepo}
+func NewVoteService(voteRepo *repositories.VoteRepository, reactionRepo *repositories.ReactionCountRepository, pollRepo *repositories.VotePollRepository, cache *cache.Service) *VoteService {
+	return &VoteService{voteRepo: voteRepo, reactionRepo: reactionRepo, pollRepo: pollRepo, cache: cache}
 }
 
-func (s *VoteService) ToggleVote(ctx context.Context, chatID int64, messageID int, inlineMessageID string, userID int64, emoji string) (bool, int64, error) {
-	added, count, err := s.voteRepo.ToggleVote(ctx, chatID, messageID, inlineMessageID, userID, emoji)
+// Quantas mensagens com votos pendentes cada rodada do flush grava no banco.
+const voteFlushBatch = 100
+
+// VoteMessageKey identifica a mensagem votada nas chaves do Redis.
+func VoteMessageKey(chatID int64, messageID int, inlineMessageID string) string {
+	if inlineMessageID != "" {
+		return "i:" + inlineMessageID
+	}
+	return fmt.Sprintf("c:%d:%d", chatID, messageID)
+}
+
+func parseVoteMessageKey(key string) (chatID int64, messageID int, inlineMessageID string, ok bool) {
+	if inline, found := strings.CutPrefix(key, "i:"); found {
+		return 0, 0, inline, inline != ""
+	}
+	rest, found := strings.CutPrefix(key, "c:")
+	if !found {
+		return 0, 0, "", false
+	}
+	chat, msg, found := strings.Cut(rest, ":")
+	if !found {
+		return 0, 0, "", false
+	}
+	chatID, err1 := strconv.ParseInt(chat, 10, 64)
+	messageID, err2 := strconv.Atoi(msg)
+	return chatID, messageID, "", err1 == nil && err2 == nil
+}
+
+// CastVote registra o voto no Redis conforme o modo da votação e retorna as
+// contagens atualizadas da mensagem: na escolha única um novo emoji substitui
+// o voto anterior; na múltipla cada emoji é alternado à parte. O banco é
+// atualizado depois, em lote, por FlushVotes.
+func (s *VoteService) CastVote(ctx context.Context, poll *models.VotePoll, chatID int64, messageID int, inlineMessageID string, userID int64, emoji string) (bool, map[string]int64, error) {
+	key := VoteMessageKey(chatID, messageID, inlineMessageID)
+	multiple := poll != nil && poll.Mode == votepoll.ModeMultiple
+
+	added, counts, err := s.cache.ToggleVote(ctx, key, userID, emoji, multiple)
+	if err == cache.ErrVotesNotLoaded {
+		if err := s.loadVotes(ctx, key, chatID, messageID, inlineMessageID); err != nil {
+			return false, nil, errors.Internal(err)
+		}
+		added, counts, err = s.cache.ToggleVote(ctx, key, userID, emoji, multiple)
+	}
+	if err != nil {
+		return false, nil, errors.Internal(err)
+	}
+	return added, counts, nil
+}
+
+// loadVotes copia os votos da mensagem do banco para o Redis.
+func (s *VoteService) loadVotes(ctx context.Context, key string, chatID int64, messageID int, inlineMessageID string) error {
+	choices, err := s.voteRepo.ListMessageVotes(ctx, chatID, messageID, inlineMessageID)
+	if err != nil {
+		return err
+	}
+	return s.cache.SeedVotes(ctx, key, choices)
+}
+
+// HasVoted indica se o usuário tem algum voto na mensagem, incluindo os ainda
+// não gravados no banco.
+func (s *VoteService) HasVoted(ctx context.Context, chatID int64, messageID int, inlineMessageID string, userID int64) (bool, error) {
+	emojis, loaded, err := s.cache.UserVotes(ctx, VoteMessageKey(chatID, messageID, inlineMessageID), userID)
+	if err != nil {
+		return false, errors.Internal(err)
+	}
+	if loaded {
+		return len(emojis) > 0, nil
+	}
+
+	voted, err := s.voteRepo.HasUserVoted(ctx, chatID, messageID, inlineMessageID, userID)
+	if err != nil {
+		return false, errors.Internal(err)
+	}
+	return voted, nil
+}
+
+// FlushVotes grava no banco as escolhas dos usuários que votaram desde a
+// última rodada e retorna quantos foram gravados. Em caso de falha os
+// usuários voltam para a lista de pendentes.
+func (s *VoteService) FlushVotes(ctx context.Context) (int, error) {
+	flushed := 0
+	for {
+		messages, err := s.cache.PopDirtyVoteMessages(ctx, voteFlushBatch)
+		if err != nil {
+			return flushed, errors.Internal(err)
+		}
+
+		for _, key := range messages {
+			chatID, messageID, inlineMessageID, ok := parseVoteMessageKey(key)
+			if !ok {
+				logger.Warn("VOTE", "Chave de votos inválida na fila de flush: %s", key)
+				continue
+			}
+
+			voters, err := s.cache.TakeDirtyVoters(ctx, key)
+			if err != nil {
+				logger.Error("VOTE", "Erro ao ler votos pendentes de %s: %v", key, err)
+				_ = s.cache.MarkVotersDirty(ctx, key, nil)
+				continue
+			}
+			if err := s.voteRepo.ReplaceUserVotes(ctx, chatID, messageID, inlineMessageID, voters); err != nil {
+				logger.Error("VOTE", "Erro ao gravar votos de %s: %v", key, err)
+				userIDs := make([]int64, 0, len(voters))
+				for userID := range voters {
+					userIDs = append(userIDs, userID)
+				}
+				if err := s.cache.MarkVotersDirty(ctx, key, userIDs); err != nil {
+					logger.Error("VOTE", "Erro ao devolver votos pendentes de %s: %v", key, err)
+				}
+				continue
+			}
+			flushed += len(voters)
+		}
+
+		if len(messages) < voteFlushBatch {
+			return flushed, nil
+		}
+	}
+}
+
+// Poll retorna as regras de votação do post. No primeiro voto de um post de
+// canal elas são copiadas das configurações do canal, com o prazo contado da
+// publicação (postedAt); mudanças posteriores nas configurações não afetam
+// posts que já votaram. Mensagens inline e canais sem configuração usam o
+// padrão, sem gravar nada no banco. As regras resolvidas ficam no Redis junto
+// dos votos da mensagem, então os toques seguintes não consultam o banco.
+func (s *VoteService) Poll(ctx context.Context, chatID int64, messageID int, inlineMessageID string, postedAt time.Time) (*models.VotePoll, error) {
+	key := VoteMessageKey(chatID, messageID, inlineMessageID)
+	if poll, err := s.cache.GetVotePoll(ctx, key); err != nil {
+		logger.Warn("VOTE", "Erro ao ler regras de votação de %s do cache: %v", key, err)
+	} else if poll != nil {
+		return poll, nil
+	}
+
+	poll, err := s.resolvePoll(ctx, chatID, messageID, inlineMessageID, postedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.SetVotePoll(ctx, key, poll); err != nil {
+		logger.Warn("VOTE", "Erro ao guardar regras de votação de %s no cache: %v", key, err)
+	}
+	return poll, nil
+}
+
+func (s *VoteService) resolvePoll(ctx context.Context, chatID int64, messageID int, inlineMessageID string, postedAt time.Time) (*models.VotePoll, error) {
+	poll, err := s.pollRepo.GetPoll(ctx, chatID, messageID, inlineMessageID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if poll != nil || inlineMessageID != "" || chatID == 0 {
+		if poll == nil {
+			poll = votepoll.New(nil, postedAt)
+		}
+		return poll, nil
+	}
+
+	settings, err := s.pollRepo.GetSettings(ctx, chatID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	poll = votepoll.New(settings, postedAt)
+	if votepoll.IsDefault(settings) {
+		return poll, nil
+	}
+
+	poll.ChatID = chatID
+	poll.MessageID = messageID
+	if err := s.pollRepo.CreatePoll(ctx, poll); err != nil {
+		return nil, errors.Internal(err)
+	}
+	return poll, nil
+}
+
+// GetVoteSettings retorna nil quando o canal ainda não configurou as votações.
+func (s *VoteService) GetVoteSettings(ctx context.Context, channelID int64) (*models.VoteSettings, error) {
+	settings, err := s.pollRepo.GetSettings(ctx, channelID)
 	if err != nil {
-		return false, 0, errors.Internal(err)
+		return nil, errors.Internal(err)
 	}
-	return added, count, nil
+	return settings, nil
 }
 
+// UpdateVoteSettings salva como funcionam as votações dos próximos posts do
+// canal.
+func (s *VoteService) UpdateVoteSettings(ctx context.Context, channelID int64, body types.VoteSettingsRequest) (*models.VoteSettings, error) {
+	mode := strings.TrimSpace(body.Mode)
+	if mode == "" {
+		mode = votepoll.ModeSingle
+	}
+	if !votepoll.IsValidMode(mode) {
+		return nil, errors.BadRequest("Modo de votação inválido: use single ou multiple")
+	}
+	if body.DurationMinutes < 0 || body.DurationMinutes > votepoll.MaxDurationMinutes {
+		return nil, errors.BadRequest("Prazo de votação inválido: use de 0 a 43200 minutos (30 dias)")
+	}
+
+	settings, err := s.pollRepo.GetSettings(ctx, channelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if settings == nil {
+		settings = &models.VoteSettings{OwnerChannelID: channelID}
+	}
+
+	settings.Mode = mode
+	settings.HideCounts = body.HideCounts
+	settings.DurationMinutes = body.DurationMinutes
+	settings.ShowResults = body.ShowResults
+
+	if err := s.pollRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	s.cache.InvalidateChannel(ctx, channelID)
+	logger.Bot("✅ Votação atualizada (Canal: %d, modo: %s, ocultar: %v, prazo: %dmin)", channelID, settings.Mode, settings.HideCounts, settings.DurationMinutes)
+
+	return settings, nil
+}
+
+// UpdatePollDeadline encerra, reabre ou muda o prazo da votação de um post do
+// canal. Posts sem votação registrada recebem as regras atuais do canal.
+func (s *VoteService) UpdatePollDeadline(ctx context.Context, channelID int64, messageID int, closesAt *time.Time) (*models.VotePoll, error) {
+	if messageID <= 0 {
+		return nil, errors.BadRequest("messageId inválido")
+	}
+
+	poll, err := s.pollRepo.GetPoll(ctx, channelID, messageID, "")
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if poll == nil {
+		settings, err := s.pollRepo.GetSettings(ctx, channelID)
+		if err != nil {
+			return nil, errors.Internal(err)
+		}
+		poll = votepoll.New(settings, time.Now())
+		poll.ChatID = channelID
+		poll.MessageID = messageID
+		poll.ClosesAt = closesAt
+		if err := s.pollRepo.CreatePoll(ctx, poll); err != nil {
+			return nil, errors.Internal(err)
+		}
+	}
+
+	if err := s.pollRepo.UpdateClosesAt(ctx, poll.ID, closesAt); err != nil {
+		return nil, errors.Internal(err)
+	}
+	poll.ClosesAt = closesAt
+	if err := s.cache.SetVotePoll(ctx, VoteMessageKey(channelID, messageID, ""), poll); err != nil {
+		logger.Warn("VOTE", "Erro ao atualizar regras de votação no cache (Canal: %d, post: %d): %v", channelID, messageID, err)
+	}
+
+	logger.Bot("✅ Prazo da votação atualizado (Canal: %d, post: %d)", channelID, messageID)
+	return poll, nil
+}
+
+// GetVoteCounts lê as contagens do Redis, que incluem votos ainda não
+// gravados, e cai no banco para mensagens que não estão lá.
 func (s *VoteService) GetVoteCounts(ctx context.Context, chatID int64, messageID int, inlineMessageID string) (map[string]int64, error) {
-	counts, err := s.voteRepo.GetVoteCounts(ctx, chatID, messageID, inlineMessageID)
+	counts, loaded, err := s.cache.VoteCounts(ctx, VoteMessageKey(chatID, messageID, inlineMessageID))
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if loaded {
+		return counts, nil
+	}
+
+	counts, err = s.voteRepo.GetVoteCounts(ctx, chatID, messageID, inlineMessageID)
 	if err != nil {
 		return nil, errors.Internal(err)
 	}
 	return counts, nil
 }
+
+// Janela e ranking padrão das estatísticas de votos.
+const (
+	DefaultVoteAnalyticsDays = 30
+	MaxVoteAnalyticsDays     = 365
+	DefaultVoteTopPosts      = 10
+	MaxVoteTopPosts          = 50
+)
+
+// voteWindow normaliza a janela em dias e retorna o início dela (meia-noite
+// UTC do primeiro dia).
+func voteWindow(days int) (int, time.Time) {
+	if days <= 0 {
+		days = DefaultVoteAnalyticsDays
+	}
+	days = min(days, MaxVoteAnalyticsDays)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return days, today.AddDate(0, 0, -(days - 1))
+}
+
+// Analytics resume os votos de reação nos posts do canal nos últimos days
+// dias: totais, votos por emoji, por dia e os top posts mais votados. Só
+// contam os votos que continuam ativos; votos retirados são apagados. As
+// reações nativas entram nos totais no dia da primeira reação do post, mas são
+// anônimas e não contam como votantes únicos.
+func (s *VoteService) Analytics(ctx context.Context, channelID int64, days, top int) (*types.VoteAnalyticsResponse, error) {
+	days, since := voteWindow(days)
+	if top <= 0 {
+		top = DefaultVoteTopPosts
+	}
+	top = min(top, MaxVoteTopPosts)
+
+	total, voters, _, err := s.voteRepo.CountChannelVotes(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	native, err := s.reactionRepo.ListChannelCounts(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	result := &types.VoteAnalyticsResponse{
+		Days:         days,
+		Since:        since,
+		TotalVotes:   total,
+		UniqueVoters: voters,
+		ByEmoji:      []types.EmojiVotes{},
+		ByDay:        make([]types.DayVotes, days),
+		TopPosts:     []types.PostVotes{},
+	}
+	for i := range result.ByDay {
+		result.ByDay[i].Date = since.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+	addToDay := func(t time.Time, votes int64) {
+		if i := int(t.UTC().Sub(since) / (24 * time.Hour)); i >= 0 && i < days {
+			result.ByDay[i].Votes += votes
+		}
+	}
+
+	byEmoji, err := s.voteRepo.CountChannelVotesByEmoji(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	buttonPosts, err := s.voteRepo.TopChannelPosts(ctx, channelID, since, 0)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	byDay, err := s.voteRepo.CountChannelVotesByDay(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	for _, d := range byDay {
+		addToDay(d.Day, d.Count)
+	}
+
+	emojiTotals := make(map[string]int64, len(byEmoji))
+	for _, e := range byEmoji {
+		emojiTotals[e.Emoji] = e.Count
+	}
+	postTotals := make(map[int]int64, len(buttonPosts))
+	for _, p := range buttonPosts {
+		postTotals[p.MessageID] = p.Count
+	}
+	for _, c := range native {
+		result.TotalVotes += c.Count
+		result.NativeReactions += c.Count
+		emojiTotals[c.Emoji] += c.Count
+		postTotals[c.MessageID] += c.Count
+		addToDay(c.CreatedAt, c.Count)
+	}
+	result.Posts = int64(len(postTotals))
+
+	for emoji, count := range emojiTotals {
+		share := 0.0
+		if result.TotalVotes > 0 {
+			share = math.Round(float64(count)/float64(result.TotalVotes)*1000) / 10
+		}
+		result.ByEmoji = append(result.ByEmoji, types.EmojiVotes{Emoji: emoji, Votes: count, Share: share})
+	}
+	sort.Slice(result.ByEmoji, func(i, j int) bool {
+		if result.ByEmoji[i].Votes != result.ByEmoji[j].Votes {
+			return result.ByEmoji[i].Votes > result.ByEmoji[j].Votes
+		}
+		return result.ByEmoji[i].Emoji < result.ByEmoji[j].Emoji
+	})
+
+	ranked := make([]int, 0, len(postTotals))
+	for messageID := range postTotals {
+		ranked = append(ranked, messageID)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if postTotals[ranked[i]] != postTotals[ranked[j]] {
+			return postTotals[ranked[i]] > postTotals[ranked[j]]
+		}
+		return ranked[i] > ranked[j]
+	})
+	if len(ranked) > top {
+		ranked = ranked[:top]
+	}
+
+	if len(ranked) > 0 {
+		rows, err := s.postEmojiRows(ctx, channelID, since)
+		if err != nil {
+			return nil, err
+		}
+		emojis := make(map[int]map[string]int64, len(ranked))
+		for _, messageID := range ranked {
+			emojis[messageID] = map[string]int64{}
+		}
+		for _, row := range rows {
+			if m, ok := emojis[row.MessageID]; ok {
+				m[row.Emoji] += row.Count
+			}
+		}
+		for _, messageID := range ranked {
+			result.TopPosts = append(result.TopPosts, types.PostVotes{
+				MessageID: messageID,
+				Link:      PostLink(channelID, messageID),
+				Votes:     postTotals[messageID],
+				Emojis:    emojis[messageID],
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// postEmojiRows junta os votos dos botões e as reações nativas por post e emoji.
+func (s *VoteService) postEmojiRows(ctx context.Context, channelID int64, since time.Time) ([]repositories.PostVoteCount, error) {
+	rows, err := s.voteRepo.CountChannelVotesByPostEmoji(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	native, err := s.reactionRepo.CountChannelReactionsByPostEmoji(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	return append(rows, native...), nil
+}
+
+// Export lista os votos do canal por post e emoji nos últimos days dias, com a
+// origem de cada linha (botões ou reações nativas).
+func (s *VoteService) Export(ctx context.Context, channelID int64, days int) ([]types.VoteExportRow, error) {
+	_, since := voteWindow(days)
+	rows, err := s.voteRepo.CountChannelVotesByPostEmoji(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	native, err := s.reactionRepo.CountChannelReactionsByPostEmoji(ctx, channelID, since)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	result := make([]types.VoteExportRow, 0, len(rows)+len(native))
+	for _, row := range rows {
+		result = append(result, types.VoteExportRow{
+			MessageID: row.MessageID,
+			Link:      PostLink(channelID, row.MessageID),
+			Emoji:     row.Emoji,
+			Votes:     row.Count,
+			Source:    reactions.ModeButtons,
+		})
+	}
+	for _, row := range native {
+		result = append(result, types.VoteExportRow{
+			MessageID: row.MessageID,
+			Link:      PostLink(channelID, row.MessageID),
+			Emoji:     row.Emoji,
+			Votes:     row.Count,
+			Source:    reactions.ModeNative,
+		})
+	}
+	return result, nil
+}
+
+// SetReactionCounts grava as contagens atuais das reações nativas de um post
+// de canal.
+func (s *VoteService) SetReactionCounts(ctx context.Context, chatID int64, messageID int, counts map[string]int64) error {
+	if err := s.reactionRepo.ReplaceCounts(ctx, chatID, messageID, counts); err != nil {
+		return errors.Internal(err)
+	}
+	return nil
+}
+
+// PostLink monta o link t.me/c de um post, que abre para membros de canais
+// públicos e privados.
+func PostLink(channelID int64, messageID int) string {
+	id := strings.TrimPrefix(strconv.FormatInt(channelID, 10), "-100")
+	return fmt.Sprintf("https://t.me/c/%s/%d", id, messageID)
+}
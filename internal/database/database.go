@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"strings"
 	"time"
 
@@ -73,22 +74,34 @@ func InitDB() *gorm.DB {
 		customLogger.DB("⚙️ Pool de conexões configurado (Idle: 10, Open: 100)")
 	}
 
-	// Forçar recriação de índices que mudaram de estrutura
-	db.Exec("DROP INDEX IF EXISTS idx_vote_user")
+	// Recriar índices que mudaram de estrutura só quando ainda estão no formato antigo
+	migrateVoteUserIndex(db)
 
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.ServerConfig{},
 		&models.Channel{},
 		&models.ChannelEvent{},
+		&models.ScheduledPost{},
+		&models.QueueJob{},
 		&models.DefaultCaption{},
 		&models.MessagePermission{},
 		&models.ButtonsPermission{},
 		&models.Button{},
 		&models.Separator{},
+		&models.SeparatorVariant{},
+		&models.LinkSettings{},
+		&models.ContentFilter{},
 		&models.CustomCaption{},
 		&models.CustomCaptionButton{},
+		&models.CaptionRule{},
+		&models.CaptionVariant{},
+		&models.AuthorSignature{},
+		&models.CaptionVariantButton{},
 		&models.Vote{},
+		&models.VoteSettings{},
+		&models.VotePoll{},
+		&models.ReactionCount{},
 	)
 	if err != nil {
 		panic(err)
@@ -101,6 +114,31 @@ func InitDB() *gorm.DB {
 	return db
 }
 
+// migrateVoteUserIndex remove o idx_vote_user antigo (sem emoji) para o
+// AutoMigrate recriá-lo com a chave (chat, message, inline, user, emoji).
+// Bancos que já têm o índice novo não são tocados.
+func migrateVoteUserIndex(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasIndex(&models.Vote{}, "idx_vote_user") {
+		return
+	}
+
+	indexes, err := migrator.GetIndexes(&models.Vote{})
+	if err != nil {
+		customLogger.Warn("DB", "⚠️ Não foi possível ler os índices de votes: %v", err)
+		return
+	}
+	for _, index := range indexes {
+		if index.Name() != "idx_vote_user" || slices.Contains(index.Columns(), "emoji") {
+			continue
+		}
+		if err := migrator.DropIndex(&models.Vote{}, "idx_vote_user"); err != nil {
+			panic(err)
+		}
+		customLogger.DB("♻️ Índice idx_vote_user antigo removido para recriação")
+	}
+}
+
 func initServerConfig(db *gorm.DB) error {
 	config := models.ServerConfig{
 		ID:                      1,
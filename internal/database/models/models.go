@@ -28,29 +28,46 @@ type User struct {
 }
 
 type Channel struct {
-	ID                     int64           `gorm:"primaryKey" json:"id"` // ID do Telegram
-	Title                  string          `json:"title"`
-	NewPackCaption         string          `json:"newPackCaption"`
-	NewPackMessageButtons  *bool           `gorm:"default:true" json:"newPackMessageButtons"`
-	NewPackStickerButtons  *bool           `gorm:"default:true" json:"newPackStickerButtons"`
-	NewPackMessagePosition *string         `gorm:"default:above" json:"newPackMessagePosition"`
-	NewPackReplyToSticker  *bool           `gorm:"default:false" json:"newPackReplyToSticker"`
-	InviteURL              string          `json:"inviteUrl"`
-	OwnerID                int64           `gorm:"index" json:"ownerId"`
-	Owner                  *User           `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
-	DefaultCaption         *DefaultCaption `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"defaultCaption,omitempty"`
-	Buttons                []Button        `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"buttons"`
-	Separator              *Separator      `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"separator,omitempty"`
-	CustomCaptions         []CustomCaption `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"customCaptions"`
-	TokenVersion           int64           `gorm:"not null;default:1"`
-	Reactions              string          `json:"reactions"`
-	ReactionPosition       int             `gorm:"default:0" json:"reactionPosition"`
-	DynamicLinks           bool            `gorm:"default:false" json:"dynamicLinks"`
-	DLBotButtons           bool            `gorm:"default:true" json:"dlBotButtons"`
-	DLBotCaptions          bool            `gorm:"default:true" json:"dlBotCaptions"`
-	DLBotReactions         bool            `gorm:"default:true" json:"dlBotReactions"`
-	CreatedAt              time.Time       `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt              time.Time       `gorm:"autoUpdateTime;index" json:"updated_at"`
+	ID                     int64              `gorm:"primaryKey" json:"id"` // ID do Telegram
+	Title                  string             `json:"title"`
+	NewPackCaption         string             `json:"newPackCaption"`
+	NewPackMessageButtons  *bool              `gorm:"default:true" json:"newPackMessageButtons"`
+	NewPackStickerButtons  *bool              `gorm:"default:true" json:"newPackStickerButtons"`
+	NewPackMessagePosition *string            `gorm:"default:above" json:"newPackMessagePosition"`
+	NewPackReplyToSticker  *bool              `gorm:"default:false" json:"newPackReplyToSticker"`
+	InviteURL              string             `json:"inviteUrl"`
+	OwnerID                int64              `gorm:"index" json:"ownerId"`
+	Owner                  *User              `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+	DefaultCaption         *DefaultCaption    `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"defaultCaption,omitempty"`
+	Buttons                []Button           `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"buttons"`
+	Separator              *Separator         `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"separator,omitempty"`
+	SeparatorVariants      []SeparatorVariant `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"separatorVariants"`
+	CustomCaptions         []CustomCaption    `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"customCaptions"`
+	CaptionRules           []CaptionRule      `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"captionRules"`
+	CaptionVariants        []CaptionVariant   `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"captionVariants"`
+	LinkSettings           *LinkSettings      `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"linkSettings,omitempty"`
+	ContentFilter          *ContentFilter     `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"contentFilter,omitempty"`
+	AuthorSignatures       []AuthorSignature  `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"authorSignatures"`
+	VoteSettings           *VoteSettings      `gorm:"foreignKey:OwnerChannelID;constraint:OnDelete:CASCADE;" json:"voteSettings,omitempty"`
+	TokenVersion           int64              `gorm:"not null;default:1"`
+	Reactions              string             `json:"reactions"`
+	ReactionPosition       int                `gorm:"default:0" json:"reactionPosition"`
+	ReactionMode           string             `gorm:"default:buttons" json:"reactionMode"` // buttons ou native
+	DynamicLinks           bool               `gorm:"default:false" json:"dynamicLinks"`
+	DLBotButtons           bool               `gorm:"default:true" json:"dlBotButtons"`
+	DLBotCaptions          bool               `gorm:"default:true" json:"dlBotCaptions"`
+	DLBotReactions         bool               `gorm:"default:true" json:"dlBotReactions"`
+	ProcessEdits           bool               `gorm:"default:false" json:"processEdits"`
+	CaptionPosition        string             `gorm:"default:append" json:"captionPosition"`
+	CaptionSeparator       string             `json:"captionSeparator"`
+	CaptionRotation        string             `json:"captionRotation"` // vazio desativa o pool de legendas
+	CaptionOverflow        string             `gorm:"default:shorten" json:"captionOverflow"`
+	TranslateLanguage      string             `json:"translateLanguage"` // vazio desativa a tradução automática
+	TranslateMode          string             `gorm:"default:append" json:"translateMode"`
+	SignatureFooter        bool               `gorm:"default:false" json:"signatureFooter"`
+	SignatureFormat        string             `json:"signatureFormat"` // template do rodapé; vazio usa o padrão
+	CreatedAt              time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt              time.Time          `gorm:"autoUpdateTime;index" json:"updated_at"`
 }
 
 type ChannelEvent struct {
@@ -65,11 +82,46 @@ type ChannelEvent struct {
 	MessageType       string    `json:"messageType"`
 	TelegramMessageID int       `json:"telegramMessageId"`
 	SessionID         string    `gorm:"index" json:"sessionId"`
+	CorrelationID     string    `gorm:"index" json:"correlationId"`
 	ErrorMessage      string    `gorm:"type:text" json:"errorMessage"`
 	Metadata          string    `gorm:"type:text" json:"metadata"`
 	CreatedAt         time.Time `gorm:"autoCreateTime;index;index:idx_channel_event_channel_created,sort:desc;index:idx_channel_event_owner_created,sort:desc" json:"created_at"`
 }
 
+type ScheduledPost struct {
+	ID            string     `gorm:"type:text;primaryKey" json:"id"`
+	ChannelID     int64      `gorm:"index;index:idx_scheduled_post_channel_status" json:"channelId"`
+	CreatedBy     int64      `gorm:"index" json:"createdBy"`
+	SessionID     string     `gorm:"index" json:"sessionId"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	MediaType     string     `json:"mediaType"`
+	ScheduledAt   time.Time  `gorm:"index;index:idx_scheduled_post_status_due" json:"scheduledAt"`
+	Status        string     `gorm:"index;index:idx_scheduled_post_channel_status;index:idx_scheduled_post_status_due;default:pending" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	ErrorMessage  string     `gorm:"type:text" json:"errorMessage"`
+	SentMessageID int        `json:"sentMessageId"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type QueueJob struct {
+	ID             string    `gorm:"type:text;primaryKey" json:"id"`
+	Queue          string    `gorm:"index:idx_queue_job_ready" json:"queue"`
+	Kind           string    `json:"kind"`
+	ChannelID      int64     `gorm:"index" json:"channelId"`
+	MessageID      int       `json:"messageId"`
+	Payload        string    `gorm:"type:text" json:"payload"`
+	Status         string    `gorm:"index;index:idx_queue_job_ready" json:"status"`
+	Attempts       int       `gorm:"default:0" json:"attempts"`
+	ClaimToken     string    `json:"-"`
+	CompletedSteps string    `gorm:"type:text" json:"completedSteps"`
+	NextAttemptAt  time.Time `gorm:"index:idx_queue_job_ready" json:"nextAttemptAt"`
+	LastError      string    `gorm:"type:text" json:"lastError"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime;index" json:"updated_at"`
+}
+
 type DefaultCaption struct {
 	CaptionID         string             `gorm:"type:text;primaryKey" json:"captionId"`
 	Caption           string             `json:"caption"`
@@ -121,11 +173,70 @@ type Button struct {
 	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// Separator é o sticker principal enviado depois de cada post. Rotation e
+// CooldownMinutes valem também para as variantes do canal (ver separatorpool).
 type Separator struct {
-	ID             string    `gorm:"type:text;primaryKey" json:"id"`
+	ID              string    `gorm:"type:text;primaryKey" json:"id"`
+	SeparatorID     string    `json:"separatorId"`
+	SeparatorURL    string    `json:"separatorUrl"`
+	Rotation        string    `json:"rotation"`                         // vazio usa só o sticker principal
+	CooldownMinutes int       `gorm:"default:0" json:"cooldownMinutes"` // silêncio antes do separador; 0 envia depois de todo post
+	OwnerChannelID  int64     `gorm:"unique;index" json:"ownerChannelId"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SeparatorVariant é um sticker extra do separador. Sem MessageType e Hashtag
+// entra no rodízio com o sticker principal; com um deles, é usado no lugar do
+// principal nos posts daquele tipo ou com aquela legenda customizada.
+type SeparatorVariant struct {
+	VariantID      string    `gorm:"type:text;primaryKey" json:"variantId"`
+	OwnerChannelID int64     `gorm:"index" json:"ownerChannelId"`
 	SeparatorID    string    `json:"separatorId"`
 	SeparatorURL   string    `json:"separatorUrl"`
+	MessageType    string    `json:"messageType"`
+	Hashtag        string    `json:"hashtag"` // código da legenda customizada, sem #
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// LinkSettings guarda as regras aplicadas aos links dos posts do canal.
+type LinkSettings struct {
+	ID             string    `gorm:"type:text;primaryKey" json:"id"`
+	OwnerChannelID int64     `gorm:"unique;index" json:"ownerChannelId"`
+	Enabled        bool      `gorm:"default:false" json:"enabled"`
+	UTMSource      string    `json:"utmSource"`
+	UTMMedium      string    `json:"utmMedium"`
+	UTMCampaign    string    `json:"utmCampaign"`
+	UTMContent     string    `json:"utmContent"`
+	AllowDomains   string    `gorm:"type:text" json:"allowDomains"` // vazia permite todos os domínios
+	DenyDomains    string    `gorm:"type:text" json:"denyDomains"`
+	Rewrites       string    `gorm:"type:text" json:"rewrites"` // "origem=destino" separados por vírgula
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ContentFilter guarda as regras de conteúdo aplicadas aos posts do canal.
+type ContentFilter struct {
+	ID             string    `gorm:"type:text;primaryKey" json:"id"`
 	OwnerChannelID int64     `gorm:"unique;index" json:"ownerChannelId"`
+	Enabled        bool      `gorm:"default:false" json:"enabled"`
+	Action         string    `gorm:"default:strip" json:"action"`     // strip, block ou delete
+	BannedWords    string    `gorm:"type:text" json:"bannedWords"`    // uma por linha
+	Patterns       string    `gorm:"type:text" json:"patterns"`       // uma expressão regular por linha
+	BlockedDomains string    `gorm:"type:text" json:"blockedDomains"` // separados por vírgula
+	BlockedTypes   string    `json:"blockedTypes"`                    // tipos de mensagem separados por vírgula
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AuthorSignature troca a assinatura de um admin (post.AuthorSignature) pelo
+// apelido ou emoji usado no rodapé dos posts.
+type AuthorSignature struct {
+	SignatureID    string    `gorm:"type:text;primaryKey" json:"signatureId"`
+	Signature      string    `gorm:"uniqueIndex:idx_author_signature" json:"signature"`
+	Label          string    `json:"label"`
+	OwnerChannelID int64     `gorm:"index;uniqueIndex:idx_author_signature" json:"ownerChannelId"`
 	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -135,12 +246,64 @@ type CustomCaption struct {
 	Code           string                `gorm:"index:idx_hashtag_lookup" json:"code"`
 	Caption        string                `json:"caption"`
 	LinkPreview    bool                  `json:"linkPreview"`
+	Position       string                `json:"position"`
+	Separator      string                `json:"separator"`
 	Buttons        []CustomCaptionButton `gorm:"foreignKey:OwnerCaptionID;constraint:OnDelete:CASCADE;" json:"buttons"`
 	OwnerChannelID int64                 `gorm:"index;index:idx_hashtag_lookup" json:"ownerChannelId"`
 	CreatedAt      time.Time             `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt      time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// CaptionRule escolhe uma legenda customizada por condições do post. As regras
+// do canal são avaliadas por Priority (menor primeiro); todas as condições
+// preenchidas precisam casar e, dentro de cada lista, basta um item casar.
+type CaptionRule struct {
+	RuleID         string    `gorm:"type:text;primaryKey" json:"ruleId"`
+	OwnerChannelID int64     `gorm:"index" json:"ownerChannelId"`
+	CaptionID      string    `gorm:"index" json:"captionId"`
+	Name           string    `json:"name"`
+	Priority       int       `gorm:"default:0" json:"priority"`
+	Enabled        bool      `json:"enabled"`
+	Hashtags       string    `json:"hashtags"`      // separadas por vírgula, na ordem de preferência
+	Keywords       string    `json:"keywords"`      // separadas por vírgula
+	Pattern        string    `json:"pattern"`       // expressão regular aplicada ao texto
+	MessageTypes   string    `json:"messageTypes"`  // ex: "photo,video"
+	ForwardedFrom  string    `json:"forwardedFrom"` // IDs ou @usernames da origem do encaminhamento
+	StartTime      string    `json:"startTime"`     // HH:MM
+	EndTime        string    `json:"endTime"`       // HH:MM
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CaptionVariant é uma legenda do pool de rotação do canal. Com a rotação
+// ativa, as variantes substituem a legenda padrão e, quando têm botões, os
+// botões do canal.
+type CaptionVariant struct {
+	VariantID      string                 `gorm:"type:text;primaryKey" json:"variantId"`
+	OwnerChannelID int64                  `gorm:"index" json:"ownerChannelId"`
+	Name           string                 `json:"name"`
+	Caption        string                 `json:"caption"`
+	Weight         int                    `gorm:"default:1" json:"weight"`
+	Weekdays       string                 `json:"weekdays"` // "0,6" (0 = domingo); vazio vale todos os dias
+	Enabled        bool                   `json:"enabled"`
+	UsageCount     int64                  `gorm:"default:0" json:"usageCount"`
+	LastUsedAt     *time.Time             `json:"lastUsedAt"`
+	Buttons        []CaptionVariantButton `gorm:"foreignKey:OwnerVariantID;constraint:OnDelete:CASCADE;" json:"buttons"`
+	CreatedAt      time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CaptionVariantButton struct {
+	ButtonID       string    `gorm:"type:text;primaryKey" json:"buttonId"`
+	NameButton     string    `json:"nameButton"`
+	ButtonURL      string    `json:"buttonUrl"`
+	PositionX      int       `gorm:"default:0" json:"positionX"`
+	PositionY      int       `gorm:"default:0" json:"positionY"`
+	OwnerVariantID string    `gorm:"index" json:"ownerVariantId"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
 type CustomCaptionButton struct {
 	ButtonID       string    `gorm:"type:text;primaryKey" json:"buttonId"`
 	NameButton     string    `json:"nameButton"`
@@ -158,6 +321,46 @@ type Vote struct {
 	MessageID       int    `gorm:"index:idx_vote_user,unique;index:idx_vote_count" json:"message_id"`
 	InlineMessageID string `gorm:"index:idx_vote_user,unique;index:idx_vote_count" json:"inline_message_id"`
 	UserID          int64  `gorm:"index:idx_vote_user,unique" json:"user_id"`
-	Emoji           string `gorm:"index:idx_vote_count" json:"emoji"`
+	Emoji           string `gorm:"index:idx_vote_user,unique;index:idx_vote_count" json:"emoji"`
 	CreatedAt       time.Time
 }
+
+// VoteSettings define como funcionam as votações dos botões de reação nos
+// posts do canal.
+type VoteSettings struct {
+	ID              string    `gorm:"type:text;primaryKey" json:"id"`
+	OwnerChannelID  int64     `gorm:"unique;index" json:"ownerChannelId"`
+	Mode            string    `gorm:"default:single" json:"mode"` // single ou multiple
+	HideCounts      bool      `gorm:"default:false" json:"hideCounts"`
+	DurationMinutes int       `gorm:"default:0" json:"durationMinutes"` // 0 deixa a votação sempre aberta
+	ShowResults     bool      `gorm:"default:false" json:"showResults"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// VotePoll guarda as regras de votação de um post, copiadas das
+// configurações do canal no primeiro voto. Posts sem registro seguem o padrão:
+// escolha única, contagens visíveis e sem prazo.
+type VotePoll struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	ChatID          int64      `gorm:"uniqueIndex:idx_vote_poll" json:"chat_id"`
+	MessageID       int        `gorm:"uniqueIndex:idx_vote_poll" json:"message_id"`
+	InlineMessageID string     `gorm:"uniqueIndex:idx_vote_poll" json:"inline_message_id"`
+	Mode            string     `gorm:"default:single" json:"mode"`
+	HideCounts      bool       `gorm:"default:false" json:"hideCounts"`
+	ClosesAt        *time.Time `json:"closesAt"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ReactionCount guarda a contagem de uma reação nativa em um post de canal,
+// recebida pelos updates message_reaction_count (anônimos, sem o votante).
+type ReactionCount struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ChatID    int64     `gorm:"uniqueIndex:idx_reaction_count" json:"chat_id"`
+	MessageID int       `gorm:"uniqueIndex:idx_reaction_count" json:"message_id"`
+	Emoji     string    `gorm:"uniqueIndex:idx_reaction_count" json:"emoji"`
+	Count     int64     `json:"count"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+type AuthorSignatureRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorSignatureRepository(db *gorm.DB) *AuthorSignatureRepository {
+	return &AuthorSignatureRepository{db: db}
+}
+
+func (r *AuthorSignatureRepository) ListSignatures(ctx context.Context, channelID int64) ([]models.AuthorSignature, error) {
+	var signatures []models.AuthorSignature
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", channelID).
+		Order("created_at ASC").
+		Find(&signatures).Error
+	return signatures, err
+}
+
+func (r *AuthorSignatureRepository) GetSignatureByID(ctx context.Context, channelID int64, signatureID string) (*models.AuthorSignature, error) {
+	var signature models.AuthorSignature
+	err := r.db.WithContext(ctx).
+		Where("signature_id = ? AND owner_channel_id = ?", signatureID, channelID).
+		First(&signature).Error
+	return &signature, err
+}
+
+func (r *AuthorSignatureRepository) CreateSignature(ctx context.Context, signature *models.AuthorSignature) error {
+	return r.db.WithContext(ctx).Create(signature).Error
+}
+
+func (r *AuthorSignatureRepository) SaveSignature(ctx context.Context, signature *models.AuthorSignature) error {
+	return r.db.WithContext(ctx).Save(signature).Error
+}
+
+func (r *AuthorSignatureRepository) DeleteSignature(ctx context.Context, channelID int64, signatureID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("signature_id = ? AND owner_channel_id = ?", signatureID, channelID).
+		Delete(&models.AuthorSignature{})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateFooter liga/desliga o rodapé de assinatura do canal e define o template.
+func (r *AuthorSignatureRepository) UpdateFooter(ctx context.Context, channelID int64, enabled bool, format string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Updates(map[string]any{"signature_footer": enabled, "signature_format": format})
+	return result.RowsAffected, result.Error
+}
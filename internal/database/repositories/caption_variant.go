@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+type CaptionVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewCaptionVariantRepository(db *gorm.DB) *CaptionVariantRepository {
+	return &CaptionVariantRepository{db: db}
+}
+
+func (r *CaptionVariantRepository) ListVariants(ctx context.Context, channelID int64) ([]models.CaptionVariant, error) {
+	var variants []models.CaptionVariant
+	err := r.db.WithContext(ctx).
+		Preload("Buttons", func(db *gorm.DB) *gorm.DB { return db.Order("position_y ASC, position_x ASC") }).
+		Where("owner_channel_id = ?", channelID).
+		Order("created_at ASC").
+		Find(&variants).Error
+	return variants, err
+}
+
+func (r *CaptionVariantRepository) GetVariantByID(ctx context.Context, channelID int64, variantID string) (*models.CaptionVariant, error) {
+	var variant models.CaptionVariant
+	err := r.db.WithContext(ctx).
+		Preload("Buttons").
+		Where("variant_id = ? AND owner_channel_id = ?", variantID, channelID).
+		First(&variant).Error
+	return &variant, err
+}
+
+func (r *CaptionVariantRepository) CreateVariant(ctx context.Context, variant *models.CaptionVariant) error {
+	return r.db.WithContext(ctx).Create(variant).Error
+}
+
+// SaveVariant grava a variante e substitui todos os seus botões.
+func (r *CaptionVariantRepository) SaveVariant(ctx context.Context, variant *models.CaptionVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("owner_variant_id = ?", variant.VariantID).Delete(&models.CaptionVariantButton{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(variant).Error
+	})
+}
+
+func (r *CaptionVariantRepository) DeleteVariant(ctx context.Context, channelID int64, variantID string) (int64, error) {
+	var rowsAffected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("variant_id = ? AND owner_channel_id = ?", variantID, channelID).Delete(&models.CaptionVariant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		if rowsAffected == 0 {
+			return nil
+		}
+		return tx.Where("owner_variant_id = ?", variantID).Delete(&models.CaptionVariantButton{}).Error
+	})
+	return rowsAffected, err
+}
+
+// IncrementUsage contabiliza um uso da variante para as estatísticas do pool.
+func (r *CaptionVariantRepository) IncrementUsage(ctx context.Context, variantID string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.CaptionVariant{}).
+		Where("variant_id = ?", variantID).
+		Updates(map[string]interface{}{
+			"usage_count":  gorm.Expr("usage_count + ?", 1),
+			"last_used_at": usedAt,
+		}).Error
+}
+
+func (r *CaptionVariantRepository) ResetUsage(ctx context.Context, channelID int64) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.CaptionVariant{}).
+		Where("owner_channel_id = ?", channelID).
+		Updates(map[string]interface{}{
+			"usage_count":  0,
+			"last_used_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *CaptionVariantRepository) GetRotationMode(ctx context.Context, channelID int64) (string, error) {
+	var channel models.Channel
+	err := r.db.WithContext(ctx).Select("caption_rotation").Where("id = ?", channelID).First(&channel).Error
+	return channel.CaptionRotation, err
+}
+
+func (r *CaptionVariantRepository) UpdateRotationMode(ctx context.Context, channelID int64, mode string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Update("caption_rotation", mode)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestCaptionVariantSaveAndUsage(t *testing.T) {
+	db := newTestDB(t, &models.CaptionVariant{}, &models.CaptionVariantButton{})
+
+	repo := NewCaptionVariantRepository(db)
+	ctx := context.Background()
+
+	variant := &models.CaptionVariant{
+		VariantID:      "v1",
+		OwnerChannelID: 10,
+		Caption:        "A",
+		Weight:         1,
+		Enabled:        true,
+		Buttons: []models.CaptionVariantButton{
+			{ButtonID: "b1", NameButton: "um", OwnerVariantID: "v1"},
+			{ButtonID: "b2", NameButton: "dois", OwnerVariantID: "v1"},
+		},
+	}
+	if err := repo.CreateVariant(ctx, variant); err != nil {
+		t.Fatalf("failed to create variant: %v", err)
+	}
+
+	variant.Caption = "B"
+	variant.Buttons = []models.CaptionVariantButton{{ButtonID: "b3", NameButton: "três", OwnerVariantID: "v1"}}
+	if err := repo.SaveVariant(ctx, variant); err != nil {
+		t.Fatalf("failed to save variant: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.IncrementUsage(ctx, "v1", time.Now()); err != nil {
+			t.Fatalf("failed to increment usage: %v", err)
+		}
+	}
+
+	variants, err := repo.ListVariants(ctx, 10)
+	if err != nil || len(variants) != 1 {
+		t.Fatalf("expected 1 variant, got %d (%v)", len(variants), err)
+	}
+	got := variants[0]
+	if got.Caption != "B" || len(got.Buttons) != 1 || got.Buttons[0].ButtonID != "b3" {
+		t.Fatalf("expected buttons to be replaced, got %+v", got)
+	}
+	if got.UsageCount != 2 || got.LastUsedAt == nil {
+		t.Fatalf("expected usage 2 with last use, got %d", got.UsageCount)
+	}
+
+	if rows, err := repo.DeleteVariant(ctx, 10, "v1"); err != nil || rows != 1 {
+		t.Fatalf("expected variant deleted, got %d (%v)", rows, err)
+	}
+	var buttons int64
+	db.Model(&models.CaptionVariantButton{}).Count(&buttons)
+	if buttons != 0 {
+		t.Fatalf("expected buttons deleted, got %d", buttons)
+	}
+}
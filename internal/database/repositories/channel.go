@@ -32,9 +32,17 @@ func (r *ChannelRepository) GetChannelByTwoID(ctx context.Context, userId, chann
 		Joins("DefaultCaption.MessagePermission").
 		Joins("DefaultCaption.ButtonsPermission").
 		Joins("Separator").
+		Joins("LinkSettings").
+		Joins("ContentFilter").
+		Joins("VoteSettings").
 		Preload("Buttons").
 		Preload("CustomCaptions").
 		Preload("CustomCaptions.Buttons").
+		Preload("CaptionRules", func(db *gorm.DB) *gorm.DB { return db.Order("priority ASC, created_at ASC") }).
+		Preload("CaptionVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("AuthorSignatures").
+		Preload("CaptionVariants.Buttons", func(db *gorm.DB) *gorm.DB { return db.Order("position_y ASC, position_x ASC") }).
+		Preload("SeparatorVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Where("channels.owner_id = ? AND channels.id = ?", userId, channelId).
 		First(&channel).Error
 
@@ -51,9 +59,17 @@ func (r *ChannelRepository) GetChannelByUserID(ctx context.Context, userId int64
 		Joins("DefaultCaption.MessagePermission").
 		Joins("DefaultCaption.ButtonsPermission").
 		Joins("Separator").
+		Joins("LinkSettings").
+		Joins("ContentFilter").
+		Joins("VoteSettings").
 		Preload("Buttons").
 		Preload("CustomCaptions").
 		Preload("CustomCaptions.Buttons").
+		Preload("CaptionRules", func(db *gorm.DB) *gorm.DB { return db.Order("priority ASC, created_at ASC") }).
+		Preload("CaptionVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("AuthorSignatures").
+		Preload("CaptionVariants.Buttons", func(db *gorm.DB) *gorm.DB { return db.Order("position_y ASC, position_x ASC") }).
+		Preload("SeparatorVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Where("channels.owner_id = ?", userId).
 		First(&channel).Error
 
@@ -70,10 +86,18 @@ func (r *ChannelRepository) GetChannelByID(ctx context.Context, channelId int64)
 		Joins("DefaultCaption.MessagePermission").
 		Joins("DefaultCaption.ButtonsPermission").
 		Joins("Separator").
+		Joins("LinkSettings").
+		Joins("ContentFilter").
+		Joins("VoteSettings").
 		Preload("Owner").
 		Preload("Buttons").
 		Preload("CustomCaptions").
 		Preload("CustomCaptions.Buttons").
+		Preload("CaptionRules", func(db *gorm.DB) *gorm.DB { return db.Order("priority ASC, created_at ASC") }).
+		Preload("CaptionVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("AuthorSignatures").
+		Preload("CaptionVariants.Buttons", func(db *gorm.DB) *gorm.DB { return db.Order("position_y ASC, position_x ASC") }).
+		Preload("SeparatorVariants", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Where("channels.id = ?", channelId).
 		First(&channel).Error
 
@@ -140,6 +164,47 @@ func (r *ChannelRepository) DeleteChannelWithRelations(ctx context.Context, user
 		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.Separator{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.SeparatorVariant{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.LinkSettings{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.ContentFilter{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.AuthorSignature{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.VoteSettings{}).Error; err != nil {
+			return err
+		}
+
+		// Cancelar agendamentos que ainda não saíram, senão o dispatcher tenta publicar no canal removido
+		if err := tx.Model(&models.ScheduledPost{}).
+			Where("channel_id = ? AND status IN ?", channelId, []string{ScheduledPostStatusPending, ScheduledPostStatusSending}).
+			Update("status", ScheduledPostStatusCanceled).Error; err != nil {
+			return err
+		}
+
+		// Limpar regras de votação dos posts
+		if err := tx.Where("chat_id = ?", channelId).Delete(&models.VotePoll{}).Error; err != nil {
+			return err
+		}
+
+		// Limpar contagens de reações nativas
+		if err := tx.Where("chat_id = ?", channelId).Delete(&models.ReactionCount{}).Error; err != nil {
+			return err
+		}
+
+		// Limpar jobs da fila persistente do canal
+		if err := tx.Where("channel_id = ?", channelId).Delete(&models.QueueJob{}).Error; err != nil {
+			return err
+		}
 
 		// Limpar Custom Captions e seus botões
 		var customCaptions []models.CustomCaption
@@ -154,6 +219,18 @@ func (r *ChannelRepository) DeleteChannelWithRelations(ctx context.Context, user
 			}
 		}
 
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.CaptionRule{}).Error; err != nil {
+			return err
+		}
+
+		// Limpar pool de legendas e seus botões
+		if err := tx.Where("owner_variant_id IN (?)", tx.Model(&models.CaptionVariant{}).Select("variant_id").Where("owner_channel_id = ?", channelId)).Delete(&models.CaptionVariantButton{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("owner_channel_id = ?", channelId).Delete(&models.CaptionVariant{}).Error; err != nil {
+			return err
+		}
+
 		// Limpar Default Caption e suas permissões
 		var defaultCaption models.DefaultCaption
 		if err := tx.Where("owner_channel_id = ?", channelId).First(&defaultCaption).Error; err == nil {
@@ -258,6 +335,41 @@ func (r *ChannelRepository) UpdateReactionPosition(ctx context.Context, channelI
 	return result.RowsAffected, result.Error
 }
 
+func (r *ChannelRepository) UpdateReactionMode(ctx context.Context, channelID int64, mode string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Update("reaction_mode", mode)
+	return result.RowsAffected, result.Error
+}
+
+func (r *ChannelRepository) UpdateProcessEdits(ctx context.Context, channelID int64, enabled bool) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Update("process_edits", enabled)
+	return result.RowsAffected, result.Error
+}
+
+func (r *ChannelRepository) UpdateCaptionLayout(ctx context.Context, channelID int64, position, separator string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Updates(map[string]any{"caption_position": position, "caption_separator": separator})
+	return result.RowsAffected, result.Error
+}
+
+func (r *ChannelRepository) UpdateCaptionOverflow(ctx context.Context, channelID int64, policy string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Update("caption_overflow", policy)
+	return result.RowsAffected, result.Error
+}
+
+func (r *ChannelRepository) UpdateTranslation(ctx context.Context, channelID int64, language, mode string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Channel{}).
+		Where("id = ?", channelID).
+		Updates(map[string]any{"translate_language": language, "translate_mode": mode})
+	return result.RowsAffected, result.Error
+}
+
 func (r *ChannelRepository) UpdateDynamicLinks(ctx context.Context, channelID int64, settings map[string]any) (int64, error) {
 	result := r.db.WithContext(ctx).Model(&models.Channel{}).
 		Where("id = ?", channelID).
@@ -10,18 +10,19 @@ import (
 )
 
 type ChannelEventFilters struct {
-	ChannelID int64
-	OwnerID   int64
-	ActorID   int64
-	Source    string
-	EventType string
-	Status    string
-	SessionID string
-	Query     string
-	DateFrom  *time.Time
-	DateTo    *time.Time
-	Limit     int
-	Offset    int
+	ChannelID     int64
+	OwnerID       int64
+	ActorID       int64
+	Source        string
+	EventType     string
+	Status        string
+	SessionID     string
+	CorrelationID string
+	Query         string
+	DateFrom      *time.Time
+	DateTo        *time.Time
+	Limit         int
+	Offset        int
 }
 
 type ChannelEventRepository struct {
@@ -85,6 +86,9 @@ func (r *ChannelEventRepository) applyFilters(query *gorm.DB, filters ChannelEve
 	if filters.SessionID != "" {
 		query = query.Where("session_id = ?", filters.SessionID)
 	}
+	if filters.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", filters.CorrelationID)
+	}
 	if filters.DateFrom != nil {
 		query = query.Where("created_at >= ?", *filters.DateFrom)
 	}
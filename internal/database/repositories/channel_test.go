@@ -28,8 +28,20 @@ func TestDeleteChannelWithRelations(t *testing.T) {
 		&models.ButtonsPermission{},
 		&models.Button{},
 		&models.Separator{},
+		&models.SeparatorVariant{},
+		&models.LinkSettings{},
+		&models.ContentFilter{},
+		&models.VoteSettings{},
 		&models.CustomCaption{},
 		&models.CustomCaptionButton{},
+		&models.CaptionRule{},
+		&models.CaptionVariant{},
+		&models.AuthorSignature{},
+		&models.CaptionVariantButton{},
+		&models.ScheduledPost{},
+		&models.VotePoll{},
+		&models.ReactionCount{},
+		&models.QueueJob{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate: %v", err)
@@ -69,6 +81,22 @@ func TestDeleteChannelWithRelations(t *testing.T) {
 		t.Fatalf("failed to create channel: %v", err)
 	}
 
+	if err := db.Create(&models.ScheduledPost{ID: "sp1", ChannelID: channelID, Status: ScheduledPostStatusPending}).Error; err != nil {
+		t.Fatalf("failed to create scheduled post: %v", err)
+	}
+
+	if err := db.Create(&models.VotePoll{ChatID: channelID, MessageID: 1}).Error; err != nil {
+		t.Fatalf("failed to create vote poll: %v", err)
+	}
+
+	if err := db.Create(&models.ReactionCount{ChatID: channelID, MessageID: 1, Emoji: "👍", Count: 3}).Error; err != nil {
+		t.Fatalf("failed to create reaction count: %v", err)
+	}
+
+	if err := db.Create(&models.QueueJob{ID: "job1", ChannelID: channelID, Status: QueueJobStatusPending}).Error; err != nil {
+		t.Fatalf("failed to create queue job: %v", err)
+	}
+
 	// Verify it exists
 	var count int64
 	db.Model(&models.Channel{}).Count(&count)
@@ -97,4 +125,22 @@ func TestDeleteChannelWithRelations(t *testing.T) {
 	if count != 0 {
 		t.Errorf("expected 0 buttons, got %d", count)
 	}
+
+	var post models.ScheduledPost
+	db.First(&post, "id = ?", "sp1")
+	if post.Status != ScheduledPostStatusCanceled {
+		t.Errorf("expected scheduled post to be canceled, got %q", post.Status)
+	}
+	db.Model(&models.VotePoll{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected 0 vote polls, got %d", count)
+	}
+	db.Model(&models.ReactionCount{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected 0 reaction counts, got %d", count)
+	}
+	db.Model(&models.QueueJob{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected 0 queue jobs, got %d", count)
+	}
 }
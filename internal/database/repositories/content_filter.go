@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContentFilterRepository struct {
+	db *gorm.DB
+}
+
+func NewContentFilterRepository(db *gorm.DB) *ContentFilterRepository {
+	return &ContentFilterRepository{db: db}
+}
+
+func (r *ContentFilterRepository) GetByOwnerChannelID(ctx context.Context, ownerChannelID int64) (*models.ContentFilter, error) {
+	var filter models.ContentFilter
+
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", ownerChannelID).
+		First(&filter).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+func (r *ContentFilterRepository) Save(ctx context.Context, filter *models.ContentFilter) error {
+	if filter.ID == "" {
+		filter.ID = uuid.NewString()
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "owner_channel_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"enabled", "action", "banned_words", "patterns", "blocked_domains", "blocked_types", "updated_at",
+			}),
+		}).
+		Create(filter).Error
+}
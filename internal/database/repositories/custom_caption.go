@@ -87,3 +87,43 @@ func (r *CustomCaptionRepository) UpdateCustomCaptionLayout(ctx context.Context,
 		return nil
 	})
 }
+
+func (r *CustomCaptionRepository) ListCaptionRules(ctx context.Context, channelID int64) ([]models.CaptionRule, error) {
+	var rules []models.CaptionRule
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", channelID).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *CustomCaptionRepository) CreateCaptionRule(ctx context.Context, rule *models.CaptionRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *CustomCaptionRepository) GetCaptionRuleByID(ctx context.Context, channelID int64, ruleID string) (*models.CaptionRule, error) {
+	var rule models.CaptionRule
+	err := r.db.WithContext(ctx).
+		Where("rule_id = ? AND owner_channel_id = ?", ruleID, channelID).
+		First(&rule).Error
+	return &rule, err
+}
+
+// SaveCaptionRule grava todos os campos da regra, inclusive valores zerados
+// (condições removidas, regra desativada).
+func (r *CustomCaptionRepository) SaveCaptionRule(ctx context.Context, rule *models.CaptionRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *CustomCaptionRepository) DeleteCaptionRule(ctx context.Context, channelID int64, ruleID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("rule_id = ? AND owner_channel_id = ?", ruleID, channelID).
+		Delete(&models.CaptionRule{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *CustomCaptionRepository) DeleteCaptionRulesByCaptionID(ctx context.Context, channelID int64, captionID string) error {
+	return r.db.WithContext(ctx).
+		Where("caption_id = ? AND owner_channel_id = ?", captionID, channelID).
+		Delete(&models.CaptionRule{}).Error
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LinkSettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewLinkSettingsRepository(db *gorm.DB) *LinkSettingsRepository {
+	return &LinkSettingsRepository{db: db}
+}
+
+func (r *LinkSettingsRepository) GetByOwnerChannelID(ctx context.Context, ownerChannelID int64) (*models.LinkSettings, error) {
+	var settings models.LinkSettings
+
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", ownerChannelID).
+		First(&settings).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *LinkSettingsRepository) Save(ctx context.Context, settings *models.LinkSettings) error {
+	if settings.ID == "" {
+		settings.ID = uuid.NewString()
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "owner_channel_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"enabled", "utm_source", "utm_medium", "utm_campaign", "utm_content",
+				"allow_domains", "deny_domains", "rewrites", "updated_at",
+			}),
+		}).
+		Create(settings).Error
+}
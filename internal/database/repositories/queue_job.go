@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+const (
+	QueueJobStatusPending    = "pending"
+	QueueJobStatusProcessing = "processing"
+	QueueJobStatusDead       = "dead"
+)
+
+type QueueJobFilters struct {
+	Queue     string
+	ChannelID int64
+	Limit     int
+	Offset    int
+}
+
+type QueueJobRepository struct {
+	db *gorm.DB
+}
+
+func NewQueueJobRepository(db *gorm.DB) *QueueJobRepository {
+	return &QueueJobRepository{db: db}
+}
+
+func (r *QueueJobRepository) Create(ctx context.Context, job *models.QueueJob) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.Status == "" {
+		job.Status = QueueJobStatusPending
+	}
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *QueueJobRepository) GetByID(ctx context.Context, id string) (*models.QueueJob, error) {
+	var job models.QueueJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Claim marca o job como em processamento com um token novo e devolve a linha
+// atualizada. Retorna nil quando outro worker já assumiu o job.
+func (r *QueueJobRepository) Claim(ctx context.Context, id string) (*models.QueueJob, error) {
+	token := uuid.NewString()
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ?", id, QueueJobStatusPending).
+		Updates(map[string]any{
+			"status":      QueueJobStatusProcessing,
+			"attempts":    gorm.Expr("attempts + 1"),
+			"claim_token": token,
+		})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return nil, result.Error
+	}
+
+	var job models.QueueJob
+	err := r.db.WithContext(ctx).Where("id = ? AND claim_token = ?", id, token).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Ack remove o job concluído da fila. Só afeta o job ainda em processamento
+// pela execução dona do token; retorna 0 quando ele foi liberado e assumido
+// por outra.
+func (r *QueueJobRepository) Ack(ctx context.Context, id, token string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND status = ? AND claim_token = ?", id, QueueJobStatusProcessing, token).
+		Delete(&models.QueueJob{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *QueueJobRepository) ScheduleRetry(ctx context.Context, id, token string, nextAttemptAt time.Time, lastError string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, QueueJobStatusProcessing, token).
+		Updates(map[string]any{
+			"status":          QueueJobStatusPending,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+			"claim_token":     "",
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *QueueJobRepository) MarkDead(ctx context.Context, id, token string, lastError string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, QueueJobStatusProcessing, token).
+		Updates(map[string]any{
+			"status":      QueueJobStatusDead,
+			"last_error":  lastError,
+			"claim_token": "",
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Touch renova o updated_at do job em processamento para o ReleaseProcessing
+// não o tratar como abandonado enquanto a execução dona do token roda.
+func (r *QueueJobRepository) Touch(ctx context.Context, id, token string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, QueueJobStatusProcessing, token).
+		Update("updated_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// SaveCompletedSteps grava os passos não repetíveis já concluídos (separados
+// por vírgula) do job em processamento pela execução dona do token, para uma
+// nova tentativa não enviá-los de novo.
+func (r *QueueJobRepository) SaveCompletedSteps(ctx context.Context, id, token, steps string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, QueueJobStatusProcessing, token).
+		Update("completed_steps", steps)
+	return result.RowsAffected, result.Error
+}
+
+// ListReady retorna jobs pendentes cujo próximo horário de tentativa já passou.
+func (r *QueueJobRepository) ListReady(ctx context.Context, queue string, now time.Time, limit int) ([]models.QueueJob, error) {
+	var jobs []models.QueueJob
+	err := r.db.WithContext(ctx).
+		Where("queue = ? AND status = ? AND next_attempt_at <= ?", queue, QueueJobStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// ReleaseProcessing devolve para pending os jobs em processamento sem
+// atualização desde staleBefore, abandonados por um processo encerrado.
+func (r *QueueJobRepository) ReleaseProcessing(ctx context.Context, queue string, staleBefore, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("queue = ? AND status = ? AND updated_at < ?", queue, QueueJobStatusProcessing, staleBefore).
+		Updates(map[string]any{
+			"status":          QueueJobStatusPending,
+			"next_attempt_at": now,
+			"claim_token":     "",
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *QueueJobRepository) ListDead(ctx context.Context, filters QueueJobFilters) ([]models.QueueJob, int64, error) {
+	var jobs []models.QueueJob
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.QueueJob{}).Where("status = ?", QueueJobStatusDead)
+	if filters.Queue != "" {
+		query = query.Where("queue = ?", filters.Queue)
+	}
+	if filters.ChannelID != 0 {
+		query = query.Where("channel_id = ?", filters.ChannelID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	limit := filters.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	offset := filters.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	err := query.Order("updated_at DESC").Limit(limit).Offset(offset).Find(&jobs).Error
+	return jobs, total, err
+}
+
+// Requeue devolve um job da dead-letter para a fila, zerando as tentativas.
+func (r *QueueJobRepository) Requeue(ctx context.Context, id string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.QueueJob{}).
+		Where("id = ? AND status = ?", id, QueueJobStatusDead).
+		Updates(map[string]any{
+			"status":          QueueJobStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *QueueJobRepository) DeleteDead(ctx context.Context, id string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND status = ?", id, QueueJobStatusDead).
+		Delete(&models.QueueJob{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestQueueJobLifecycle(t *testing.T) {
+	db := newTestDB(t, &models.QueueJob{})
+
+	repo := NewQueueJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	ready := &models.QueueJob{Queue: "channel_post", ChannelID: 10, Payload: "{}", NextAttemptAt: now.Add(-time.Second)}
+	waiting := &models.QueueJob{Queue: "channel_post", ChannelID: 10, Payload: "{}", NextAttemptAt: now.Add(time.Hour)}
+	for _, job := range []*models.QueueJob{ready, waiting} {
+		if err := repo.Create(ctx, job); err != nil {
+			t.Fatalf("failed to create job: %v", err)
+		}
+	}
+
+	jobs, err := repo.ListReady(ctx, "channel_post", now, 10)
+	if err != nil {
+		t.Fatalf("ListReady failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != ready.ID {
+		t.Fatalf("expected only the ready job, got %d jobs", len(jobs))
+	}
+
+	claimed, err := repo.Claim(ctx, ready.ID)
+	if err != nil || claimed == nil || claimed.Attempts != 1 || claimed.ClaimToken == "" {
+		t.Fatalf("expected first claim to succeed, got claimed=%+v err=%v", claimed, err)
+	}
+	if again, err := repo.Claim(ctx, ready.ID); err != nil || again != nil {
+		t.Fatalf("expected second claim to be rejected, got claimed=%+v err=%v", again, err)
+	}
+
+	// Um job em processamento recente pertence a outro worker e não é liberado.
+	released, err := repo.ReleaseProcessing(ctx, "channel_post", now.Add(-time.Minute), now)
+	if err != nil || released != 0 {
+		t.Fatalf("expected no job released, got %d err=%v", released, err)
+	}
+
+	// Um job parado há mais tempo que o limite volta para a fila.
+	released, err = repo.ReleaseProcessing(ctx, "channel_post", now.Add(time.Minute), now)
+	if err != nil || released != 1 {
+		t.Fatalf("expected 1 job released, got %d err=%v", released, err)
+	}
+
+	// Depois de liberado e assumido de novo, a execução antiga não decide mais
+	// o destino do job.
+	reclaimed, err := repo.Claim(ctx, ready.ID)
+	if err != nil || reclaimed == nil || reclaimed.Attempts != 2 {
+		t.Fatalf("expected reclaim to succeed, got claimed=%+v err=%v", reclaimed, err)
+	}
+	if rows, _ := repo.Ack(ctx, ready.ID, claimed.ClaimToken); rows != 0 {
+		t.Fatalf("expected stale ack to affect 0 rows, got %d", rows)
+	}
+	if rows, _ := repo.ScheduleRetry(ctx, ready.ID, claimed.ClaimToken, now.Add(time.Minute), "stale"); rows != 0 {
+		t.Fatalf("expected stale retry to affect 0 rows, got %d", rows)
+	}
+
+	if rows, err := repo.ScheduleRetry(ctx, ready.ID, reclaimed.ClaimToken, now.Add(time.Minute), "timeout"); err != nil || rows != 1 {
+		t.Fatalf("ScheduleRetry failed: rows=%d err=%v", rows, err)
+	}
+	if jobs, _ := repo.ListReady(ctx, "channel_post", now, 10); len(jobs) != 0 {
+		t.Errorf("expected no ready jobs after retry was scheduled, got %d", len(jobs))
+	}
+
+	claimed, _ = repo.Claim(ctx, ready.ID)
+	if rows, err := repo.MarkDead(ctx, ready.ID, claimed.ClaimToken, "forbidden"); err != nil || rows != 1 {
+		t.Fatalf("MarkDead failed: rows=%d err=%v", rows, err)
+	}
+	dead, total, err := repo.ListDead(ctx, QueueJobFilters{ChannelID: 10})
+	if err != nil || total != 1 || len(dead) != 1 || dead[0].LastError != "forbidden" {
+		t.Fatalf("expected 1 dead job, got total=%d err=%v", total, err)
+	}
+
+	// Só jobs na dead-letter podem ser reenfileirados ou removidos.
+	if rows, _ := repo.Requeue(ctx, waiting.ID, now); rows != 0 {
+		t.Errorf("expected requeue of a pending job to affect 0 rows, got %d", rows)
+	}
+	if rows, _ := repo.Requeue(ctx, ready.ID, now); rows != 1 {
+		t.Fatalf("expected requeue to affect 1 row, got %d", rows)
+	}
+	stored, _ := repo.GetByID(ctx, ready.ID)
+	if stored.Status != QueueJobStatusPending || stored.Attempts != 0 {
+		t.Errorf("expected requeued job pending with 0 attempts, got status=%s attempts=%d", stored.Status, stored.Attempts)
+	}
+
+	if rows, _ := repo.DeleteDead(ctx, ready.ID); rows != 0 {
+		t.Errorf("expected delete of a pending job to affect 0 rows, got %d", rows)
+	}
+	claimed, _ = repo.Claim(ctx, ready.ID)
+	if rows, err := repo.Ack(ctx, ready.ID, claimed.ClaimToken); err != nil || rows != 1 {
+		t.Fatalf("Ack failed: rows=%d err=%v", rows, err)
+	}
+	if stored, _ := repo.GetByID(ctx, ready.ID); stored != nil {
+		t.Errorf("expected acked job to be deleted")
+	}
+}
+
+// Um job que espera na fila em memória além do timeout (canal com flood wait)
+// continua pendente: só é assumido pelo worker logo antes de rodar, então
+// ReleaseProcessing não o devolve e uma segunda cópia não consegue executá-lo.
+func TestQueueJobHeldInMemoryPastTimeout(t *testing.T) {
+	db := newTestDB(t, &models.QueueJob{})
+
+	repo := NewQueueJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	job := &models.QueueJob{Queue: "channel_post", ChannelID: 10, Payload: "{}", NextAttemptAt: now.Add(-time.Second)}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+
+	// O poller lista o job e o mantém em memória sem assumi-lo.
+	if jobs, _ := repo.ListReady(ctx, "channel_post", now, 10); len(jobs) != 1 {
+		t.Fatalf("expected the job to be ready, got %d", len(jobs))
+	}
+
+	// Passado o timeout, nada é liberado: o job nunca saiu de pending.
+	later := now.Add(10 * time.Minute)
+	released, err := repo.ReleaseProcessing(ctx, "channel_post", later.Add(-5*time.Minute), later)
+	if err != nil || released != 0 {
+		t.Fatalf("expected no job released, got %d err=%v", released, err)
+	}
+
+	// O worker assume o job logo antes de rodar; uma cópia empurrada por outro
+	// ciclo do poller é recusada.
+	claimed, err := repo.Claim(ctx, job.ID)
+	if err != nil || claimed == nil {
+		t.Fatalf("expected worker claim to succeed, got claimed=%+v err=%v", claimed, err)
+	}
+	if again, err := repo.Claim(ctx, job.ID); err != nil || again != nil {
+		t.Fatalf("expected duplicate claim to be rejected, got claimed=%+v err=%v", again, err)
+	}
+}
+
+// Um job lento renovado pelo heartbeat não é liberado como abandonado.
+func TestQueueJobTouchKeepsSlowJob(t *testing.T) {
+	db := newTestDB(t, &models.QueueJob{})
+
+	repo := NewQueueJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	job := &models.QueueJob{Queue: "channel_post", ChannelID: 10, Payload: "{}", NextAttemptAt: now.Add(-time.Second)}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	claimed, err := repo.Claim(ctx, job.ID)
+	if err != nil || claimed == nil {
+		t.Fatalf("expected claim to succeed, got claimed=%+v err=%v", claimed, err)
+	}
+	db.Model(&models.QueueJob{}).Where("id = ?", job.ID).UpdateColumn("updated_at", now.Add(-10*time.Minute))
+
+	if rows, err := repo.Touch(ctx, job.ID, claimed.ClaimToken, now); err != nil || rows != 1 {
+		t.Fatalf("Touch failed: rows=%d err=%v", rows, err)
+	}
+	released, err := repo.ReleaseProcessing(ctx, "channel_post", now.Add(-5*time.Minute), now)
+	if err != nil || released != 0 {
+		t.Fatalf("expected touched job to stay in processing, got %d released err=%v", released, err)
+	}
+
+	if rows, _ := repo.Touch(ctx, job.ID, "stale", now); rows != 0 {
+		t.Fatalf("expected touch with a stale token to affect 0 rows, got %d", rows)
+	}
+}
+
+// Passos já concluídos continuam no job na próxima tentativa.
+func TestQueueJobCompletedStepsSurviveRetry(t *testing.T) {
+	db := newTestDB(t, &models.QueueJob{})
+
+	repo := NewQueueJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	job := &models.QueueJob{Queue: "channel_post", ChannelID: 10, Payload: "{}", NextAttemptAt: now.Add(-time.Second)}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	claimed, _ := repo.Claim(ctx, job.ID)
+	if rows, err := repo.SaveCompletedSteps(ctx, job.ID, claimed.ClaimToken, "overflow_reply"); err != nil || rows != 1 {
+		t.Fatalf("SaveCompletedSteps failed: rows=%d err=%v", rows, err)
+	}
+	if rows, _ := repo.SaveCompletedSteps(ctx, job.ID, "stale", "resend:1"); rows != 0 {
+		t.Fatalf("expected stale token to affect 0 rows, got %d", rows)
+	}
+	if _, err := repo.ScheduleRetry(ctx, job.ID, claimed.ClaimToken, now.Add(-time.Second), "timeout"); err != nil {
+		t.Fatalf("ScheduleRetry failed: %v", err)
+	}
+
+	retried, err := repo.Claim(ctx, job.ID)
+	if err != nil || retried == nil || retried.CompletedSteps != "overflow_reply" {
+		t.Fatalf("expected completed steps on the retry, got claimed=%+v err=%v", retried, err)
+	}
+}
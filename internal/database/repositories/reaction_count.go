@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionCountRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionCountRepository(db *gorm.DB) *ReactionCountRepository {
+	return &ReactionCountRepository{db: db}
+}
+
+// ReplaceCounts grava as contagens atuais das reações de um post. Emojis que
+// sumiram do post são apagados; os demais mantêm a data da primeira reação.
+func (r *ReactionCountRepository) ReplaceCounts(ctx context.Context, chatID int64, messageID int, counts map[string]int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		emojis := make([]string, 0, len(counts))
+		rows := make([]models.ReactionCount, 0, len(counts))
+		for emoji, count := range counts {
+			emojis = append(emojis, emoji)
+			rows = append(rows, models.ReactionCount{ChatID: chatID, MessageID: messageID, Emoji: emoji, Count: count})
+		}
+
+		stale := tx.Where("chat_id = ? AND message_id = ?", chatID, messageID)
+		if len(emojis) > 0 {
+			stale = stale.Where("emoji NOT IN ?", emojis)
+		}
+		if err := stale.Delete(&models.ReactionCount{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chat_id"}, {Name: "message_id"}, {Name: "emoji"}},
+			DoUpdates: clause.AssignmentColumns([]string{"count", "updated_at"}),
+		}).Create(&rows).Error
+	})
+}
+
+// channelCounts filtra as contagens dos posts do canal cuja primeira reação
+// foi recebida desde since.
+func (r *ReactionCountRepository) channelCounts(ctx context.Context, chatID int64, since time.Time) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.ReactionCount{}).Where("chat_id = ?", chatID)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	return query
+}
+
+// CountChannelReactionsByPostEmoji lista as contagens do canal por post e emoji.
+func (r *ReactionCountRepository) CountChannelReactionsByPostEmoji(ctx context.Context, chatID int64, since time.Time) ([]PostVoteCount, error) {
+	var results []PostVoteCount
+	err := r.channelCounts(ctx, chatID, since).
+		Select("message_id, emoji, count").
+		Order("message_id desc, count desc, emoji").
+		Scan(&results).Error
+	return results, err
+}
+
+// ListChannelCounts retorna as contagens do canal com a data da primeira reação.
+func (r *ReactionCountRepository) ListChannelCounts(ctx context.Context, chatID int64, since time.Time) ([]models.ReactionCount, error) {
+	var counts []models.ReactionCount
+	err := r.channelCounts(ctx, chatID, since).Find(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestReactionCountReplace(t *testing.T) {
+	db := newTestDB(t, &models.ReactionCount{})
+
+	repo := NewReactionCountRepository(db)
+	ctx := context.Background()
+
+	if err := repo.ReplaceCounts(ctx, -1001, 7, map[string]int64{"👍": 3, "❤": 1}); err != nil {
+		t.Fatalf("failed to replace counts: %v", err)
+	}
+	if err := repo.ReplaceCounts(ctx, -1001, 7, map[string]int64{"👍": 5, "🔥": 2}); err != nil {
+		t.Fatalf("failed to replace counts: %v", err)
+	}
+	if err := repo.ReplaceCounts(ctx, -1001, 8, map[string]int64{"👍": 1}); err != nil {
+		t.Fatalf("failed to replace counts: %v", err)
+	}
+
+	counts, err := repo.ListChannelCounts(ctx, -1001, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to list counts: %v", err)
+	}
+	if len(counts) != 3 {
+		t.Fatalf("expected 3 counts after replace, got %+v", counts)
+	}
+
+	if err := repo.ReplaceCounts(ctx, -1001, 8, nil); err != nil {
+		t.Fatalf("failed to clear counts: %v", err)
+	}
+	rows, err := repo.CountChannelReactionsByPostEmoji(ctx, -1001, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to list counts: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Emoji != "👍" || rows[0].Count != 5 {
+		t.Fatalf("unexpected counts: %+v", rows)
+	}
+}
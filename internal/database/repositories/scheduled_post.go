@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduledPostStatusPending  = "pending"
+	ScheduledPostStatusSending  = "sending"
+	ScheduledPostStatusSent     = "sent"
+	ScheduledPostStatusFailed   = "failed"
+	ScheduledPostStatusCanceled = "canceled"
+)
+
+type ScheduledPostRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduledPostRepository(db *gorm.DB) *ScheduledPostRepository {
+	return &ScheduledPostRepository{db: db}
+}
+
+func (r *ScheduledPostRepository) Create(ctx context.Context, post *models.ScheduledPost) error {
+	if post.ID == "" {
+		post.ID = uuid.NewString()
+	}
+	if post.Status == "" {
+		post.Status = ScheduledPostStatusPending
+	}
+	return r.db.WithContext(ctx).Create(post).Error
+}
+
+func (r *ScheduledPostRepository) GetByID(ctx context.Context, id string) (*models.ScheduledPost, error) {
+	var post models.ScheduledPost
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&post).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *ScheduledPostRepository) ListByChannel(ctx context.Context, channelID int64, status string) ([]models.ScheduledPost, error) {
+	var posts []models.ScheduledPost
+	query := r.db.WithContext(ctx).Where("channel_id = ?", channelID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("scheduled_at ASC").Limit(100).Find(&posts).Error
+	return posts, err
+}
+
+func (r *ScheduledPostRepository) ListPendingByCreator(ctx context.Context, userID int64) ([]models.ScheduledPost, error) {
+	var posts []models.ScheduledPost
+	err := r.db.WithContext(ctx).
+		Where("created_by = ? AND status = ?", userID, ScheduledPostStatusPending).
+		Order("scheduled_at ASC").
+		Limit(50).
+		Find(&posts).Error
+	return posts, err
+}
+
+// ListDue retorna os agendamentos pendentes cujo horário já passou.
+func (r *ScheduledPostRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.ScheduledPost, error) {
+	var posts []models.ScheduledPost
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", ScheduledPostStatusPending, now).
+		Order("scheduled_at ASC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
+
+// Claim move o agendamento de pending para sending. Retorna false quando outro
+// processo já assumiu o envio ou quando ele foi cancelado nesse meio tempo.
+func (r *ScheduledPostRepository) Claim(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledPost{}).
+		Where("id = ? AND status = ?", id, ScheduledPostStatusPending).
+		Updates(map[string]any{
+			"status":   ScheduledPostStatusSending,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ScheduledPostRepository) MarkSent(ctx context.Context, id string, messageID int, sentAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ScheduledPost{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          ScheduledPostStatusSent,
+			"sent_message_id": messageID,
+			"sent_at":         sentAt,
+			"error_message":   "",
+		}).Error
+}
+
+func (r *ScheduledPostRepository) MarkFailed(ctx context.Context, id string, errorMessage string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ScheduledPost{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":        ScheduledPostStatusFailed,
+			"error_message": errorMessage,
+		}).Error
+}
+
+// Cancel só afeta agendamentos ainda pendentes do canal informado.
+func (r *ScheduledPostRepository) Cancel(ctx context.Context, channelID int64, id string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledPost{}).
+		Where("id = ? AND channel_id = ? AND status = ?", id, channelID, ScheduledPostStatusPending).
+		Update("status", ScheduledPostStatusCanceled)
+	return result.RowsAffected, result.Error
+}
+
+// ReleaseStale devolve para a fila os envios que ficaram presos em sending,
+// por exemplo quando o processo caiu no meio do envio.
+func (r *ScheduledPostRepository) ReleaseStale(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ScheduledPost{}).
+		Where("status = ? AND updated_at < ?", ScheduledPostStatusSending, cutoff).
+		Update("status", ScheduledPostStatusPending)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestScheduledPostClaimAndCancel(t *testing.T) {
+	db := newTestDB(t, &models.ScheduledPost{})
+
+	repo := NewScheduledPostRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	due := &models.ScheduledPost{ChannelID: 10, Payload: "{}", ScheduledAt: now.Add(-time.Minute)}
+	later := &models.ScheduledPost{ChannelID: 10, Payload: "{}", ScheduledAt: now.Add(time.Hour)}
+	for _, post := range []*models.ScheduledPost{due, later} {
+		if err := repo.Create(ctx, post); err != nil {
+			t.Fatalf("failed to create scheduled post: %v", err)
+		}
+	}
+
+	posts, err := repo.ListDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("ListDue failed: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != due.ID {
+		t.Fatalf("expected only the due post, got %d posts", len(posts))
+	}
+
+	claimed, err := repo.Claim(ctx, due.ID)
+	if err != nil || !claimed {
+		t.Fatalf("expected first claim to succeed, got claimed=%v err=%v", claimed, err)
+	}
+	claimed, err = repo.Claim(ctx, due.ID)
+	if err != nil || claimed {
+		t.Fatalf("expected second claim to be rejected, got claimed=%v err=%v", claimed, err)
+	}
+
+	// Um post já em envio não pode ser cancelado.
+	if rows, _ := repo.Cancel(ctx, 10, due.ID); rows != 0 {
+		t.Errorf("expected cancel of a sending post to affect 0 rows, got %d", rows)
+	}
+	// O canal precisa bater.
+	if rows, _ := repo.Cancel(ctx, 99, later.ID); rows != 0 {
+		t.Errorf("expected cancel with wrong channel to affect 0 rows, got %d", rows)
+	}
+	if rows, _ := repo.Cancel(ctx, 10, later.ID); rows != 1 {
+		t.Errorf("expected cancel to affect 1 row, got %d", rows)
+	}
+
+	released, err := repo.ReleaseStale(ctx, time.Now().Add(time.Minute))
+	if err != nil || released != 1 {
+		t.Fatalf("expected 1 stale post released, got %d err=%v", released, err)
+	}
+	stored, _ := repo.GetByID(ctx, due.ID)
+	if stored.Status != ScheduledPostStatusPending || stored.Attempts != 1 {
+		t.Errorf("expected released post pending with 1 attempt, got status=%s attempts=%d", stored.Status, stored.Attempts)
+	}
+}
@@ -50,20 +50,34 @@ func (r *SeparatorRepository) SaveSeparator(ctx context.Context, separator *mode
 	return err
 }
 
+// DeleteSeparatorByOwnerChannelId remove o sticker principal e as variantes do canal.
 func (r *SeparatorRepository) DeleteSeparatorByOwnerChannelId(ctx context.Context, channelID int64) error {
-	result := r.db.WithContext(ctx).
-		Where("owner_channel_id = ?", channelID).
-		Delete(&models.Separator{})
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("owner_channel_id = ?", channelID).
+			Delete(&models.Separator{})
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return errors.New("separator not found")
-	}
+		if result.RowsAffected == 0 {
+			return errors.New("separator not found")
+		}
+
+		return tx.Where("owner_channel_id = ?", channelID).Delete(&models.SeparatorVariant{}).Error
+	})
+}
 
-	return nil
+// UpdateSettings grava o modo de rodízio e o intervalo de silêncio do separador.
+func (r *SeparatorRepository) UpdateSettings(ctx context.Context, channelID int64, rotation string, cooldownMinutes int) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Separator{}).
+		Where("owner_channel_id = ?", channelID).
+		Updates(map[string]interface{}{
+			"rotation":         rotation,
+			"cooldown_minutes": cooldownMinutes,
+		})
+	return result.RowsAffected, result.Error
 }
 
 func (r *SeparatorRepository) GetSeparatorByTwoID(ctx context.Context, channelID int64, separatorID string) (*models.Separator, error) {
@@ -73,6 +87,19 @@ func (r *SeparatorRepository) GetSeparatorByTwoID(ctx context.Context, channelID
 		Where("owner_channel_id = ? and separator_id = ?", channelID, separatorID).
 		First(&separator).Error
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Os stickers das variantes também são servidos pelo mesmo endereço.
+		var variant models.SeparatorVariant
+		err = r.db.WithContext(ctx).
+			Where("owner_channel_id = ? and separator_id = ?", channelID, separatorID).
+			First(&variant).Error
+		separator = models.Separator{
+			SeparatorID:    variant.SeparatorID,
+			SeparatorURL:   variant.SeparatorURL,
+			OwnerChannelID: variant.OwnerChannelID,
+		}
+	}
+
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -82,3 +109,46 @@ func (r *SeparatorRepository) GetSeparatorByTwoID(ctx context.Context, channelID
 
 	return &separator, nil
 }
+
+func (r *SeparatorRepository) ListVariants(ctx context.Context, channelID int64) ([]models.SeparatorVariant, error) {
+	var variants []models.SeparatorVariant
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", channelID).
+		Order("created_at ASC").
+		Find(&variants).Error
+	return variants, err
+}
+
+func (r *SeparatorRepository) GetVariantByID(ctx context.Context, channelID int64, variantID string) (*models.SeparatorVariant, error) {
+	var variant models.SeparatorVariant
+	err := r.db.WithContext(ctx).
+		Where("variant_id = ? AND owner_channel_id = ?", variantID, channelID).
+		First(&variant).Error
+	return &variant, err
+}
+
+func (r *SeparatorRepository) CreateVariant(ctx context.Context, variant *models.SeparatorVariant) error {
+	if variant.VariantID == "" {
+		variant.VariantID = uuid.NewString()
+	}
+	return r.db.WithContext(ctx).Create(variant).Error
+}
+
+func (r *SeparatorRepository) SaveVariant(ctx context.Context, variant *models.SeparatorVariant) error {
+	return r.db.WithContext(ctx).Save(variant).Error
+}
+
+func (r *SeparatorRepository) DeleteVariant(ctx context.Context, channelID int64, variantID string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("variant_id = ? AND owner_channel_id = ?", variantID, channelID).
+		Delete(&models.SeparatorVariant{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteVariants remove todas as variantes do canal, mantendo o sticker principal.
+func (r *SeparatorRepository) DeleteVariants(ctx context.Context, channelID int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", channelID).
+		Delete(&models.SeparatorVariant{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestSeparatorVariants(t *testing.T) {
+	db := newTestDB(t, &models.Separator{}, &models.SeparatorVariant{})
+
+	repo := NewSeparatorRepository(db)
+	ctx := context.Background()
+
+	if err := repo.SaveSeparator(ctx, &models.Separator{OwnerChannelID: 10, SeparatorID: "main", SeparatorURL: "main.webp"}); err != nil {
+		t.Fatalf("SaveSeparator failed: %v", err)
+	}
+	if _, err := repo.UpdateSettings(ctx, 10, "round_robin", 15); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	// Trocar o sticker principal mantém as configurações.
+	if err := repo.SaveSeparator(ctx, &models.Separator{OwnerChannelID: 10, SeparatorID: "main2", SeparatorURL: "main2.webp"}); err != nil {
+		t.Fatalf("SaveSeparator failed: %v", err)
+	}
+	sep, err := repo.GetSeparatorByOwnerChannelID(ctx, 10)
+	if err != nil || sep == nil {
+		t.Fatalf("GetSeparatorByOwnerChannelID failed: %v", err)
+	}
+	if sep.SeparatorID != "main2" || sep.Rotation != "round_robin" || sep.CooldownMinutes != 15 {
+		t.Fatalf("unexpected separator: %+v", sep)
+	}
+
+	for _, v := range []*models.SeparatorVariant{
+		{OwnerChannelID: 10, SeparatorID: "extra", SeparatorURL: "extra.webp"},
+		{OwnerChannelID: 10, SeparatorID: "photo", MessageType: "photo"},
+		{OwnerChannelID: 20, SeparatorID: "other"},
+	} {
+		if err := repo.CreateVariant(ctx, v); err != nil {
+			t.Fatalf("CreateVariant failed: %v", err)
+		}
+	}
+
+	variants, err := repo.ListVariants(ctx, 10)
+	if err != nil || len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d (%v)", len(variants), err)
+	}
+
+	// O endereço do sticker também encontra as variantes.
+	found, err := repo.GetSeparatorByTwoID(ctx, 10, "extra")
+	if err != nil || found == nil || found.SeparatorURL != "extra.webp" {
+		t.Fatalf("expected variant sticker, got %+v (%v)", found, err)
+	}
+	if found, _ := repo.GetSeparatorByTwoID(ctx, 10, "other"); found != nil {
+		t.Fatalf("variant of another channel must not be found, got %+v", found)
+	}
+
+	if err := repo.DeleteSeparatorByOwnerChannelId(ctx, 10); err != nil {
+		t.Fatalf("DeleteSeparatorByOwnerChannelId failed: %v", err)
+	}
+	if variants, _ := repo.ListVariants(ctx, 10); len(variants) != 0 {
+		t.Fatalf("expected variants removed with the separator, got %d", len(variants))
+	}
+	if variants, _ := repo.ListVariants(ctx, 20); len(variants) != 1 {
+		t.Fatalf("variants of other channels must be kept, got %d", len(variants))
+	}
+}
@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/database/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VoteRepository struct {
@@ -86,6 +89,57 @@ func (r *VoteRepository) ToggleVote(ctx context.Context, chatID int64, messageID
 	return added, count, err
 }
 
+// ToggleEmojiVote adiciona ou remove o voto do usuário em um emoji sem mexer
+// nos demais, para votações de múltipla escolha. Retorna se o voto foi
+// adicionado e a contagem atual do emoji.
+func (r *VoteRepository) ToggleEmojiVote(ctx context.Context, chatID int64, messageID int, inlineMessageID string, userID int64, emoji string) (bool, int64, error) {
+	var added bool
+	var count int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scope := func(q *gorm.DB) *gorm.DB {
+			if inlineMessageID != "" {
+				return q.Where("inline_message_id = ?", inlineMessageID)
+			}
+			return q.Where("chat_id = ? AND message_id = ?", chatID, messageID)
+		}
+
+		result := scope(tx.Where("user_id = ? AND emoji = ?", userID, emoji)).Delete(&models.Vote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Create(&models.Vote{
+				ChatID:          chatID,
+				MessageID:       messageID,
+				InlineMessageID: inlineMessageID,
+				UserID:          userID,
+				Emoji:           emoji,
+			}).Error; err != nil {
+				return err
+			}
+			added = true
+		}
+
+		return scope(tx.Model(&models.Vote{}).Where("emoji = ?", emoji)).Count(&count).Error
+	})
+
+	return added, count, err
+}
+
+// HasUserVoted indica se o usuário tem algum voto na mensagem.
+func (r *VoteRepository) HasUserVoted(ctx context.Context, chatID int64, messageID int, inlineMessageID string, userID int64) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.Vote{}).Where("user_id = ?", userID)
+	if inlineMessageID != "" {
+		query = query.Where("inline_message_id = ?", inlineMessageID)
+	} else {
+		query = query.Where("chat_id = ? AND message_id = ?", chatID, messageID)
+	}
+	err := query.Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *VoteRepository) GetVoteCounts(ctx context.Context, chatID int64, messageID int, inlineMessageID string) (map[string]int64, error) {
 	type Result struct {
 		Emoji string
@@ -112,3 +166,203 @@ func (r *VoteRepository) GetVoteCounts(ctx context.Context, chatID int64, messag
 	}
 	return counts, nil
 }
+
+// ListMessageVotes retorna os emojis votados por cada usuário na mensagem, na
+// ordem em que foram votados.
+func (r *VoteRepository) ListMessageVotes(ctx context.Context, chatID int64, messageID int, inlineMessageID string) (map[int64][]string, error) {
+	var votes []models.Vote
+	query := r.db.WithContext(ctx).Select("user_id, emoji")
+	if inlineMessageID != "" {
+		query = query.Where("inline_message_id = ?", inlineMessageID)
+	} else {
+		query = query.Where("chat_id = ? AND message_id = ?", chatID, messageID)
+	}
+	if err := query.Order("id ASC").Find(&votes).Error; err != nil {
+		return nil, err
+	}
+
+	choices := make(map[int64][]string)
+	for _, v := range votes {
+		choices[v.UserID] = append(choices[v.UserID], v.Emoji)
+	}
+	return choices, nil
+}
+
+// ReplaceUserVotes grava as escolhas atuais dos usuários na mensagem: votos
+// que saíram são apagados e os novos criados, mantendo os que continuam (e a
+// data original deles). Uma lista vazia remove todos os votos do usuário.
+func (r *VoteRepository) ReplaceUserVotes(ctx context.Context, chatID int64, messageID int, inlineMessageID string, voters map[int64][]string) error {
+	if len(voters) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scope := func(q *gorm.DB) *gorm.DB {
+			if inlineMessageID != "" {
+				return q.Where("inline_message_id = ?", inlineMessageID)
+			}
+			return q.Where("chat_id = ? AND message_id = ?", chatID, messageID)
+		}
+
+		userIDs := make([]int64, 0, len(voters))
+		for userID := range voters {
+			userIDs = append(userIDs, userID)
+		}
+		var existing []models.Vote
+		if err := scope(tx.Where("user_id IN ?", userIDs)).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		kept := make(map[int64]map[string]bool, len(voters))
+		var stale []uint
+		for _, v := range existing {
+			if slices.Contains(voters[v.UserID], v.Emoji) {
+				if kept[v.UserID] == nil {
+					kept[v.UserID] = map[string]bool{}
+				}
+				kept[v.UserID][v.Emoji] = true
+				continue
+			}
+			stale = append(stale, v.ID)
+		}
+		if len(stale) > 0 {
+			if err := tx.Delete(&models.Vote{}, stale).Error; err != nil {
+				return err
+			}
+		}
+
+		var created []models.Vote
+		for userID, emojis := range voters {
+			for _, emoji := range emojis {
+				if kept[userID][emoji] {
+					continue
+				}
+				created = append(created, models.Vote{
+					ChatID:          chatID,
+					MessageID:       messageID,
+					InlineMessageID: inlineMessageID,
+					UserID:          userID,
+					Emoji:           emoji,
+				})
+			}
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error
+	})
+}
+
+// EmojiVoteCount é o total de votos de um emoji.
+type EmojiVoteCount struct {
+	Emoji string
+	Count int64
+}
+
+// DayVoteCount é o total de votos de um dia, com Day à meia-noite UTC.
+type DayVoteCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// PostVoteCount é o total de votos de um post, opcionalmente de um único emoji.
+type PostVoteCount struct {
+	MessageID int
+	Emoji     string
+	Count     int64
+}
+
+// channelVotes filtra os votos dados nos posts do canal desde since. Votos em
+// mensagens inline do PostBuilder não têm canal e ficam de fora.
+func (r *VoteRepository) channelVotes(ctx context.Context, chatID int64, since time.Time) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Vote{}).
+		Where("chat_id = ? AND inline_message_id = ?", chatID, "")
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	return query
+}
+
+// CountChannelVotes retorna o total de votos, de votantes únicos e de posts
+// votados no canal.
+func (r *VoteRepository) CountChannelVotes(ctx context.Context, chatID int64, since time.Time) (votes, voters, posts int64, err error) {
+	var result struct {
+		Votes  int64
+		Voters int64
+		Posts  int64
+	}
+	err = r.channelVotes(ctx, chatID, since).
+		Select("count(*) as votes, count(distinct user_id) as voters, count(distinct message_id) as posts").
+		Scan(&result).Error
+	return result.Votes, result.Voters, result.Posts, err
+}
+
+// CountChannelVotesByEmoji agrupa os votos do canal por emoji, do mais votado
+// para o menos votado.
+func (r *VoteRepository) CountChannelVotesByEmoji(ctx context.Context, chatID int64, since time.Time) ([]EmojiVoteCount, error) {
+	var results []EmojiVoteCount
+	err := r.channelVotes(ctx, chatID, since).
+		Select("emoji, count(*) as count").
+		Group("emoji").
+		Order("count desc, emoji").
+		Scan(&results).Error
+	return results, err
+}
+
+// TopChannelPosts retorna os posts do canal com mais votos. limit <= 0 traz
+// todos.
+func (r *VoteRepository) TopChannelPosts(ctx context.Context, chatID int64, since time.Time, limit int) ([]PostVoteCount, error) {
+	var results []PostVoteCount
+	query := r.channelVotes(ctx, chatID, since).
+		Select("message_id, count(*) as count").
+		Group("message_id").
+		Order("count desc, message_id desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Scan(&results).Error
+	return results, err
+}
+
+// CountChannelVotesByPostEmoji agrupa os votos do canal por post e emoji, para
+// exportação.
+func (r *VoteRepository) CountChannelVotesByPostEmoji(ctx context.Context, chatID int64, since time.Time) ([]PostVoteCount, error) {
+	var results []PostVoteCount
+	err := r.channelVotes(ctx, chatID, since).
+		Select("message_id, emoji, count(*) as count").
+		Group("message_id, emoji").
+		Order("message_id desc, count desc, emoji").
+		Scan(&results).Error
+	return results, err
+}
+
+// CountChannelVotesByDay agrupa os votos do canal por dia (em UTC), em ordem
+// cronológica. Dias sem votos não aparecem.
+func (r *VoteRepository) CountChannelVotesByDay(ctx context.Context, chatID int64, since time.Time) ([]DayVoteCount, error) {
+	var rows []struct {
+		Day   string
+		Count int64
+	}
+	err := r.channelVotes(ctx, chatID, since).
+		Select("DATE(created_at) as day, count(*) as count").
+		Group("DATE(created_at)").
+		Order("day").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]DayVoteCount, 0, len(rows))
+	for _, row := range rows {
+		// O SQLite devolve "2006-01-02" e o PostgreSQL uma data completa.
+		if len(row.Day) < len(time.DateOnly) {
+			continue
+		}
+		day, err := time.Parse(time.DateOnly, row.Day[:len(time.DateOnly)])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, DayVoteCount{Day: day, Count: row.Count})
+	}
+	return results, nil
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VotePollRepository struct {
+	db *gorm.DB
+}
+
+func NewVotePollRepository(db *gorm.DB) *VotePollRepository {
+	return &VotePollRepository{db: db}
+}
+
+func (r *VotePollRepository) GetSettings(ctx context.Context, ownerChannelID int64) (*models.VoteSettings, error) {
+	var settings models.VoteSettings
+
+	err := r.db.WithContext(ctx).
+		Where("owner_channel_id = ?", ownerChannelID).
+		First(&settings).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *VotePollRepository) SaveSettings(ctx context.Context, settings *models.VoteSettings) error {
+	if settings.ID == "" {
+		settings.ID = uuid.NewString()
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "owner_channel_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"mode", "hide_counts", "duration_minutes", "show_results", "updated_at",
+			}),
+		}).
+		Create(settings).Error
+}
+
+// GetPoll retorna nil quando o post ainda não tem votação registrada.
+func (r *VotePollRepository) GetPoll(ctx context.Context, chatID int64, messageID int, inlineMessageID string) (*models.VotePoll, error) {
+	var poll models.VotePoll
+
+	query := r.db.WithContext(ctx)
+	if inlineMessageID != "" {
+		query = query.Where("inline_message_id = ?", inlineMessageID)
+	} else {
+		query = query.Where("chat_id = ? AND message_id = ? AND inline_message_id = ''", chatID, messageID)
+	}
+
+	if err := query.First(&poll).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+// CreatePoll registra a votação do post. Se outro voto simultâneo já a criou,
+// mantém o registro existente e o carrega em poll.
+func (r *VotePollRepository) CreatePoll(ctx context.Context, poll *models.VotePoll) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(poll).Error
+	if err != nil {
+		return err
+	}
+	if poll.ID != 0 {
+		return nil
+	}
+
+	existing, err := r.GetPoll(ctx, poll.ChatID, poll.MessageID, poll.InlineMessageID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		*poll = *existing
+	}
+	return nil
+}
+
+// UpdateClosesAt muda o prazo da votação; nil reabre sem prazo.
+func (r *VotePollRepository) UpdateClosesAt(ctx context.Context, pollID uint, closesAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.VotePoll{}).
+		Where("id = ?", pollID).
+		Update("closes_at", closesAt).Error
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestVoteChannelAnalytics(t *testing.T) {
+	db := newTestDB(t, &models.Vote{})
+
+	repo := NewVoteRepository(db)
+	ctx := context.Background()
+
+	votes := []struct {
+		messageID int
+		userID    int64
+		emoji     string
+	}{
+		{1, 100, "👍"}, {1, 101, "👍"}, {1, 102, "❤️"},
+		{2, 100, "❤️"},
+	}
+	for _, v := range votes {
+		if _, _, err := repo.ToggleVote(ctx, -1001, v.messageID, "", v.userID, v.emoji); err != nil {
+			t.Fatalf("failed to vote: %v", err)
+		}
+	}
+	// Outro canal e mensagem inline não entram na conta.
+	if _, _, err := repo.ToggleVote(ctx, -1002, 1, "", 100, "👍"); err != nil {
+		t.Fatalf("failed to vote: %v", err)
+	}
+	if _, _, err := repo.ToggleVote(ctx, 0, 0, "inline", 100, "👍"); err != nil {
+		t.Fatalf("failed to vote: %v", err)
+	}
+
+	total, voters, posts, err := repo.CountChannelVotes(ctx, -1001, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to count votes: %v", err)
+	}
+	if total != 4 || voters != 3 || posts != 2 {
+		t.Fatalf("expected 4 votes, 3 voters and 2 posts, got %d, %d, %d", total, voters, posts)
+	}
+
+	byEmoji, err := repo.CountChannelVotesByEmoji(ctx, -1001, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to count by emoji: %v", err)
+	}
+	if len(byEmoji) != 2 || byEmoji[0].Count != 2 || byEmoji[1].Count != 2 {
+		t.Fatalf("unexpected emoji counts: %+v", byEmoji)
+	}
+
+	top, err := repo.TopChannelPosts(ctx, -1001, time.Time{}, 1)
+	if err != nil {
+		t.Fatalf("failed to list top posts: %v", err)
+	}
+	if len(top) != 1 || top[0].MessageID != 1 || top[0].Count != 3 {
+		t.Fatalf("unexpected top posts: %+v", top)
+	}
+
+	rows, err := repo.CountChannelVotesByPostEmoji(ctx, -1001, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to count by post and emoji: %v", err)
+	}
+	if len(rows) != 3 || rows[0].MessageID != 2 {
+		t.Fatalf("unexpected export rows: %+v", rows)
+	}
+
+	days, err := repo.CountChannelVotesByDay(ctx, -1001, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to count votes by day: %v", err)
+	}
+	if len(days) != 1 || days[0].Count != 4 || !days[0].Day.Equal(time.Now().UTC().Truncate(24*time.Hour)) {
+		t.Fatalf("expected 4 votes today, got %+v", days)
+	}
+	if total, _, _, _ := repo.CountChannelVotes(ctx, -1001, time.Now().Add(time.Hour)); total != 0 {
+		t.Fatalf("expected no votes after since, got %d", total)
+	}
+}
+
+func TestVoteMultipleChoiceAndPoll(t *testing.T) {
+	db := newTestDB(t, &models.Vote{}, &models.VotePoll{})
+
+	repo := NewVoteRepository(db)
+	polls := NewVotePollRepository(db)
+	ctx := context.Background()
+
+	for _, emoji := range []string{"👍", "❤️"} {
+		if added, count, err := repo.ToggleEmojiVote(ctx, -1001, 1, "", 100, emoji); err != nil || !added || count != 1 {
+			t.Fatalf("expected vote on %s, got %v, %d, %v", emoji, added, count, err)
+		}
+	}
+	if added, count, err := repo.ToggleEmojiVote(ctx, -1001, 1, "", 100, "👍"); err != nil || added || count != 0 {
+		t.Fatalf("expected vote removal, got %v, %d, %v", added, count, err)
+	}
+	if voted, err := repo.HasUserVoted(ctx, -1001, 1, "", 100); err != nil || !voted {
+		t.Fatalf("expected user to have voted, got %v, %v", voted, err)
+	}
+	if voted, err := repo.HasUserVoted(ctx, -1001, 1, "", 101); err != nil || voted {
+		t.Fatalf("expected user without votes, got %v, %v", voted, err)
+	}
+
+	closesAt := time.Now().Add(time.Hour)
+	first := &models.VotePoll{ChatID: -1001, MessageID: 1, Mode: "multiple", ClosesAt: &closesAt}
+	if err := polls.CreatePoll(ctx, first); err != nil {
+		t.Fatalf("failed to create poll: %v", err)
+	}
+	second := &models.VotePoll{ChatID: -1001, MessageID: 1, Mode: "single"}
+	if err := polls.CreatePoll(ctx, second); err != nil {
+		t.Fatalf("failed to create poll: %v", err)
+	}
+	if second.ID != first.ID || second.Mode != "multiple" {
+		t.Fatalf("expected existing poll to be kept, got %+v", second)
+	}
+
+	if err := polls.UpdateClosesAt(ctx, first.ID, nil); err != nil {
+		t.Fatalf("failed to reopen poll: %v", err)
+	}
+	poll, err := polls.GetPoll(ctx, -1001, 1, "")
+	if err != nil || poll == nil || poll.ClosesAt != nil {
+		t.Fatalf("expected reopened poll, got %+v, %v", poll, err)
+	}
+}
+
+func TestReplaceUserVotes(t *testing.T) {
+	db := newTestDB(t, &models.Vote{})
+
+	repo := NewVoteRepository(db)
+	ctx := context.Background()
+
+	if err := repo.ReplaceUserVotes(ctx, -1001, 1, "", map[int64][]string{100: {"👍", "❤️"}, 101: {"👍"}}); err != nil {
+		t.Fatalf("failed to replace votes: %v", err)
+	}
+	var kept models.Vote
+	if err := db.Where("user_id = ? AND emoji = ?", 100, "👍").First(&kept).Error; err != nil {
+		t.Fatalf("failed to load vote: %v", err)
+	}
+
+	// 100 troca ❤️ por 🔥 e mantém 👍; 101 remove o voto.
+	if err := repo.ReplaceUserVotes(ctx, -1001, 1, "", map[int64][]string{100: {"👍", "🔥"}, 101: nil}); err != nil {
+		t.Fatalf("failed to replace votes: %v", err)
+	}
+
+	choices, err := repo.ListMessageVotes(ctx, -1001, 1, "")
+	if err != nil {
+		t.Fatalf("failed to list votes: %v", err)
+	}
+	if len(choices) != 1 || len(choices[100]) != 2 || choices[100][0] != "👍" || choices[100][1] != "🔥" {
+		t.Fatalf("unexpected choices: %+v", choices)
+	}
+	var still models.Vote
+	if err := db.Where("user_id = ? AND emoji = ?", 100, "👍").First(&still).Error; err != nil || still.ID != kept.ID {
+		t.Fatalf("expected unchanged vote to be kept, got %+v, %v", still, err)
+	}
+}
@@ -0,0 +1,312 @@
+// Package linkrewrite aplica as regras de links do canal aos posts: parâmetros
+// UTM, listas de domínios permitidos/bloqueados e troca de hosts. As regras
+// valem para os botões e para os links da legenda.
+package linkrewrite
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+// Limites das listas salvas por canal.
+const (
+	MaxDomains  = 50
+	MaxRewrites = 20
+)
+
+// Rules são as regras de links de um canal já interpretadas.
+type Rules struct {
+	utm      [][2]string
+	allow    []string
+	deny     []string
+	rewrites map[string]string
+	targets  map[string]bool
+}
+
+// Rewrite troca o host From por To.
+type Rewrite struct {
+	From string
+	To   string
+}
+
+// FromSettings interpreta as configurações do canal. Retorna nil quando não
+// há configuração ou ela está desativada, e nil é um *Rules válido que não
+// altera nada.
+func FromSettings(settings *models.LinkSettings) *Rules {
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+
+	r := &Rules{
+		allow:    SplitDomains(settings.AllowDomains),
+		deny:     SplitDomains(settings.DenyDomains),
+		rewrites: map[string]string{},
+		targets:  map[string]bool{},
+	}
+	for _, p := range [][2]string{
+		{"utm_source", settings.UTMSource},
+		{"utm_medium", settings.UTMMedium},
+		{"utm_campaign", settings.UTMCampaign},
+		{"utm_content", settings.UTMContent},
+	} {
+		if v := strings.TrimSpace(p[1]); v != "" {
+			r.utm = append(r.utm, [2]string{p[0], v})
+		}
+	}
+	for _, rw := range ParseRewrites(settings.Rewrites) {
+		r.rewrites[rw.From] = rw.To
+		r.targets[rw.To] = true
+	}
+	return r
+}
+
+// Deny cria regras que só bloqueiam os domínios informados, sem UTM nem troca
+// de hosts.
+func Deny(domains []string) *Rules {
+	if len(domains) == 0 {
+		return nil
+	}
+	return &Rules{deny: domains}
+}
+
+// Allowed indica se o link pode ser publicado pelas listas de domínios. Links
+// sem host (ex: tg://) são sempre permitidos.
+func (r *Rules) Allowed(rawURL string) bool {
+	if r == nil {
+		return true
+	}
+	parsed, err := url.Parse(utils.NormalizeTelegramURL(rawURL))
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return true
+	}
+	if MatchDomain(host, r.deny) {
+		return false
+	}
+	return len(r.allow) == 0 || MatchDomain(host, r.allow)
+}
+
+// URL aplica as regras ao link: troca o host, se houver regra para ele, e
+// acrescenta os parâmetros UTM que ainda não estão no link. ok é false quando o
+// domínio está bloqueado. Links do Telegram não recebem UTM. Aplicar de novo a
+// um link já reescrito não o altera: trocas não são encadeadas.
+func (r *Rules) URL(rawURL string) (result string, ok bool) {
+	if r == nil {
+		return rawURL, true
+	}
+	if !r.Allowed(rawURL) {
+		return "", false
+	}
+
+	parsed, err := url.Parse(utils.NormalizeTelegramURL(rawURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return rawURL, true
+	}
+
+	if !r.targets[strings.ToLower(parsed.Host)] {
+		if to, found := r.rewrites[strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")]; found {
+			parsed.Host = to
+		}
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host != "t.me" && host != "telegram.me" {
+		query := parsed.Query()
+		for _, p := range r.utm {
+			if query.Has(p[0]) {
+				continue
+			}
+			if parsed.RawQuery != "" {
+				parsed.RawQuery += "&"
+			}
+			parsed.RawQuery += p[0] + "=" + url.QueryEscape(p[1])
+		}
+	}
+	return parsed.String(), true
+}
+
+var (
+	tagRegex     = regexp.MustCompile(`<[^>]*>`)
+	hrefRegex    = regexp.MustCompile(`(?i)^<a\s+href\s*=\s*"([^"]*)"\s*>$`)
+	bareURLRegex = regexp.MustCompile(`https?://[^\s<>"]+`)
+)
+
+// HTML aplica as regras aos links de um texto em HTML do Telegram: o href das
+// tags <a> e os links soltos no texto (fora de <code> e <pre>). Links de
+// domínios bloqueados perdem a tag <a>, mantendo o texto, ou são removidos
+// quando soltos. Retorna também quantos links foram alterados.
+func (r *Rules) HTML(text string) (string, int) {
+	if r == nil || text == "" {
+		return text, 0
+	}
+
+	var b strings.Builder
+	changed := 0
+	// Cada <a> aberta registra se foi mantida, para descartar o </a> correspondente.
+	var anchors []bool
+	code := 0
+
+	last := 0
+	for _, loc := range tagRegex.FindAllStringIndex(text, -1) {
+		segment := text[last:loc[0]]
+		if code > 0 || len(anchors) > 0 {
+			b.WriteString(segment)
+		} else {
+			segment, n := r.rewriteBare(segment)
+			b.WriteString(segment)
+			changed += n
+		}
+		last = loc[1]
+
+		tag := text[loc[0]:loc[1]]
+		lower := strings.ToLower(tag)
+		switch {
+		case strings.HasPrefix(lower, "<a "):
+			m := hrefRegex.FindStringSubmatch(tag)
+			if m == nil {
+				anchors = append(anchors, true)
+				b.WriteString(tag)
+				continue
+			}
+			href := html.UnescapeString(m[1])
+			rewritten, ok := r.URL(href)
+			if !ok {
+				anchors = append(anchors, false)
+				changed++
+				continue
+			}
+			anchors = append(anchors, true)
+			if rewritten != href {
+				changed++
+			}
+			b.WriteString(`<a href="` + html.EscapeString(rewritten) + `">`)
+			continue
+		case lower == "</a>":
+			if len(anchors) > 0 {
+				kept := anchors[len(anchors)-1]
+				anchors = anchors[:len(anchors)-1]
+				if !kept {
+					continue
+				}
+			}
+		case strings.HasPrefix(lower, "<code") || strings.HasPrefix(lower, "<pre"):
+			code++
+		case lower == "</code>" || lower == "</pre>":
+			if code > 0 {
+				code--
+			}
+		}
+		b.WriteString(tag)
+	}
+
+	segment := text[last:]
+	if code > 0 || len(anchors) > 0 {
+		b.WriteString(segment)
+	} else {
+		segment, n := r.rewriteBare(segment)
+		b.WriteString(segment)
+		changed += n
+	}
+	return b.String(), changed
+}
+
+func (r *Rules) rewriteBare(segment string) (string, int) {
+	changed := 0
+	out := bareURLRegex.ReplaceAllStringFunc(segment, func(match string) string {
+		// Pontuação no fim costuma ser da frase, não do link.
+		trimmed := strings.TrimRight(match, ".,;:!?)")
+		suffix := match[len(trimmed):]
+
+		raw := html.UnescapeString(trimmed)
+		rewritten, ok := r.URL(raw)
+		if !ok {
+			changed++
+			return suffix
+		}
+		if rewritten == raw {
+			return match
+		}
+		changed++
+		return html.EscapeString(rewritten) + suffix
+	})
+	return out, changed
+}
+
+// MatchDomain indica se o host é um dos domínios ou subdomínio de algum deles.
+func MatchDomain(host string, domains []string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+var hostRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+(:[0-9]{1,5})?$`)
+
+// NormalizeDomain reduz um domínio ou URL ao host em minúsculas, sem esquema,
+// caminho e "www.".
+func NormalizeDomain(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.Index(value, "://"); i >= 0 {
+		value = value[i+3:]
+	}
+	if i := strings.IndexAny(value, "/?#"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimPrefix(value, "www.")
+}
+
+// SplitDomains separa uma lista de domínios por vírgulas ou quebras de linha.
+func SplitDomains(value string) []string {
+	var domains []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if d := NormalizeDomain(item); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// ParseRewrites lê as regras no formato "origem=destino", separadas por
+// vírgulas ou quebras de linha. Regras incompletas são ignoradas.
+func ParseRewrites(value string) []Rewrite {
+	var rewrites []Rewrite
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		from, to, found := strings.Cut(item, "=")
+		if !found {
+			continue
+		}
+		rw := Rewrite{From: NormalizeDomain(from), To: strings.ToLower(strings.TrimSpace(to))}
+		if rw.From != "" && rw.To != "" {
+			rewrites = append(rewrites, rw)
+		}
+	}
+	return rewrites
+}
+
+// FormatRewrites é o inverso de ParseRewrites.
+func FormatRewrites(rewrites []Rewrite) string {
+	items := make([]string, len(rewrites))
+	for i, rw := range rewrites {
+		items[i] = rw.From + "=" + rw.To
+	}
+	return strings.Join(items, ",")
+}
+
+// ValidateDomain confere um domínio já normalizado.
+func ValidateDomain(domain string) error {
+	if !hostRegex.MatchString(domain) {
+		return fmt.Errorf("domínio inválido: %q", domain)
+	}
+	return nil
+}
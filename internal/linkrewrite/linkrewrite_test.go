@@ -0,0 +1,65 @@
+package linkrewrite
+
+import (
+	"testing"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func testRules() *Rules {
+	return FromSettings(&models.LinkSettings{
+		Enabled:     true,
+		UTMSource:   "telegram",
+		UTMCampaign: "canal x",
+		DenyDomains: "spam.com",
+		Rewrites:    "amzn.to=amazon.com.br, old.site=new.site",
+	})
+}
+
+func TestURL(t *testing.T) {
+	r := testRules()
+	cases := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"https://example.com/p?a=1#top", "https://example.com/p?a=1&utm_source=telegram&utm_campaign=canal+x#top", true},
+		{"https://example.com/?utm_source=site", "https://example.com/?utm_source=site&utm_campaign=canal+x", true},
+		{"https://www.amzn.to/x", "https://amazon.com.br/x?utm_source=telegram&utm_campaign=canal+x", true},
+		{"https://t.me/canal", "https://t.me/canal", true},
+		{"tg://resolve?domain=x", "tg://resolve?domain=x", true},
+		{"https://cdn.spam.com/x", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := r.URL(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("URL(%q) = %q, %v; want %q, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+		// Reaplicar as regras não altera o link.
+		if again, _ := r.URL(got); ok && again != got {
+			t.Errorf("URL is not idempotent: %q -> %q", got, again)
+		}
+	}
+
+	allowOnly := FromSettings(&models.LinkSettings{Enabled: true, AllowDomains: "loja.com"})
+	if !allowOnly.Allowed("https://www.loja.com/x") || allowOnly.Allowed("https://outra.com") {
+		t.Fatal("allow list not applied")
+	}
+
+	if FromSettings(&models.LinkSettings{UTMSource: "x"}) != nil {
+		t.Fatal("disabled settings should not produce rules")
+	}
+	var none *Rules
+	if got, ok := none.URL("https://a.com"); !ok || got != "https://a.com" {
+		t.Fatalf("nil rules changed the link: %q", got)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	r := testRules()
+	in := `Veja <a href="https://old.site/a?b=1&amp;c=2">aqui</a>, https://spam.com/x. e <a href="https://spam.com">isso</a> <code>https://example.com</code>`
+	want := `Veja <a href="https://new.site/a?b=1&amp;c=2&amp;utm_source=telegram&amp;utm_campaign=canal+x">aqui</a>, . e isso <code>https://example.com</code>`
+	got, n := r.HTML(in)
+	if got != want || n != 3 {
+		t.Fatalf("got %q (%d changes)", got, n)
+	}
+}
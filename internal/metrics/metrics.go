@@ -0,0 +1,124 @@
+// Package metrics concentra as métricas Prometheus do bot e da API, expostas
+// em /metrics no servidor gin.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "freddybot"
+
+var (
+	PipelineStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "pipeline_stage_duration_seconds",
+		Help:      "Duração de cada stage da pipeline de posts de canal.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"pipeline", "stage", "channel_type", "message_type", "result"})
+
+	QueueJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_jobs_total",
+		Help:      "Jobs da fila por resultado (enqueued, dropped, deferred, processed, retried, dead_lettered).",
+	}, []string{"queue", "message_type", "result"})
+
+	QueueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_wait_seconds",
+		Help:      "Tempo entre o enfileiramento e o início do processamento.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 3, 10),
+	}, []string{"queue"})
+
+	TelegramRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_api_requests_total",
+		Help:      "Chamadas à Bot API por método e resultado (ok, código de erro ou transport_error).",
+	}, []string{"method", "chat_type", "result"})
+
+	TelegramRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "telegram_api_request_duration_seconds",
+		Help:      "Latência das chamadas à Bot API, sem contar a espera no rate limiter.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	TelegramRateLimitWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "telegram_ratelimit_wait_seconds",
+		Help:      "Tempo de espera no rate limiter antes da chamada à Bot API.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 4, 8),
+	}, []string{"priority"})
+
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Consultas ao cache por camada (l1 local, l2 redis) e resultado.",
+	}, []string{"layer", "keyspace", "result"})
+
+	CacheInvalidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_invalidations_total",
+		Help:      "Invalidações do cache L1 trocadas entre instâncias via Redis pub/sub.",
+	}, []string{"kind", "direction"})
+
+	BroadcastJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "broadcast_jobs_total",
+		Help:      "Envios de broadcast por tipo e resultado.",
+	}, []string{"kind", "result"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latência das requisições HTTP por rota.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Handler retorna o handler HTTP do endpoint /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterQueueDepth publica a profundidade atual de uma fila em memória.
+func RegisterQueueDepth(queue string, depth func() float64) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "queue_depth",
+		Help:        "Itens aguardando processamento na fila em memória.",
+		ConstLabels: prometheus.Labels{"queue": queue},
+	}, depth)
+}
+
+// ChatType classifica o chat pelo ID: usuários têm IDs positivos, grupos e canais negativos.
+func ChatType(chatID int64) string {
+	switch {
+	case chatID > 0:
+		return "private"
+	case chatID < 0:
+		return "group"
+	default:
+		return "none"
+	}
+}
+
+// Keyspace reduz uma chave de cache ao seu prefixo para manter a cardinalidade baixa.
+func Keyspace(key string) string {
+	if prefix, _, found := strings.Cut(key, ":"); found {
+		return prefix
+	}
+	return "other"
+}
+
+// LabelOrUnknown evita labels vazios.
+func LabelOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
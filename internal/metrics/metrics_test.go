@@ -0,0 +1,18 @@
+package metrics
+
+import "testing"
+
+func TestLabelHelpers(t *testing.T) {
+	if got := Keyspace("channel:v2:-100123"); got != "channel" {
+		t.Errorf("expected keyspace channel, got %s", got)
+	}
+	if got := Keyspace("sessionwithoutprefix"); got != "other" {
+		t.Errorf("expected keyspace other, got %s", got)
+	}
+	if ChatType(42) != "private" || ChatType(-100123) != "group" || ChatType(0) != "none" {
+		t.Error("unexpected chat type classification")
+	}
+	if LabelOrUnknown("") != "unknown" || LabelOrUnknown("photo") != "photo" {
+		t.Error("unexpected label fallback")
+	}
+}
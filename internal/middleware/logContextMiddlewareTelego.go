@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+// LogContextMiddlewareTelego anexa ao contexto do update os campos de log
+// (correlation_id, update_id, user_id e channel_id) usados pelas variantes *Ctx do logger.
+func LogContextMiddlewareTelego() telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		logCtx := logger.WithCorrelationID(ctx.Context(), logger.NewCorrelationID())
+		logCtx = logger.With(logCtx, logger.FieldUpdateID, update.UpdateID)
+
+		if userID := GetUpdateUserIDTelego(update); userID != 0 {
+			logCtx = logger.With(logCtx, logger.FieldUserID, userID)
+		}
+		switch {
+		case update.ChannelPost != nil:
+			logCtx = logger.With(logCtx, logger.FieldChannelID, update.ChannelPost.Chat.ID)
+		case update.EditedChannelPost != nil:
+			logCtx = logger.With(logCtx, logger.FieldChannelID, update.EditedChannelPost.Chat.ID)
+		}
+
+		return ctx.WithContext(logCtx).Next(update)
+	}
+}
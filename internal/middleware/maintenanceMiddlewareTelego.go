@@ -12,7 +12,7 @@ import (
 
 func CheckMaintenanceMiddlewareTelego(c *container.AppContainer) telegohandler.Handler {
 	return func(ctx *telegohandler.Context, upt telego.Update) error {
-		if upt.ChannelPost != nil || upt.EditedChannelPost != nil {
+		if upt.ChannelPost != nil || upt.EditedChannelPost != nil || upt.MessageReactionCount != nil {
 			return ctx.Next(upt)
 		}
 
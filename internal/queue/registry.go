@@ -0,0 +1,27 @@
+package queue
+
+import "sync"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() []KeyStats{}
+)
+
+// Register publica as métricas de uma fila para consulta pela API de monitoramento.
+func Register(name string, stats func() []KeyStats) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = stats
+}
+
+// Snapshot retorna as métricas atuais de todas as filas registradas.
+func Snapshot() map[string][]KeyStats {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	snapshot := make(map[string][]KeyStats, len(registry))
+	for name, stats := range registry {
+		snapshot[name] = stats()
+	}
+	return snapshot
+}
@@ -0,0 +1,245 @@
+// Package queue implementa o agendador usado pelas filas em memória do bot:
+// cada chave (normalmente o ID do canal) tem sua própria fila FIFO, no máximo
+// um item por chave é processado por vez e as chaves são atendidas em rodízio.
+package queue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySmoothing é o peso da amostra mais recente na média móvel exponencial.
+const latencySmoothing = 0.2
+
+type entry[T any] struct {
+	job        T
+	enqueuedAt time.Time
+}
+
+type lane[T any] struct {
+	items  []entry[T]
+	busy   bool
+	queued bool // true quando a chave está na fila de rodízio
+
+	processed uint64
+	lastWait  time.Duration
+	avgWait   time.Duration
+	lastRun   time.Duration
+	avgRun    time.Duration
+}
+
+// Lease representa um item retirado da fila. Done deve ser chamado ao final
+// do processamento para liberar a chave.
+type Lease[T any] struct {
+	Key       int64
+	Job       T
+	Wait      time.Duration
+	startedAt time.Time
+}
+
+// FairScheduler mantém uma fila FIFO por chave e entrega os itens em rodízio
+// entre as chaves, garantindo ordem dentro da chave e justiça entre chaves.
+type FairScheduler[T any] struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	lanes  map[int64]*lane[T]
+	ready  []int64
+	slots  chan struct{}
+	size   int
+	closed bool
+}
+
+// NewFairScheduler cria um agendador com capacidade total de capacity itens.
+func NewFairScheduler[T any](capacity int) *FairScheduler[T] {
+	s := &FairScheduler[T]{
+		lanes: make(map[int64]*lane[T]),
+		slots: make(chan struct{}, capacity),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// TryPush adiciona o item sem bloquear. Retorna false quando a fila está cheia.
+func (s *FairScheduler[T]) TryPush(key int64, job T) bool {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		return false
+	}
+	return s.push(key, job)
+}
+
+// Push adiciona o item, aguardando espaço na fila ou o cancelamento do ctx.
+func (s *FairScheduler[T]) Push(ctx context.Context, key int64, job T) error {
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if !s.push(key, job) {
+		return context.Canceled
+	}
+	return nil
+}
+
+func (s *FairScheduler[T]) push(key int64, job T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		<-s.slots
+		return false
+	}
+
+	l := s.lanes[key]
+	if l == nil {
+		l = &lane[T]{}
+		s.lanes[key] = l
+	}
+	l.items = append(l.items, entry[T]{job: job, enqueuedAt: time.Now()})
+	s.size++
+	s.markReady(key, l)
+	s.cond.Signal()
+	return true
+}
+
+// markReady coloca a chave no fim do rodízio se ela tem itens e está livre.
+func (s *FairScheduler[T]) markReady(key int64, l *lane[T]) {
+	if l.busy || l.queued || len(l.items) == 0 {
+		return
+	}
+	l.queued = true
+	s.ready = append(s.ready, key)
+}
+
+// Next bloqueia até existir um item disponível. Retorna false quando o
+// agendador foi fechado e não há mais itens.
+func (s *FairScheduler[T]) Next() (Lease[T], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.ready) == 0 {
+		if s.closed {
+			return Lease[T]{}, false
+		}
+		s.cond.Wait()
+	}
+
+	key := s.ready[0]
+	s.ready = s.ready[1:]
+
+	l := s.lanes[key]
+	l.queued = false
+	l.busy = true
+
+	head := l.items[0]
+	l.items[0] = entry[T]{}
+	l.items = l.items[1:]
+	s.size--
+	<-s.slots
+
+	now := time.Now()
+	wait := now.Sub(head.enqueuedAt)
+	l.lastWait = wait
+	l.avgWait = smooth(l.avgWait, wait, l.processed)
+
+	return Lease[T]{Key: key, Job: head.job, Wait: wait, startedAt: now}, true
+}
+
+// Done libera a chave do item e a devolve ao fim do rodízio se houver mais itens.
+func (s *FairScheduler[T]) Done(lease Lease[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.lanes[lease.Key]
+	if l == nil {
+		return
+	}
+	run := time.Since(lease.startedAt)
+	l.lastRun = run
+	l.avgRun = smooth(l.avgRun, run, l.processed)
+	l.processed++
+	l.busy = false
+
+	// Chave ociosa e sem itens sai do mapa; senão ele cresce com cada canal já visto.
+	if len(l.items) == 0 {
+		delete(s.lanes, lease.Key)
+		return
+	}
+	s.markReady(lease.Key, l)
+	if l.queued {
+		s.cond.Signal()
+	}
+}
+
+// Len retorna o total de itens aguardando processamento.
+func (s *FairScheduler[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Close acorda os workers parados em Next. Itens ainda enfileirados continuam
+// sendo entregues até a fila esvaziar.
+func (s *FairScheduler[T]) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// KeyStats resume o estado de uma chave para monitoramento.
+type KeyStats struct {
+	Key          int64   `json:"key"`
+	Depth        int     `json:"depth"`
+	InFlight     bool    `json:"inFlight"`
+	Processed    uint64  `json:"processed"`
+	OldestWaitMs int64   `json:"oldestWaitMs"`
+	LastWaitMs   int64   `json:"lastWaitMs"`
+	AvgWaitMs    float64 `json:"avgWaitMs"`
+	LastRunMs    int64   `json:"lastRunMs"`
+	AvgRunMs     float64 `json:"avgRunMs"`
+}
+
+// Stats retorna as métricas por chave, das filas mais profundas para as menores.
+// Só aparecem chaves com itens na fila ou em execução.
+func (s *FairScheduler[T]) Stats() []KeyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]KeyStats, 0, len(s.lanes))
+	for key, l := range s.lanes {
+		stat := KeyStats{
+			Key:        key,
+			Depth:      len(l.items),
+			InFlight:   l.busy,
+			Processed:  l.processed,
+			LastWaitMs: l.lastWait.Milliseconds(),
+			AvgWaitMs:  float64(l.avgWait) / float64(time.Millisecond),
+			LastRunMs:  l.lastRun.Milliseconds(),
+			AvgRunMs:   float64(l.avgRun) / float64(time.Millisecond),
+		}
+		if len(l.items) > 0 {
+			stat.OldestWaitMs = now.Sub(l.items[0].enqueuedAt).Milliseconds()
+		}
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Depth != stats[j].Depth {
+			return stats[i].Depth > stats[j].Depth
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+func smooth(avg, sample time.Duration, samples uint64) time.Duration {
+	if samples == 0 {
+		return sample
+	}
+	return time.Duration(float64(avg)*(1-latencySmoothing) + float64(sample)*latencySmoothing)
+}
@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFairSchedulerOrderAndFairness(t *testing.T) {
+	s := NewFairScheduler[string](10)
+
+	// Canal 1 enfileira uma rajada antes do canal 2.
+	for _, job := range []string{"a1", "a2", "a3"} {
+		if !s.TryPush(1, job) {
+			t.Fatalf("push %s rejected", job)
+		}
+	}
+	if err := s.Push(context.Background(), 2, "b1"); err != nil {
+		t.Fatalf("push b1 failed: %v", err)
+	}
+
+	first, _ := s.Next()
+	if first.Job != "a1" {
+		t.Fatalf("expected a1 first, got %s", first.Job)
+	}
+
+	// Enquanto a1 está em execução o canal 1 não pode entregar outro item.
+	second, _ := s.Next()
+	if second.Job != "b1" {
+		t.Fatalf("expected b1 while channel 1 is busy, got %s", second.Job)
+	}
+	s.Done(second)
+	s.Done(first)
+
+	var order []string
+	for s.Len() > 0 {
+		lease, _ := s.Next()
+		order = append(order, lease.Job)
+		if s.Len() == 0 {
+			stats := s.Stats()
+			if len(stats) != 1 || stats[0].Key != 1 || !stats[0].InFlight || stats[0].Processed != 2 {
+				t.Errorf("expected only key 1 in flight with 2 processed, got %+v", stats)
+			}
+		}
+		s.Done(lease)
+	}
+	if len(order) != 2 || order[0] != "a2" || order[1] != "a3" {
+		t.Fatalf("expected FIFO order [a2 a3], got %v", order)
+	}
+
+	// Chaves ociosas são descartadas para o mapa não crescer sem limite.
+	if stats := s.Stats(); len(stats) != 0 {
+		t.Fatalf("expected idle keys to be dropped, got %+v", stats)
+	}
+}
+
+func TestFairSchedulerRoundRobin(t *testing.T) {
+	s := NewFairScheduler[int64](10)
+	for i := 0; i < 3; i++ {
+		s.TryPush(1, 1)
+	}
+	s.TryPush(2, 2)
+	s.TryPush(3, 3)
+
+	var order []int64
+	for s.Len() > 0 {
+		lease, _ := s.Next()
+		order = append(order, lease.Job)
+		s.Done(lease)
+	}
+
+	expected := []int64{1, 2, 3, 1, 1}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected round-robin order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestFairSchedulerCapacity(t *testing.T) {
+	s := NewFairScheduler[int](1)
+	if !s.TryPush(1, 1) {
+		t.Fatal("first push rejected")
+	}
+	if s.TryPush(2, 2) {
+		t.Fatal("expected push over capacity to be rejected")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Push(ctx, 2, 2); err == nil {
+		t.Fatal("expected blocking push to honor canceled context")
+	}
+
+	lease, _ := s.Next()
+	s.Done(lease)
+	if !s.TryPush(2, 2) {
+		t.Fatal("expected push to succeed after a slot was released")
+	}
+}
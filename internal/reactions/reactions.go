@@ -0,0 +1,71 @@
+// Package reactions trata do modo de reação dos posts: botões de voto do bot
+// ou reações nativas do Telegram.
+package reactions
+
+import (
+	"slices"
+	"strings"
+)
+
+// Modos de reação por canal.
+const (
+	// ModeButtons adiciona os emojis como botões "vote:<emoji>" no teclado do post.
+	ModeButtons = "buttons"
+	// ModeNative usa as reações nativas do Telegram; o bot reage com o primeiro
+	// emoji e acompanha as contagens anônimas do canal.
+	ModeNative = "native"
+)
+
+// Native lista os emojis aceitos como reação pelo Telegram, já normalizados.
+var Native = []string{
+	"❤", "👍", "👎", "🔥", "🥰", "👏", "😁", "🤔", "🤯", "😱", "🤬", "😢", "🎉", "🤩", "🤮",
+	"💩", "🙏", "👌", "🕊", "🤡", "🥱", "🥴", "😍", "🐳", "❤‍🔥", "🌚", "🌭", "💯", "🤣", "⚡",
+	"🍌", "🏆", "💔", "🤨", "😐", "🍓", "🍾", "💋", "🖕", "😈", "😴", "😭", "🤓", "👻", "👨‍💻",
+	"👀", "🎃", "🙈", "😇", "😨", "🤝", "✍", "🤗", "🫡", "🎅", "🎄", "☃", "💅", "🤪", "🗿",
+	"🆒", "💘", "🙉", "🦄", "😘", "💊", "🙊", "😎", "👾", "🤷‍♂", "🤷", "🤷‍♀", "😡",
+}
+
+// IsValidMode indica se o modo é um dos aceitos.
+func IsValidMode(mode string) bool {
+	return mode == ModeButtons || mode == ModeNative
+}
+
+// ModeOf retorna o modo efetivo; vazio ou desconhecido vale ModeButtons.
+func ModeOf(mode string) string {
+	if mode == ModeNative {
+		return ModeNative
+	}
+	return ModeButtons
+}
+
+// Normalize remove o seletor de variação (U+FE0F), que o Telegram não usa nas
+// reações: "❤️" digitado pelo dono vira "❤".
+func Normalize(emoji string) string {
+	return strings.ReplaceAll(strings.TrimSpace(emoji), "\uFE0F", "")
+}
+
+// IsNative indica se o emoji pode ser usado como reação nativa.
+func IsNative(emoji string) bool {
+	return slices.Contains(Native, Normalize(emoji))
+}
+
+// Split separa a lista de reações do canal, descartando itens vazios.
+func Split(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// BotEmoji retorna o emoji, já normalizado, com que o bot reage aos posts no
+// modo native: o primeiro da lista do canal. Vazio sem reações configuradas.
+func BotEmoji(value string) string {
+	items := Split(value)
+	if len(items) == 0 {
+		return ""
+	}
+	return Normalize(items[0])
+}
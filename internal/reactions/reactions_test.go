@@ -0,0 +1,34 @@
+package reactions
+
+import "testing"
+
+func TestNormalizeAndIsNative(t *testing.T) {
+	if got := Normalize(" ❤️ "); got != "❤" {
+		t.Fatalf("expected variation selector to be removed, got %q", got)
+	}
+	for _, emoji := range []string{"❤️", "👍", "❤️‍🔥", "✍️"} {
+		if !IsNative(emoji) {
+			t.Errorf("expected %q to be a native reaction", emoji)
+		}
+	}
+	for _, emoji := range []string{"🚀", "a", ""} {
+		if IsNative(emoji) {
+			t.Errorf("expected %q not to be a native reaction", emoji)
+		}
+	}
+}
+
+func TestModeOf(t *testing.T) {
+	if ModeOf("") != ModeButtons || ModeOf("x") != ModeButtons || ModeOf(ModeNative) != ModeNative {
+		t.Fatal("unexpected effective mode")
+	}
+}
+
+func TestBotEmoji(t *testing.T) {
+	if got := BotEmoji(" ❤️ , 👍"); got != "❤" {
+		t.Fatalf("expected first reaction normalized, got %q", got)
+	}
+	if got := BotEmoji(" , "); got != "" {
+		t.Fatalf("expected empty bot emoji, got %q", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Package separatorpool escolhe o sticker separador enviado depois de um post:
+// variantes por legenda customizada, por tipo de mensagem ou o rodízio entre o
+// sticker principal e as variantes gerais.
+package separatorpool
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+// Modos de rodízio. ModeOff usa sempre o sticker principal.
+const (
+	ModeOff        = ""
+	ModeRoundRobin = "round_robin"
+	ModeRandom     = "random"
+)
+
+// Limites das configurações do separador.
+const (
+	MaxVariants        = 20
+	MaxCooldownMinutes = 24 * 60
+)
+
+func IsValidMode(mode string) bool {
+	return mode == ModeOff || mode == ModeRoundRobin || mode == ModeRandom
+}
+
+// Post descreve o post que acabou de ser publicado. Hashtag é o código da
+// legenda customizada aplicada, vazio quando nenhuma foi usada.
+type Post struct {
+	MessageType string
+	Hashtag     string
+}
+
+// Candidates lista os stickers que servem para o post, na ordem de criação.
+// Variantes da legenda customizada têm preferência sobre as do tipo de
+// mensagem; sem nenhuma delas, vale o sticker principal e, com o rodízio
+// ativo, as variantes gerais. Retorna nil sem sticker principal.
+func Candidates(sep *models.Separator, variants []models.SeparatorVariant, post Post) []string {
+	if sep == nil || sep.SeparatorID == "" {
+		return nil
+	}
+
+	if hashtag := NormalizeHashtag(post.Hashtag); hashtag != "" {
+		if ids := filter(variants, func(v *models.SeparatorVariant) bool {
+			return v.Hashtag != "" && strings.EqualFold(v.Hashtag, hashtag)
+		}); len(ids) > 0 {
+			return ids
+		}
+	}
+	if post.MessageType != "" {
+		if ids := filter(variants, func(v *models.SeparatorVariant) bool { return v.MessageType == post.MessageType }); len(ids) > 0 {
+			return ids
+		}
+	}
+
+	ids := []string{sep.SeparatorID}
+	if sep.Rotation != ModeOff {
+		ids = append(ids, filter(variants, func(v *models.SeparatorVariant) bool {
+			return v.MessageType == "" && v.Hashtag == ""
+		})...)
+	}
+	return ids
+}
+
+func filter(variants []models.SeparatorVariant, match func(v *models.SeparatorVariant) bool) []string {
+	var ids []string
+	for i := range variants {
+		if v := &variants[i]; v.SeparatorID != "" && match(v) {
+			ids = append(ids, v.SeparatorID)
+		}
+	}
+	return ids
+}
+
+// Pick escolhe o sticker entre os candidatos. seq é o contador de rodízio do
+// canal e random(n) deve devolver um inteiro em [0, n). Sem rodízio fica o
+// primeiro candidato; retorna vazio quando não há nenhum.
+func Pick(candidates []string, mode string, seq int64, random func(n int) int) string {
+	switch {
+	case len(candidates) == 0:
+		return ""
+	case len(candidates) == 1 || mode == ModeOff:
+		return candidates[0]
+	case mode == ModeRandom:
+		return candidates[random(len(candidates))]
+	}
+
+	if seq < 0 {
+		seq = -seq
+	}
+	return candidates[seq%int64(len(candidates))]
+}
+
+// NormalizeHashtag tira o # e os espaços do código da legenda customizada.
+func NormalizeHashtag(hashtag string) string {
+	return strings.TrimPrefix(strings.TrimSpace(hashtag), "#")
+}
+
+// ValidateVariant confere o filtro de uma variante antes de salvá-la. Uma
+// variante vale para um tipo de mensagem ou para uma legenda, não os dois.
+func ValidateVariant(v *models.SeparatorVariant) error {
+	if v.MessageType != "" && v.Hashtag != "" {
+		return fmt.Errorf("use um tipo de mensagem ou uma hashtag, não os dois")
+	}
+	if v.MessageType != "" && !slices.Contains(captiontpl.MessageTypes, v.MessageType) {
+		return fmt.Errorf("tipo de mensagem desconhecido %q (use %s)", v.MessageType, strings.Join(captiontpl.MessageTypes, ", "))
+	}
+	if strings.ContainsAny(v.Hashtag, " \t\n#") {
+		return fmt.Errorf("hashtag inválida %q", v.Hashtag)
+	}
+	return nil
+}
+
+// ValidateCooldown confere o intervalo de silêncio antes do separador.
+func ValidateCooldown(minutes int) error {
+	if minutes < 0 || minutes > MaxCooldownMinutes {
+		return fmt.Errorf("intervalo deve estar entre 0 e %d minutos", MaxCooldownMinutes)
+	}
+	return nil
+}
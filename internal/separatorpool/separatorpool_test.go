@@ -0,0 +1,73 @@
+package separatorpool
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+)
+
+func TestCandidatesPreference(t *testing.T) {
+	sep := &models.Separator{SeparatorID: "main", Rotation: ModeRoundRobin}
+	variants := []models.SeparatorVariant{
+		{SeparatorID: "extra"},
+		{SeparatorID: "photo", MessageType: "photo"},
+		{SeparatorID: "promo", Hashtag: "Promo"},
+	}
+
+	cases := []struct {
+		post Post
+		want []string
+	}{
+		{Post{MessageType: "photo", Hashtag: "promo"}, []string{"promo"}},
+		{Post{MessageType: "photo", Hashtag: "outra"}, []string{"photo"}},
+		{Post{MessageType: "text"}, []string{"main", "extra"}},
+	}
+	for _, tc := range cases {
+		if got := Candidates(sep, variants, tc.post); !slices.Equal(got, tc.want) {
+			t.Errorf("%+v: got %v, want %v", tc.post, got, tc.want)
+		}
+	}
+
+	sep.Rotation = ModeOff
+	if got := Candidates(sep, variants, Post{MessageType: "text"}); !slices.Equal(got, []string{"main"}) {
+		t.Errorf("rotation off: got %v, want [main]", got)
+	}
+	if got := Candidates(&models.Separator{}, variants, Post{MessageType: "photo"}); got != nil {
+		t.Errorf("expected nil without main sticker, got %v", got)
+	}
+}
+
+func TestPick(t *testing.T) {
+	ids := []string{"a", "b", "c"}
+
+	var got []string
+	for seq := int64(1); seq <= 4; seq++ {
+		got = append(got, Pick(ids, ModeRoundRobin, seq, nil))
+	}
+	if want := []string{"b", "c", "a", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if id := Pick(ids, ModeRandom, 0, func(n int) int { return n - 1 }); id != "c" {
+		t.Errorf("random: got %s, want c", id)
+	}
+	if id := Pick(ids, ModeOff, 2, nil); id != "a" {
+		t.Errorf("off: got %s, want a", id)
+	}
+	if id := Pick(nil, ModeRoundRobin, 1, nil); id != "" {
+		t.Errorf("expected empty pick, got %s", id)
+	}
+}
+
+func TestValidateVariant(t *testing.T) {
+	if err := ValidateVariant(&models.SeparatorVariant{MessageType: "photo", Hashtag: "promo"}); err == nil {
+		t.Error("expected error for type and hashtag together")
+	}
+	if err := ValidateVariant(&models.SeparatorVariant{MessageType: "poll"}); err == nil {
+		t.Error("expected error for unknown message type")
+	}
+	if err := ValidateVariant(&models.SeparatorVariant{Hashtag: "promo"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
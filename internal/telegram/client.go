@@ -3,43 +3,67 @@ package telegram
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
-	"os"
-	"os/signal"
 
 	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
 	"github.com/mymmrac/telego/telegohandler"
-	"github.com/leirbagxis/FreddyBot/internal/cache"
 	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/events/channelPost"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/handlers/events/postBuilder"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/ratelimit"
 	"github.com/leirbagxis/FreddyBot/pkg/config"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
-	"gorm.io/gorm"
 )
 
-func StartBot(db *gorm.DB) (http.Handler, *telego.Bot) {
-
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
-
-	cache.GetRedisClient()
-
-	// Inicializar telego
-	tb, err := telego.NewBot(config.TelegramBotToken)
+// NewBot cria o cliente telego. Todas as chamadas à Bot API passam pelo mesmo
+// limiter (global + por chat).
+func NewBot() *telego.Bot {
+	limiter := ratelimit.NewLimiter(ratelimit.DefaultLimits)
+	tb, err := telego.NewBot(config.TelegramBotToken,
+		telego.WithAPICaller(ratelimit.NewCaller(telegoapi.DefaultFastHTTPCaller, limiter)),
+		telego.WithRequestConstructor(ratelimit.Constructor{}),
+	)
 	if err != nil {
 		panic(err)
 	}
+	return tb
+}
+
+// allowedUpdates lista os updates pedidos ao Telegram, no webhook e no polling.
+// message_reaction_count alimenta as estatísticas dos canais com reações nativas.
+var allowedUpdates = []string{"message", "edited_message", "callback_query", "inline_query", "chosen_inline_result", "my_chat_member", "channel_post", "edited_channel_post", "message_reaction_count"}
 
-	app := container.NewAppContainer(db, tb)
+// Bot é o recebimento de updates em execução, criado por StartBot.
+type Bot struct {
+	// WebhookHandler recebe os updates no modo webhook; deve ser registrado na API.
+	WebhookHandler http.Handler
 
-	botInfo, _ := tb.GetMe(context.Background())
+	handler     *telegohandler.BotHandler
+	stopPolling context.CancelFunc
+}
+
+// StartBot registra os handlers no container compartilhado e começa a receber
+// updates. As rotinas em background (fila persistente, agendamentos) param
+// quando ctx é cancelado.
+func StartBot(ctx context.Context, app *container.AppContainer) (*Bot, error) {
+	tb := app.TelegoBot
+
+	botInfo, _ := tb.GetMe(ctx)
 	logger.Bot("🤖 Bot iniciado (Telego): %s", botInfo.Username)
 
 	// Updates channel
 	updates := make(chan telego.Update, 1000)
-	bh, _ := telegohandler.NewBotHandler(tb, updates)
+	bh, err := telegohandler.NewBotHandler(tb, updates)
+	if err != nil {
+		return nil, err
+	}
+	bot := &Bot{handler: bh}
 
 	// Custom HTTP Handler for Webhook
-	webhookHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	bot.WebhookHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			logger.Error("BOT", "❌ Erro ao ler body: %v", err)
@@ -59,30 +83,26 @@ func StartBot(db *gorm.DB) (http.Handler, *telego.Bot) {
 		logger.Bot("✅ Webhook processado com sucesso")
 	})
 
-	go func() {
-		<-ctx.Done()
-		logger.Bot("🔻 Shutting down gracefully...")
-		if err := cache.CloseRedis(); err != nil {
-			logger.Error("SYS", "❌ Error closing Redis: %v", err)
-		}
-		cancel()
-	}()
+	// Fila persistente dos posts de canal (precisa estar ativa antes dos handlers)
+	channelpost.StartDurableQueueTelego(ctx, app)
 
 	// Load Handlers
 	LoadHandlersTelegoWithBH(bh, app)
 
+	go postbuilder.StartScheduledPostDispatcherTelego(ctx, app)
+
 	webhookUrl := config.WebhookURL
 	if webhookUrl != "" {
 		logger.Bot("🔗 Bot configurado para modo webhook: %s", webhookUrl)
 
-		_ = tb.SetWebhook(context.Background(), &telego.SetWebhookParams{
+		_ = tb.SetWebhook(ctx, &telego.SetWebhookParams{
 			URL:            webhookUrl,
-			AllowedUpdates: []string{"message", "edited_message", "callback_query", "inline_query", "chosen_inline_result", "my_chat_member", "channel_post", "edited_channel_post"},
+			AllowedUpdates: allowedUpdates,
 		})
 
 		logger.Bot("✅ Webhook configurado com sucesso")
 
-		webhookInfo, err := tb.GetWebhookInfo(context.Background())
+		webhookInfo, err := tb.GetWebhookInfo(ctx)
 		if err == nil {
 			logger.Bot("📊 Webhook Info - URL: %s, Pending: %d",
 				webhookInfo.URL, webhookInfo.PendingUpdateCount)
@@ -93,18 +113,37 @@ func StartBot(db *gorm.DB) (http.Handler, *telego.Bot) {
 
 	} else {
 		logger.Bot("🔄 Bot iniciado em modo polling")
-		_ = tb.DeleteWebhook(context.Background(), &telego.DeleteWebhookParams{})
-		
+		_ = tb.DeleteWebhook(ctx, &telego.DeleteWebhookParams{})
+
+		// O long polling tem contexto próprio para parar antes dos handlers no Shutdown.
+		pollingCtx, stopPolling := context.WithCancel(context.Background())
+		bot.stopPolling = stopPolling
+
 		// Iniciar Long Polling em paralelo para alimentar o channel de updates
-		pollingUpdates, _ := tb.UpdatesViaLongPolling(context.Background(), nil)
+		pollingUpdates, err := tb.UpdatesViaLongPolling(pollingCtx, &telego.GetUpdatesParams{AllowedUpdates: allowedUpdates})
+		if err != nil {
+			stopPolling()
+			return nil, err
+		}
 		go func() {
 			for u := range pollingUpdates {
 				updates <- u
 			}
 		}()
-		
+
 		go bh.Start()
 	}
 
-	return webhookHandler, tb
+	return bot, nil
+}
+
+// Shutdown para de receber updates, aguarda os handlers em andamento e drena a
+// fila de posts de canal. O servidor HTTP (webhook) deve ser encerrado antes.
+func (b *Bot) Shutdown(ctx context.Context) error {
+	logger.Bot("🔻 Parando recebimento de updates...")
+	if b.stopPolling != nil {
+		b.stopPolling()
+	}
+	handlerErr := b.handler.StopWithContext(ctx)
+	return errors.Join(handlerErr, channelpost.ShutdownQueueTelego(ctx))
 }
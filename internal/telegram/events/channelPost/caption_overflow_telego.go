@@ -0,0 +1,69 @@
+package channelpost
+
+import (
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+)
+
+// fitCaptionTelego monta o texto final com compose e, quando ele passa do
+// limite do Telegram, aplica a política do canal para legendas longas.
+func fitCaptionTelego(c *container.AppContainer, pCtx *ProcessingContextTelego, original string, compose func(string) string) captiontpl.Fitted {
+	policy := pCtx.Channel.CaptionOverflow
+	if !captiontpl.IsValidOverflow(policy) {
+		policy = captiontpl.OverflowShorten
+	}
+	// A resposta com o excedente é enviada uma vez só: edições (e posts que o
+	// bot não pode editar) encurtam o original.
+	if policy == captiontpl.OverflowReply && (pCtx.IsEdit || !pCtx.Permissions.CanEdit) {
+		policy = captiontpl.OverflowShorten
+	}
+
+	limit := textLimitTelego(pCtx)
+	fitted := captiontpl.Fit(original, compose, policy, limit)
+	if fitted.Action != "" {
+		logger.BotCtx(pCtx.Ctx, "📏 Texto com %d caracteres passa do limite de %d, política %s aplicada", fitted.Length, limit, fitted.Action)
+		recordChannelPostEvent(c, pCtx, "caption_overflow", services.ChannelEventStatusInfo, map[string]any{"policy": fitted.Action, "length": fitted.Length, "limit": limit}, nil)
+	}
+	return fitted
+}
+
+// sendOverflowReplyTelego envia o trecho que não coube no post como resposta a
+// ele. Álbuns reenviados (áudios e documentos) não têm mais a mensagem
+// original, então o excedente vai como mensagem comum.
+func sendOverflowReplyTelego(pCtx *ProcessingContextTelego) error {
+	text, _ := tghtml.Truncate(pCtx.OverflowText, tghtml.MaxTextLength)
+	params := &telego.SendMessageParams{
+		ChatID:             telego.ChatID{ID: pCtx.Channel.ID},
+		Text:               text,
+		ParseMode:          telego.ModeHTML,
+		LinkPreviewOptions: &telego.LinkPreviewOptions{IsDisabled: pCtx.DisableLinkPreview},
+	}
+	if replyTo := overflowReplyTargetTelego(pCtx); replyTo != 0 {
+		params.ReplyParameters = &telego.ReplyParameters{MessageID: replyTo, AllowSendingWithoutReply: true}
+	}
+
+	_, err := pCtx.Bot.SendMessage(pCtx.Ctx, params)
+	return err
+}
+
+func overflowReplyTargetTelego(pCtx *ProcessingContextTelego) int {
+	if !pCtx.IsMediaGroup {
+		if post := pCtx.Update.ChannelPost; post != nil {
+			return post.MessageID
+		}
+		return 0
+	}
+	if pCtx.MessageType == MessageTypeAudio || pCtx.MessageType == MessageTypeDocument || len(pCtx.GroupMessages) == 0 {
+		return 0
+	}
+	for _, message := range pCtx.GroupMessages {
+		if message.HasCaption {
+			return message.MessageID
+		}
+	}
+	return pCtx.GroupMessages[0].MessageID
+}
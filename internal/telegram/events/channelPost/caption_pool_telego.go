@@ -0,0 +1,55 @@
+package channelpost
+
+import (
+	"math/rand/v2"
+
+	"github.com/leirbagxis/FreddyBot/internal/captionpool"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+// pickCaptionVariantTelego escolhe a variante do pool de legendas do canal.
+// Nas edições a variante já aplicada é mantida, para que o texto não mude a
+// cada edição; reused indica esse caso, que não conta como novo uso.
+func pickCaptionVariantTelego(c *container.AppContainer, pCtx *ProcessingContextTelego) (variant *dbmodels.CaptionVariant, reused bool) {
+	channel := pCtx.Channel
+	mode := channel.CaptionRotation
+	if mode == captionpool.ModeOff || len(channel.CaptionVariants) == 0 {
+		return nil, false
+	}
+
+	if pCtx.AppliedVariantID != "" {
+		for i := range channel.CaptionVariants {
+			if channel.CaptionVariants[i].VariantID == pCtx.AppliedVariantID {
+				return &channel.CaptionVariants[i], true
+			}
+		}
+	}
+
+	var seq int64
+	if captionpool.NeedsSequence(mode) {
+		next, err := c.CacheService.NextCaptionRotation(pCtx.Ctx, channel.ID)
+		if err != nil {
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao avançar rodízio de legendas do canal %d: %v", channel.ID, err)
+			next = rand.Int64()
+		}
+		seq = next
+	}
+
+	return captionpool.Pick(channel.CaptionVariants, mode, seq, postTimeTelego(pCtx), rand.IntN), false
+}
+
+func convertVariantButtons(vbs []dbmodels.CaptionVariantButton) []dbmodels.Button {
+	btns := make([]dbmodels.Button, len(vbs))
+	for i, vb := range vbs {
+		btns[i] = dbmodels.Button{
+			ButtonID:   vb.ButtonID,
+			NameButton: vb.NameButton,
+			ButtonURL:  vb.ButtonURL,
+			PositionX:  vb.PositionX,
+			PositionY:  vb.PositionY,
+		}
+	}
+	return btns
+}
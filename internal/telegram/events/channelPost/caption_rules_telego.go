@@ -0,0 +1,105 @@
+package channelpost
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/captionrule"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/config"
+	"github.com/mymmrac/telego"
+)
+
+// captionSelectionTelego é a legenda customizada escolhida para o post e o
+// motivo da escolha (regra e/ou hashtag).
+type captionSelectionTelego struct {
+	Custom  *dbmodels.CustomCaption
+	Rule    *dbmodels.CaptionRule
+	Hashtag string
+}
+
+// selectCustomCaptionTelego escolhe a legenda customizada do post. As regras do
+// canal têm preferência; sem regra, vale a primeira hashtag do texto com
+// legenda cadastrada e, nas edições, a legenda aplicada anteriormente.
+func selectCustomCaptionTelego(pCtx *ProcessingContextTelego, formattedBase string) captionSelectionTelego {
+	channel := pCtx.Channel
+	hashtags := extractHashtags(formattedBase)
+
+	if len(channel.CaptionRules) > 0 {
+		in := captionrule.Input{
+			Hashtags:      hashtags,
+			Text:          htmlToPlainText(formattedBase),
+			MessageType:   string(pCtx.MessageType),
+			ForwardedFrom: forwardOriginTelego(pCtx.Update.ChannelPost),
+			Time:          postTimeTelego(pCtx),
+		}
+		if match := captionrule.Evaluate(channel.CaptionRules, in); match != nil {
+			if custom := findCustomCaptionByID(channel, match.Rule.CaptionID); custom != nil {
+				return captionSelectionTelego{Custom: custom, Rule: match.Rule, Hashtag: match.Hashtag}
+			}
+		}
+	}
+
+	for _, hashtag := range hashtags {
+		if custom := findCustomCaption(channel, hashtag); custom != nil {
+			return captionSelectionTelego{Custom: custom, Hashtag: hashtag}
+		}
+	}
+
+	if custom := findCustomCaption(channel, pCtx.AppliedHashtag); custom != nil {
+		return captionSelectionTelego{Custom: custom}
+	}
+	return captionSelectionTelego{}
+}
+
+func findCustomCaptionByID(channel *dbmodels.Channel, captionID string) *dbmodels.CustomCaption {
+	for i := range channel.CustomCaptions {
+		if channel.CustomCaptions[i].CaptionID == captionID {
+			return &channel.CustomCaptions[i]
+		}
+	}
+	return nil
+}
+
+// forwardOriginTelego devolve o ID e o username da origem de um post
+// encaminhado, usados nas regras por fonte.
+func forwardOriginTelego(post *telego.Message) []string {
+	if post == nil || post.ForwardOrigin == nil {
+		return nil
+	}
+
+	var chat *telego.Chat
+	switch origin := post.ForwardOrigin.(type) {
+	case *telego.MessageOriginChannel:
+		chat = &origin.Chat
+	case *telego.MessageOriginChat:
+		chat = &origin.SenderChat
+	case *telego.MessageOriginUser:
+		sources := []string{strconv.FormatInt(origin.SenderUser.ID, 10)}
+		if origin.SenderUser.Username != "" {
+			sources = append(sources, origin.SenderUser.Username)
+		}
+		return sources
+	case *telego.MessageOriginHiddenUser:
+		return []string{origin.SenderUserName}
+	default:
+		return nil
+	}
+
+	sources := []string{strconv.FormatInt(chat.ID, 10)}
+	if chat.Username != "" {
+		sources = append(sources, chat.Username)
+	}
+	return sources
+}
+
+// postTimeTelego devolve a data de publicação do post no fuso configurado
+// (TIMEZONE), usada nas regras por horário e no rodízio por dia da semana. Usar a data do post, e não a hora em que a fila o processa,
+// mantém o resultado estável em atrasos da fila, novas tentativas e edições.
+func postTimeTelego(pCtx *ProcessingContextTelego) time.Time {
+	t := time.Now()
+	if post := pCtx.Update.ChannelPost; post != nil && post.Date > 0 {
+		t = time.Unix(post.Date, 0)
+	}
+	return t.In(config.Location)
+}
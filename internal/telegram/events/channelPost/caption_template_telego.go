@@ -0,0 +1,127 @@
+package channelpost
+
+import (
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/config"
+	"github.com/mymmrac/telego"
+)
+
+// captionTemplateDataTelego monta as variáveis do template de legenda a partir
+// do post. originalCaption deve estar em HTML (saída de ProcessTextWithFormattingTelego).
+func captionTemplateDataTelego(pCtx *ProcessingContextTelego, originalCaption string) captiontpl.Data {
+	data := captiontpl.Data{
+		MessageType:     string(pCtx.MessageType),
+		OriginalCaption: originalCaption,
+	}
+	if pCtx.Channel != nil {
+		data.ChannelTitle = pCtx.Channel.Title
+		data.ChannelLink = pCtx.Channel.InviteURL
+	}
+
+	post := pCtx.Update.ChannelPost
+	if post == nil {
+		return data
+	}
+	if post.Chat.Title != "" {
+		data.ChannelTitle = post.Chat.Title
+	}
+	if post.Chat.Username != "" {
+		data.ChannelLink = "https://t.me/" + post.Chat.Username
+	}
+	// A data do post é mantida nas edições, então a legenda renderizada não muda.
+	// O fuso é o mesmo das regras por horário e do rodízio por dia da semana.
+	if post.Date > 0 {
+		data.Date = time.Unix(post.Date, 0).In(config.Location)
+	}
+	data.FileName, data.Duration = fileInfoTelego(post)
+	data.Author = authorSignatureTelego(pCtx.Channel, post.AuthorSignature)
+	return data
+}
+
+// authorSignatureTelego troca a assinatura do post pelo apelido cadastrado no
+// canal; assinaturas sem apelido são usadas como vieram.
+func authorSignatureTelego(channel *dbmodels.Channel, signature string) string {
+	signature = strings.TrimSpace(signature)
+	if signature == "" || channel == nil {
+		return signature
+	}
+	for _, s := range channel.AuthorSignatures {
+		if strings.EqualFold(s.Signature, signature) {
+			return s.Label
+		}
+	}
+	return signature
+}
+
+// signatureFormatTelego retorna o template do rodapé de assinatura do canal.
+func signatureFormatTelego(channel *dbmodels.Channel) string {
+	if channel.SignatureFormat == "" {
+		return captiontpl.DefaultSignatureFormat
+	}
+	return channel.SignatureFormat
+}
+
+// signatureFooterTelego renderiza o rodapé de assinatura do post, vazio quando
+// o canal não usa o rodapé ou o post não é assinado.
+func signatureFooterTelego(pCtx *ProcessingContextTelego) string {
+	if pCtx.Channel == nil || !pCtx.Channel.SignatureFooter {
+		return ""
+	}
+	data := captionTemplateDataTelego(pCtx, "")
+	if data.Author == "" {
+		return ""
+	}
+	footer, placesOriginal := renderCaptionTelego(signatureFormatTelego(pCtx.Channel), data)
+	if placesOriginal {
+		return ""
+	}
+	return footer
+}
+
+// renderCaptionTelego renderiza o template da legenda do canal. placesOriginal
+// indica que o template já posiciona o texto original via {original_caption}.
+func renderCaptionTelego(tpl string, data captiontpl.Data) (html string, placesOriginal bool) {
+	t, err := captiontpl.Parse(tpl)
+	if err != nil {
+		return DetectParseMode(tpl), false
+	}
+	return t.Render(data), t.Uses(captiontpl.VarOriginalCaption)
+}
+
+// captionLayoutTelego resolve posição e separador da legenda: a legenda
+// customizada sobrescreve o canal quando define os próprios valores.
+func captionLayoutTelego(channel *dbmodels.Channel, custom *dbmodels.CustomCaption) (position, separator string) {
+	if channel != nil {
+		position, separator = channel.CaptionPosition, channel.CaptionSeparator
+	}
+	if custom != nil {
+		if custom.Position != "" {
+			position = custom.Position
+		}
+		if custom.Separator != "" {
+			separator = custom.Separator
+		}
+	}
+	if !captiontpl.IsValidPosition(position) {
+		position = captiontpl.PositionAppend
+	}
+	return position, separator
+}
+
+func fileInfoTelego(post *telego.Message) (string, int) {
+	switch {
+	case post.Audio != nil:
+		return post.Audio.FileName, post.Audio.Duration
+	case post.Video != nil:
+		return post.Video.FileName, post.Video.Duration
+	case post.Animation != nil:
+		return post.Animation.FileName, post.Animation.Duration
+	case post.Document != nil:
+		return post.Document.FileName, 0
+	}
+	return "", 0
+}
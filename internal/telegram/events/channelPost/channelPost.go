@@ -3,10 +3,14 @@ package channelpost
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/container"
 	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
+	"github.com/leirbagxis/FreddyBot/internal/queue"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 	"github.com/mymmrac/telego"
 	"github.com/mymmrac/telego/telegohandler"
@@ -18,20 +22,29 @@ type Job interface {
 	GetChannelID() int64
 }
 
+// MessageQueue distribui os jobs entre os workers mantendo uma fila FIFO por
+// canal: um canal nunca tem dois jobs em execução ao mesmo tempo e os canais
+// são atendidos em rodízio, para que um canal muito ativo não atrase os outros.
 type MessageQueue struct {
-	queue       chan Job
-	mu          sync.Mutex
-	isRunning   bool
+	scheduler   *queue.FairScheduler[Job]
 	lastProcess sync.Map // map[int64]time.Time
+	queued      sync.Map // map[string]struct{}: jobs persistidos na fila em memória
+	app         atomic.Pointer[container.AppContainer]
+	workers     sync.WaitGroup
 }
 
 type PipelineJobTelego struct {
 	Ctx      *ProcessingContextTelego
 	Pipeline *PipelineTelego
+	Record   *dbmodels.QueueJob // nil quando a fila persistente não está ativa
 }
 
 func (j PipelineJobTelego) Run() error {
-	return j.Pipeline.Execute(j.Ctx)
+	if err := j.Pipeline.Execute(j.Ctx); err != nil {
+		return err
+	}
+	// Um panic recuperado pela pipeline só aparece em Ctx.Error.
+	return j.Ctx.Error
 }
 
 func (j PipelineJobTelego) GetChannelID() int64 {
@@ -53,59 +66,143 @@ func init() {
 
 func NewMessageQueue() *MessageQueue {
 	mq := &MessageQueue{
-		queue:     make(chan Job, 5000),
-		isRunning: true,
+		scheduler: queue.NewFairScheduler[Job](5000),
 	}
+	queue.Register(services.QueueChannelPost, mq.scheduler.Stats)
+	metrics.RegisterQueueDepth(services.QueueChannelPost, func() float64 {
+		return float64(mq.scheduler.Len())
+	})
 	// Iniciar 20 workers para processamento paralelo (ideal para 2 vCPUs + I/O)
 	for i := 0; i < 20; i++ {
+		mq.workers.Add(1)
 		go mq.worker()
 	}
 	return mq
 }
 
 func (mq *MessageQueue) worker() {
-	for mq.isRunning {
-		select {
-		case job := <-mq.queue:
-			// Controle per-chat: evita processar mensagens do mesmo chat muito rápido
-			channelID := job.GetChannelID()
-			if channelID != 0 {
-				last, ok := mq.lastProcess.Load(channelID)
-				if ok {
-					elapsed := time.Since(last.(time.Time))
-					if elapsed < 500*time.Millisecond {
-						time.Sleep(500*time.Millisecond - elapsed)
-					}
-				}
-				mq.lastProcess.Store(channelID, time.Now())
-			}
+	defer mq.workers.Done()
+	for {
+		lease, ok := mq.scheduler.Next()
+		if !ok {
+			return
+		}
+		metrics.QueueWait.WithLabelValues(services.QueueChannelPost).Observe(lease.Wait.Seconds())
+		mq.process(lease.Key, lease.Job)
+		mq.scheduler.Done(lease)
+	}
+}
 
-			if err := job.Run(); err != nil {
-				logger.Error("BOT", "❌ Erro ao processar job da fila: %v", err)
+func (mq *MessageQueue) process(channelID int64, job Job) {
+	// Controle per-chat: mantém um intervalo mínimo entre posts do mesmo canal
+	if channelID != 0 {
+		last, ok := mq.lastProcess.Load(channelID)
+		if ok {
+			elapsed := time.Since(last.(time.Time))
+			if elapsed < 500*time.Millisecond {
+				time.Sleep(500*time.Millisecond - elapsed)
 			}
 		}
+		mq.lastProcess.Store(channelID, time.Now())
+	}
+
+	if durable, ok := job.(PipelineJobTelego); ok && durable.Record != nil {
+		mq.runDurable(durable)
+		return
+	}
+
+	pipelineJob, isPipelineJob := job.(PipelineJobTelego)
+	logCtx := context.Background()
+	if isPipelineJob && pipelineJob.Ctx.Ctx != nil {
+		logCtx = pipelineJob.Ctx.Ctx
+	}
+
+	err := job.Run()
+	if err != nil {
+		logger.ErrorCtx(logCtx, "BOT", "❌ Erro ao processar job da fila: %v", err)
+	}
+	if isPipelineJob {
+		result := "processed"
+		if err != nil {
+			result = "failed"
+		}
+		observeQueueJob(pipelineJob, result)
+	}
+}
+
+func observeQueueJob(job PipelineJobTelego, result string) {
+	metrics.QueueJobs.WithLabelValues(services.QueueChannelPost, metrics.LabelOrUnknown(string(job.Ctx.MessageType)), result).Inc()
+}
+
+// runDurable executa um job persistido: o job é assumido no banco logo antes de
+// rodar e, ao final, confirmado ou reagendado conforme o erro.
+func (mq *MessageQueue) runDurable(job PipelineJobTelego) {
+	defer mq.untrackQueued(job.Record.ID)
+
+	c := mq.app.Load()
+	claimed, err := c.JobQueueService.Claim(context.Background(), job.Record.ID)
+	if err != nil {
+		logger.ErrorCtx(job.Ctx.Ctx, "QUEUE", "❌ Erro ao assumir job %s: %v", job.Record.ID, err)
+		return
+	}
+	if claimed == nil {
+		// Outra réplica já assumiu ou concluiu o job.
+		return
 	}
+	// A linha assumida substitui a cópia em memória: traz as tentativas atuais
+	// e o token que prova que o job ainda pertence a esta execução ao final.
+	job.Record = claimed
+
+	trackJobStepsTelego(c, job)
+	stopHeartbeat := keepJobAliveTelego(c, job)
+	err = job.Run()
+	stopHeartbeat()
+	if err != nil {
+		logger.ErrorCtx(job.Ctx.Ctx, "BOT", "❌ Erro ao processar job da fila: %v", err)
+	}
+	finishPipelineJobTelego(c, job, err)
 }
 
 func (mq *MessageQueue) AddTelegoToQueue(pCtx *ProcessingContextTelego, pipeline *PipelineTelego) {
-	select {
-	case mq.queue <- PipelineJobTelego{Ctx: pCtx, Pipeline: pipeline}:
-		logger.Bot("📥 Mensagem Telego adicionada à fila (tamanho: %d)", len(mq.queue))
-	default:
-		logger.Bot("⚠️ Fila cheia, descartando mensagem Telego")
+	job := PipelineJobTelego{Ctx: pCtx, Pipeline: pipeline}
+
+	if c := mq.app.Load(); c != nil {
+		record, err := persistPipelineJobTelego(c, pCtx)
+		if err != nil {
+			logger.ErrorCtx(pCtx.Ctx, "QUEUE", "❌ Erro ao persistir job, seguindo apenas em memória: %v", err)
+		} else {
+			job.Record = record
+			mq.trackQueued(record.ID)
+		}
+	}
+
+	if mq.scheduler.TryPush(job.GetChannelID(), job) {
+		observeQueueJob(job, "enqueued")
+		logger.BotCtx(pCtx.Ctx, "📥 Mensagem Telego adicionada à fila (tamanho: %d)", mq.scheduler.Len())
+		return
 	}
+	if job.Record != nil {
+		mq.untrackQueued(job.Record.ID)
+		observeQueueJob(job, "deferred")
+		logger.BotCtx(pCtx.Ctx, "⚠️ Fila cheia, job %s fica salvo para o poller", job.Record.ID)
+		return
+	}
+	observeQueueJob(job, "dropped")
+	logger.BotCtx(pCtx.Ctx, "⚠️ Fila cheia, descartando mensagem Telego")
 }
 
 func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 	return func(ctx *telegohandler.Context, update telego.Update) error {
 		if update.EditedChannelPost != nil {
-			logger.Bot("⏭️ Edição de post de canal ignorada: %d", update.EditedChannelPost.MessageID)
+			handleEditedChannelPostTelego(ctx, c, update)
 			return nil
 		}
 
+		updateCtx := updateContextTelego(ctx)
+
 		if update.ChannelPost != nil {
-			logger.Bot("🚀 [%d] Novo post recebido no canal %d (Telego)", update.ChannelPost.MessageID, update.ChannelPost.Chat.ID)
-			c.ChannelEventService.Record(context.Background(), services.ChannelEventRecordInput{
+			logger.BotCtx(updateCtx, "🚀 [%d] Novo post recebido no canal %d (Telego)", update.ChannelPost.MessageID, update.ChannelPost.Chat.ID)
+			c.ChannelEventService.Record(updateCtx, services.ChannelEventRecordInput{
 				ChannelID:         update.ChannelPost.Chat.ID,
 				ChannelTitle:      update.ChannelPost.Chat.Title,
 				Source:            services.ChannelEventSourceChannelPost,
@@ -117,24 +214,42 @@ func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 		}
 
 		// 1. Execution Pipeline
-		executionPipeline := NewPipelineTelego(
-			"Execution",
-			StageTransformTelego(c),
-			StageDecorateTelego(c),
-			StageSendTelego(c),
-		)
+		executionPipeline := newExecutionPipelineTelego(c)
 
 		// 2. Discovery Pipeline
 		discoveryPipeline := NewPipelineTelego(
 			"Discovery",
 			StagePreflightTelego(c),
+			StageFilterTelego(c),
 			StageSpecialFlowsTelego(c),
 			StageMediaGroupingTelego(c, executionPipeline),
 			StageQueueTelego(c, executionPipeline),
 		)
 
-		pCtx := NewProcessingContextTelego(context.Background(), ctx.Bot(), update, discoveryPipeline)
+		pCtx := NewProcessingContextTelego(updateCtx, ctx.Bot(), update, discoveryPipeline)
 		_ = discoveryPipeline.Execute(pCtx)
 		return nil
 	}
 }
+
+// updateContextTelego retorna o contexto do update com os campos de log
+// (correlation_id, update_id...), desligado do cancelamento do handler para
+// sobreviver à fila.
+func updateContextTelego(ctx *telegohandler.Context) context.Context {
+	updateCtx := context.WithoutCancel(ctx.Context())
+	if logger.CorrelationID(updateCtx) == "" {
+		updateCtx = logger.WithCorrelationID(updateCtx, logger.NewCorrelationID())
+	}
+	return updateCtx
+}
+
+func newExecutionPipelineTelego(c *container.AppContainer) *PipelineTelego {
+	return NewPipelineTelego(
+		"Execution",
+		StageTransformTelego(c),
+		StageTranslateTelego(c),
+		StageDecorateTelego(c),
+		StageSanitizeTelego(c),
+		StageSendTelego(c),
+	)
+}
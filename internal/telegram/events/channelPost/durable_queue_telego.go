@@ -0,0 +1,372 @@
+package channelpost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
+)
+
+const (
+	queueJobKindPost = "post"
+	queueJobKindEdit = "edit"
+
+	// jobStepOverflowReply marca a resposta com o excedente da legenda como enviada.
+	jobStepOverflowReply = "overflow_reply"
+
+	durableQueuePollInterval = 5 * time.Second
+	durableQueuePollBatch    = 50
+)
+
+// pipelineJobSnapshot é o estado mínimo salvo no banco para reconstruir um job
+// da Execution pipeline depois de um restart ou de uma nova tentativa.
+type pipelineJobSnapshot struct {
+	Kind             string               `json:"kind"`
+	Update           telego.Update        `json:"update"`
+	IsMediaGroup     bool                 `json:"isMediaGroup,omitempty"`
+	MediaGroupID     string               `json:"mediaGroupId,omitempty"`
+	GroupMessages    []MediaMessageTelego `json:"groupMessages,omitempty"`
+	AppliedHashtag   string               `json:"appliedHashtag,omitempty"`
+	AppliedVariantID string               `json:"appliedVariantId,omitempty"`
+	CorrelationID    string               `json:"correlationId,omitempty"`
+}
+
+// StartDurableQueueTelego liga a fila em memória ao banco: devolve para a fila
+// os jobs interrompidos no último encerramento e passa a buscar periodicamente
+// jobs pendentes (novas tentativas e jobs que não couberam na fila em memória).
+// Deve ser chamado antes de os handlers começarem a receber updates.
+func StartDurableQueueTelego(ctx context.Context, c *container.AppContainer) {
+	messageQueue.app.Store(c)
+	go messageQueue.runPoller(ctx, c)
+}
+
+// ShutdownQueueTelego para de aceitar novos jobs e aguarda os workers
+// esvaziarem a fila em memória. Se o ctx expirar antes, os jobs restantes
+// continuam salvos no banco e são retomados no próximo start.
+func ShutdownQueueTelego(ctx context.Context) error {
+	messageQueue.scheduler.Close()
+
+	done := make(chan struct{})
+	go func() {
+		messageQueue.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Bot("✅ Fila de posts drenada")
+		return nil
+	case <-ctx.Done():
+		logger.Warn("QUEUE", "⚠️ Tempo esgotado drenando a fila, %d job(s) ficam para o próximo start", messageQueue.scheduler.Len())
+		return ctx.Err()
+	}
+}
+
+func (mq *MessageQueue) runPoller(ctx context.Context, c *container.AppContainer) {
+	ticker := time.NewTicker(durableQueuePollInterval)
+	defer ticker.Stop()
+
+	mq.releaseStale(ctx, c)
+	mq.pollReady(ctx, c)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mq.releaseStale(ctx, c)
+			mq.pollReady(ctx, c)
+		}
+	}
+}
+
+// releaseStale devolve à fila jobs abandonados por uma réplica que caiu no
+// meio do processamento. Roda a cada ciclo, não só no startup, porque com
+// várias réplicas a que travou pode nunca reiniciar.
+func (mq *MessageQueue) releaseStale(ctx context.Context, c *container.AppContainer) {
+	released, err := c.JobQueueService.ReleaseProcessing(ctx, services.QueueChannelPost)
+	if err != nil {
+		logger.Error("QUEUE", "❌ Erro ao liberar jobs interrompidos: %v", err)
+	} else if released > 0 {
+		logger.Bot("♻️ %d job(s) interrompido(s) devolvido(s) à fila", released)
+	}
+}
+
+func (mq *MessageQueue) pollReady(ctx context.Context, c *container.AppContainer) {
+	jobs, err := c.JobQueueService.ListReady(ctx, services.QueueChannelPost, durableQueuePollBatch)
+	if err != nil {
+		logger.Error("QUEUE", "❌ Erro ao buscar jobs pendentes: %v", err)
+		return
+	}
+
+	// O poller não assume os jobs: eles entram na fila em memória ainda
+	// pendentes e só são assumidos pelo worker, logo antes de rodar. Assim um
+	// job parado atrás de um canal lento não é liberado como abandonado e
+	// executado de novo por outro ciclo do poller.
+	for i := range jobs {
+		record := jobs[i]
+		if !mq.trackQueued(record.ID) {
+			// Já está na fila em memória desta réplica.
+			continue
+		}
+
+		job, err := rehydratePipelineJobTelego(ctx, c, &record)
+		if err != nil {
+			mq.untrackQueued(record.ID)
+			logger.Error("QUEUE", "❌ Job %s não pôde ser reconstruído: %v", record.ID, err)
+			claimed, claimErr := c.JobQueueService.Claim(ctx, record.ID)
+			if claimErr != nil || claimed == nil {
+				continue
+			}
+			if _, failErr := c.JobQueueService.Fail(ctx, claimed, err, true); failErr != nil {
+				logger.Error("QUEUE", "❌ Erro ao mover job %s para a dead-letter: %v", record.ID, failErr)
+			}
+			continue
+		}
+
+		if err := mq.scheduler.Push(ctx, job.GetChannelID(), job); err != nil {
+			mq.untrackQueued(record.ID)
+			return
+		}
+	}
+}
+
+// trackQueued registra que o job está na fila em memória. Retorna false quando
+// ele já estava, para o poller não empurrar a mesma linha duas vezes.
+func (mq *MessageQueue) trackQueued(id string) bool {
+	_, loaded := mq.queued.LoadOrStore(id, struct{}{})
+	return !loaded
+}
+
+func (mq *MessageQueue) untrackQueued(id string) {
+	mq.queued.Delete(id)
+}
+
+// persistPipelineJobTelego salva o snapshot do job antes de ele entrar na fila em memória.
+func persistPipelineJobTelego(c *container.AppContainer, pCtx *ProcessingContextTelego) (*dbmodels.QueueJob, error) {
+	post := pCtx.Update.ChannelPost
+	if post == nil {
+		return nil, fmt.Errorf("job sem channel_post")
+	}
+
+	snapshot := pipelineJobSnapshot{
+		Kind:             queueJobKindPost,
+		Update:           pCtx.Update,
+		IsMediaGroup:     pCtx.IsMediaGroup,
+		MediaGroupID:     pCtx.MediaGroupID,
+		GroupMessages:    pCtx.GroupMessages,
+		AppliedHashtag:   pCtx.AppliedHashtag,
+		AppliedVariantID: pCtx.AppliedVariantID,
+		CorrelationID:    logger.CorrelationID(pCtx.Ctx),
+	}
+	if pCtx.IsEdit {
+		snapshot.Kind = queueJobKindEdit
+	}
+
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	channelID := post.Chat.ID
+	if pCtx.Channel != nil {
+		channelID = pCtx.Channel.ID
+	}
+
+	return c.JobQueueService.Enqueue(pCtx.Ctx, services.QueueJobInput{
+		Queue:     services.QueueChannelPost,
+		Kind:      snapshot.Kind,
+		ChannelID: channelID,
+		MessageID: post.MessageID,
+		Payload:   string(payload),
+	})
+}
+
+// rehydratePipelineJobTelego reconstrói o contexto a partir do snapshot salvo,
+// recarregando canal e permissões para refletir o estado atual.
+func rehydratePipelineJobTelego(ctx context.Context, c *container.AppContainer, record *dbmodels.QueueJob) (PipelineJobTelego, error) {
+	var snapshot pipelineJobSnapshot
+	if err := json.Unmarshal([]byte(record.Payload), &snapshot); err != nil {
+		return PipelineJobTelego{}, fmt.Errorf("payload inválido: %w", err)
+	}
+
+	post := snapshot.Update.ChannelPost
+	if post == nil {
+		return PipelineJobTelego{}, fmt.Errorf("payload sem channel_post")
+	}
+
+	channel, err := c.ChannelService.GetChannelWithRelations(ctx, post.Chat.ID)
+	if err != nil {
+		return PipelineJobTelego{}, fmt.Errorf("canal %d não encontrado: %w", post.Chat.ID, err)
+	}
+
+	pipeline := newExecutionPipelineTelego(c)
+	if snapshot.Kind == queueJobKindEdit {
+		pipeline = newEditExecutionPipelineTelego(c)
+	}
+
+	// O correlation ID original acompanha o post em todas as tentativas.
+	correlationID := snapshot.CorrelationID
+	if correlationID == "" {
+		correlationID = logger.NewCorrelationID()
+	}
+	jobCtx := logger.WithCorrelationID(context.Background(), correlationID)
+	jobCtx = logger.With(jobCtx, logger.FieldUpdateID, snapshot.Update.UpdateID)
+	jobCtx = logger.With(jobCtx, logger.FieldChannelID, post.Chat.ID)
+
+	messageType := GetMessageTypeTelego(post)
+	pCtx := &ProcessingContextTelego{
+		Ctx:              jobCtx,
+		Bot:              c.TelegoBot,
+		Update:           snapshot.Update,
+		MessageType:      messageType,
+		Channel:          channel,
+		Permissions:      GetPermissionManager().CheckPermissions(channel, messageType),
+		IsMediaGroup:     snapshot.IsMediaGroup,
+		MediaGroupID:     snapshot.MediaGroupID,
+		GroupMessages:    snapshot.GroupMessages,
+		IsEdit:           snapshot.Kind == queueJobKindEdit,
+		AppliedHashtag:   snapshot.AppliedHashtag,
+		AppliedVariantID: snapshot.AppliedVariantID,
+		Pipeline:         pipeline,
+	}
+
+	return PipelineJobTelego{Ctx: pCtx, Pipeline: pipeline, Record: record}, nil
+}
+
+// jobStepResendTelego marca um item de álbum já reenviado (áudios e documentos).
+func jobStepResendTelego(messageID int) string {
+	return fmt.Sprintf("resend:%d", messageID)
+}
+
+// stepDoneTelego indica se o passo já foi concluído por uma execução anterior
+// do job. Novas tentativas reexecutam a pipeline inteira, e edições podem ser
+// repetidas, mas envios não.
+func stepDoneTelego(pCtx *ProcessingContextTelego, step string) bool {
+	return pCtx.CompletedSteps[step]
+}
+
+// markStepDoneTelego registra o passo concluído na execução atual e no job persistido.
+func markStepDoneTelego(pCtx *ProcessingContextTelego, step string) {
+	if pCtx.CompletedSteps == nil {
+		pCtx.CompletedSteps = make(map[string]bool)
+	}
+	pCtx.CompletedSteps[step] = true
+	if pCtx.OnStepDone != nil {
+		pCtx.OnStepDone(step)
+	}
+}
+
+// trackJobStepsTelego liga o contexto do job aos passos salvos na linha assumida.
+func trackJobStepsTelego(c *container.AppContainer, job PipelineJobTelego) {
+	ctx := context.WithoutCancel(job.Ctx.Ctx)
+	job.Ctx.CompletedSteps = services.QueueJobCompletedSteps(job.Record)
+	job.Ctx.OnStepDone = func(step string) {
+		err := c.JobQueueService.CompleteStep(ctx, job.Record, step)
+		if errors.Is(err, services.ErrQueueJobLost) {
+			logger.WarnCtx(ctx, "QUEUE", "⚠️ Job %s foi assumido por outra execução, passo %s não registrado", job.Record.ID, step)
+		} else if err != nil {
+			logger.ErrorCtx(ctx, "QUEUE", "❌ Erro ao registrar passo %s do job %s: %v", step, job.Record.ID, err)
+		}
+	}
+}
+
+// keepJobAliveTelego renova o job no banco enquanto ele roda, para um post lento
+// (álbum grande, flood wait) não ser liberado como abandonado e executado de
+// novo por outra réplica. A função retornada para o heartbeat.
+func keepJobAliveTelego(c *container.AppContainer, job PipelineJobTelego) func() {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(job.Ctx.Ctx))
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(services.QueueJobHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := c.JobQueueService.Heartbeat(ctx, job.Record)
+				if errors.Is(err, services.ErrQueueJobLost) {
+					logger.WarnCtx(ctx, "QUEUE", "⚠️ Job %s foi assumido por outra execução enquanto rodava", job.Record.ID)
+					return
+				}
+				if err != nil && ctx.Err() == nil {
+					logger.ErrorCtx(ctx, "QUEUE", "❌ Erro ao renovar job %s: %v", job.Record.ID, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// finishPipelineJobTelego confirma ou reagenda o job no banco conforme o resultado da execução.
+func finishPipelineJobTelego(c *container.AppContainer, job PipelineJobTelego, runErr error) {
+	ctx := job.Ctx.Ctx
+	record := job.Record
+
+	if runErr == nil || isMessageNotModifiedTelego(runErr) {
+		observeQueueJob(job, "processed")
+		if err := c.JobQueueService.Ack(ctx, record); errors.Is(err, services.ErrQueueJobLost) {
+			logger.WarnCtx(ctx, "QUEUE", "⚠️ Job %s foi assumido por outra execução enquanto rodava", record.ID)
+		} else if err != nil {
+			logger.ErrorCtx(ctx, "QUEUE", "❌ Erro ao confirmar job %s: %v", record.ID, err)
+		}
+		return
+	}
+
+	dead, err := c.JobQueueService.Fail(ctx, record, runErr, isPermanentTelegoError(runErr))
+	if errors.Is(err, services.ErrQueueJobLost) {
+		logger.WarnCtx(ctx, "QUEUE", "⚠️ Job %s foi assumido por outra execução enquanto rodava, falha descartada", record.ID)
+		return
+	}
+	if err != nil {
+		logger.ErrorCtx(ctx, "QUEUE", "❌ Erro ao registrar falha do job %s: %v", record.ID, err)
+		return
+	}
+
+	if dead {
+		observeQueueJob(job, "dead_lettered")
+		logger.ErrorCtx(ctx, "QUEUE", "☠️ Job %s movido para a dead-letter após %d tentativa(s): %v", record.ID, record.Attempts, runErr)
+		recordChannelPostEvent(c, job.Ctx, "post_dead_lettered", services.ChannelEventStatusError, map[string]any{"job_id": record.ID, "attempts": record.Attempts}, runErr)
+		return
+	}
+
+	observeQueueJob(job, "retried")
+	delay := services.QueueJobBackoff(record.Attempts)
+	logger.BotCtx(ctx, "🔁 Job %s reagendado em %s (tentativa %d/%d)", record.ID, delay, record.Attempts, services.QueueJobMaxAttempts)
+	recordChannelPostEvent(c, job.Ctx, "post_retry_scheduled", services.ChannelEventStatusInfo, map[string]any{"job_id": record.ID, "attempt": record.Attempts, "retry_in_seconds": int(delay.Seconds())}, runErr)
+}
+
+// isPermanentTelegoError indica erros da API que não mudam com novas tentativas
+// (mensagem apagada, bot removido do canal, payload rejeitado).
+func isPermanentTelegoError(err error) bool {
+	var apiErr *telegoapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode == http.StatusBadRequest || apiErr.ErrorCode == http.StatusForbidden
+}
+
+func isMessageNotModifiedTelego(err error) bool {
+	var apiErr *telegoapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode == http.StatusBadRequest && strings.Contains(apiErr.Description, "message is not modified")
+}
@@ -31,7 +31,11 @@ func recordChannelPostEvent(c *container.AppContainer, pCtx *ProcessingContextTe
 		messageID = post.MessageID
 	}
 
-	c.ChannelEventService.Record(context.Background(), services.ChannelEventRecordInput{
+	ctx := pCtx.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c.ChannelEventService.Record(ctx, services.ChannelEventRecordInput{
 		ChannelID:         channelID,
 		ChannelTitle:      channelTitle,
 		OwnerID:           ownerID,
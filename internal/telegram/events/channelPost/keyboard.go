@@ -4,7 +4,10 @@ import (
 	"strings"
 
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
 	"github.com/leirbagxis/FreddyBot/internal/utils"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
 	"github.com/mymmrac/telego"
 )
 
@@ -35,11 +38,20 @@ func CreateInlineKeyboardTelego(buttons []dbmodels.Button, customCaption *dbmode
 
 	rows := map[int][]telego.InlineKeyboardButton{}
 
+	var links *linkrewrite.Rules
+	if channel != nil {
+		links = linkrewrite.FromSettings(channel.LinkSettings)
+	}
+
 	for _, b := range finalButtons {
 		buttonURL := utils.NormalizeTelegramURL(b.ButtonURL)
 		if b.NameButton == "" || buttonURL == "" || !utils.IsValidButtonURL(buttonURL) {
 			continue
 		}
+		buttonURL, allowed := links.URL(buttonURL)
+		if !allowed {
+			continue
+		}
 		row := b.PositionY
 		if row < 0 {
 			row = 0
@@ -48,8 +60,9 @@ func CreateInlineKeyboardTelego(buttons []dbmodels.Button, customCaption *dbmode
 		rows[row] = append(rows[row], btn)
 	}
 
+	// No modo nativo as reações ficam no próprio post, sem botões de voto.
 	perms := pm.CheckPermissions(channel, messageType)
-	if channel != nil && channel.Reactions != "" && perms.CanAddReactions {
+	if channel != nil && channel.Reactions != "" && perms.CanAddReactions && reactions.ModeOf(channel.ReactionMode) == reactions.ModeButtons {
 		reactions := strings.Split(channel.Reactions, ",")
 		var reactionRow []telego.InlineKeyboardButton
 		for _, r := range reactions {
@@ -61,6 +74,12 @@ func CreateInlineKeyboardTelego(buttons []dbmodels.Button, customCaption *dbmode
 				})
 			}
 		}
+		if len(reactionRow) > 0 && channel.VoteSettings != nil && channel.VoteSettings.ShowResults {
+			reactionRow = append(reactionRow, telego.InlineKeyboardButton{
+				Text:         "📊",
+				CallbackData: votepoll.ResultsCallback,
+			})
+		}
 		if len(reactionRow) > 0 {
 			row := channel.ReactionPosition
 			if row < 0 {
@@ -5,19 +5,16 @@ import (
 	"time"
 )
 
-type MediaGroupTelego struct {
-	Messages           []MediaMessageTelego
-	Processed          bool
-	Timer              *time.Timer
-	MessageEditAllowed bool
-	ChatID             int64
-	mu                 sync.Mutex
+// MediaGroupManagerTelego guarda apenas os timers locais dos álbuns. As partes,
+// o prazo de fechamento e o marcador de processado ficam no Redis (ver
+// cache.AppendMediaGroupItem), para que réplicas diferentes possam receber
+// partes do mesmo álbum.
+type MediaGroupManagerTelego struct {
+	timers sync.Map // string -> *mediaGroupTimer
 }
 
-type MediaGroupManagerTelego struct {
-	groups          sync.Map // string -> *MediaGroupTelego
-	processedGroups sync.Map // string -> ProcessedGroup
-	newPackChannels sync.Map // int64 -> bool
+type mediaGroupTimer struct {
+	timer *time.Timer
 }
 
 var globalMediaGroupManagerTelego *MediaGroupManagerTelego
@@ -26,60 +23,18 @@ var onceTelego sync.Once
 func GetMediaGroupManagerTelego() *MediaGroupManagerTelego {
 	onceTelego.Do(func() {
 		globalMediaGroupManagerTelego = &MediaGroupManagerTelego{}
-		go globalMediaGroupManagerTelego.cleanupRoutine()
 	})
 	return globalMediaGroupManagerTelego
 }
 
-func (mgm *MediaGroupManagerTelego) cleanupRoutine() {
-	ticker := time.NewTicker(CleanupTimeout)
-	defer ticker.Stop()
-	for range ticker.C {
-		now := time.Now()
-		mgm.processedGroups.Range(func(key, value interface{}) bool {
-			if group, ok := value.(ProcessedGroup); ok {
-				if now.Sub(group.Timestamp) > CleanupTimeout {
-					mgm.processedGroups.Delete(key)
-				}
-			}
-			return true
-		})
-	}
-}
-
-func (mgm *MediaGroupManagerTelego) GetMediaGroup(groupID string) (*MediaGroupTelego, bool) {
-	if value, ok := mgm.groups.Load(groupID); ok {
-		return value.(*MediaGroupTelego), true
-	}
-	return nil, false
-}
-
-func (mgm *MediaGroupManagerTelego) SetMediaGroup(groupID string, group *MediaGroupTelego) {
-	mgm.groups.Store(groupID, group)
-}
-
-func (mgm *MediaGroupManagerTelego) DeleteMediaGroup(groupID string) {
-	mgm.groups.Delete(groupID)
-}
-
-func (mgm *MediaGroupManagerTelego) IsProcessed(groupID string) bool {
-	_, exists := mgm.processedGroups.Load(groupID)
-	return exists
-}
-
-func (mgm *MediaGroupManagerTelego) MarkProcessed(groupID string) {
-	mgm.processedGroups.Store(groupID, ProcessedGroup{Timestamp: time.Now()})
-}
-
-func (mgm *MediaGroupManagerTelego) IsNewPackActive(channelID int64) bool {
-	value, exists := mgm.newPackChannels.Load(channelID)
-	return exists && value.(bool)
-}
-
-func (mgm *MediaGroupManagerTelego) SetNewPackActive(channelID int64, active bool) {
-	if active {
-		mgm.newPackChannels.Store(channelID, true)
-	} else {
-		mgm.newPackChannels.Delete(channelID)
+// Schedule (re)agenda a tentativa de fechamento do álbum nesta instância.
+func (mgm *MediaGroupManagerTelego) Schedule(groupID string, delay time.Duration, fn func()) {
+	entry := &mediaGroupTimer{}
+	entry.timer = time.AfterFunc(delay, func() {
+		mgm.timers.CompareAndDelete(groupID, entry)
+		fn()
+	})
+	if previous, loaded := mgm.timers.Swap(groupID, entry); loaded {
+		previous.(*mediaGroupTimer).timer.Stop()
 	}
 }
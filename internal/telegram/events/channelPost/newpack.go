@@ -6,32 +6,28 @@ import (
 	"html"
 	"regexp"
 	"strings"
-	"sync"
 
+	"github.com/leirbagxis/FreddyBot/internal/cache"
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 	"github.com/mymmrac/telego"
 )
 
-// Estado por canal: quando recebeu !newpack e está aguardando sticker
-type newPackState struct {
-	WaitingForSticker bool
-	MessageID         int
-}
-
-var newPackStates sync.Map // map[int64]newPackState
 var cmdNewPackRegex = regexp.MustCompile(`^[!/]newpack(?:\s|$)`)
 
-func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels.Channel, post telego.Message) (handled bool, err error) {
+// TryHandleNewPackTelego trata o fluxo !newpack. O estado de espera pelo
+// sticker fica no Redis, então o comando e o sticker podem chegar em réplicas diferentes.
+func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, store *cache.Service, channel dbmodels.Channel, post telego.Message) (handled bool, err error) {
 	channelID := post.Chat.ID
-	mgm := GetMediaGroupManagerTelego()
 
 	if post.Text != "" && cmdNewPackRegex.MatchString(strings.TrimSpace(post.Text)) {
-		mgm.SetNewPackActive(channelID, true)
-		newPackStates.Store(channelID, newPackState{
+		if err := store.SetNewPackState(ctx, channelID, cache.NewPackState{
 			WaitingForSticker: true,
 			MessageID:         post.MessageID,
-		})
+		}); err != nil {
+			return true, err
+		}
 
 		_, err := b.EditMessageText(context.Background(), &telego.EditMessageTextParams{
 			ChatID:    telego.ChatID{ID: channelID},
@@ -40,20 +36,19 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 		})
 		if err != nil {
 			logger.Error("BOT", "falha ao solicitar sticker newpack: %v", err)
-			newPackStates.Delete(channelID)
-			mgm.SetNewPackActive(channelID, false)
+			_ = store.DeleteNewPackState(ctx, channelID)
 			return true, err
 		}
 		return true, nil
 	}
 
 	if post.Sticker != nil {
-		v, ok := newPackStates.Load(channelID)
-		if !ok {
-			return false, nil
+		// Take remove o estado: apenas uma réplica processa o sticker.
+		state, err := store.TakeNewPackState(ctx, channelID)
+		if err != nil {
+			return false, err
 		}
-		state := v.(newPackState)
-		if !state.WaitingForSticker {
+		if state == nil || !state.WaitingForSticker {
 			return false, nil
 		}
 
@@ -63,8 +58,6 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 				ChatID: telego.ChatID{ID: channelID},
 				Text:   "Sticker não faz parte de um pack público.",
 			})
-			newPackStates.Delete(channelID)
-			mgm.SetNewPackActive(channelID, false)
 			return true, nil
 		}
 
@@ -72,8 +65,6 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 			Name: setName,
 		})
 		if err != nil {
-			newPackStates.Delete(channelID)
-			mgm.SetNewPackActive(channelID, false)
 			return true, err
 		}
 
@@ -92,7 +83,7 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 
 		stickerCount := len(pack.Stickers)
 		caption := renderNewPackTemplate(tpl, title, link, stickerCount)
-		captionHTML := renderNewPackHTML(caption)
+		captionHTML, _ := tghtml.Sanitize(renderNewPackHTML(caption))
 		messageButtons := newPackButtonEnabled(channel.NewPackMessageButtons)
 		stickerButtons := newPackButtonEnabled(channel.NewPackStickerButtons)
 		messagePosition := newPackMessagePosition(channel.NewPackMessagePosition)
@@ -134,8 +125,6 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 
 			if _, err = b.SendMessage(context.Background(), &sendParams); err != nil {
 				logger.Error("BOT", "falha ao enviar mensagem newpack abaixo: %v | html=%q", err, captionHTML)
-				newPackStates.Delete(channelID)
-				mgm.SetNewPackActive(channelID, false)
 				return true, err
 			}
 
@@ -158,8 +147,6 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 
 			if _, err = b.EditMessageText(context.Background(), &editParams); err != nil {
 				logger.Error("BOT", "falha ao editar mensagem newpack: %v | html=%q", err, captionHTML)
-				newPackStates.Delete(channelID)
-				mgm.SetNewPackActive(channelID, false)
 				return true, err
 			}
 		}
@@ -183,8 +170,6 @@ func TryHandleNewPackTelego(ctx context.Context, b *telego.Bot, channel dbmodels
 			})
 		}
 
-		newPackStates.Delete(channelID)
-		mgm.SetNewPackActive(channelID, false)
 		return true, nil
 	}
 
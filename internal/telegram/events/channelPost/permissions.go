@@ -2,9 +2,11 @@ package channelpost
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/leirbagxis/FreddyBot/internal/cache"
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
@@ -19,6 +21,8 @@ var oncePM sync.Once
 func GetPermissionManager() *PermissionManager {
 	oncePM.Do(func() {
 		globalPermissionManager = &PermissionManager{}
+		// Alterações feitas em qualquer instância (bot ou API) descartam as permissões em memória.
+		cache.OnChannelInvalidated(globalPermissionManager.InvalidateCache)
 	})
 	return globalPermissionManager
 }
@@ -106,8 +110,15 @@ func (pm *PermissionManager) computePermissions(channel *dbmodels.Channel, messa
 	}
 }
 
+// InvalidateCache descarta as permissões calculadas para o canal (todos os
+// canais quando channelID é 0).
 func (pm *PermissionManager) InvalidateCache(channelID int64) {
-	// Como a chave contém o TokenVersion, basta incrementar o TokenVersion no banco
-	// Mas podemos limpar prefixos se necessário.
-	logger.Bot("Invalidating permission cache for %d", channelID)
+	prefix := fmt.Sprintf("%d:", channelID)
+	pm.cache.Range(func(key, _ any) bool {
+		if channelID == 0 || strings.HasPrefix(key.(string), prefix) {
+			pm.cache.Delete(key)
+		}
+		return true
+	})
+	logger.Debug("BOT", "Invalidating permission cache for %d", channelID)
 }
@@ -3,9 +3,14 @@ package channelpost
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/mymmrac/telego"
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
@@ -23,16 +28,31 @@ type ProcessingContextTelego struct {
 
 	// Transformation State
 	OriginalCaption string
+	BaseText        string                   // original text as HTML, without the channel caption
+	ComposeText     func(base string) string // rebuilds the final text from a new base (set by Transform)
 	FormattedText   string
 	DisableLinkPreview bool
 	FinalButtons    []dbmodels.Button
 	FinalKeyboard   *telego.InlineKeyboardMarkup
+	CustomCaption   *dbmodels.CustomCaption // chosen by the Transform stage (rule or hashtag)
+	OverflowText    string // caption overflow sent as a reply to the post (overflow policy "reply")
+	Filtered        bool   // album part barred by the content filter (see StageFilterTelego)
+	SeparatorDue    bool   // post edited by the dispatcher; the sticker separator follows (see scheduleSeparatorTelego)
 
 	// Media Group State (for albums)
 	IsMediaGroup  bool
 	MediaGroupID  string
 	GroupMessages []MediaMessageTelego
 
+	// Edit State (edited_channel_post)
+	IsEdit         bool
+	AppliedHashtag string // custom caption already applied before the edit
+	AppliedVariantID string // caption pool variant already applied before the edit
+
+	// Durable Job State (persistent queue)
+	CompletedSteps map[string]bool   // non-repeatable steps done by an earlier run of the job
+	OnStepDone     func(step string) // records a completed step in the persisted job
+
 	// Execution Control
 	Pipeline     *PipelineTelego
 	StopPipeline bool // If true, remaining stages are skipped
@@ -45,6 +65,7 @@ type MediaMessageTelego struct {
 	HasCaption      bool
 	Caption         string
 	CaptionEntities []telego.MessageEntity
+	Filtered        bool // barred by the content filter; the whole album is skipped
 }
 
 // StageTelego defines a single step in the processing pipeline using telego.
@@ -52,16 +73,37 @@ type StageTelego func(ctx *ProcessingContextTelego) error
 
 // PipelineTelego orchestrates the execution of multiple stages for telego.
 type PipelineTelego struct {
-	Name   string
-	stages []StageTelego
+	Name       string
+	stages     []StageTelego
+	stageNames []string
 }
 
 // NewPipelineTelego initializes a pipeline with the provided stages.
 func NewPipelineTelego(name string, stages ...StageTelego) *PipelineTelego {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stageName(stage)
+	}
 	return &PipelineTelego{
-		Name:   name,
-		stages: stages,
+		Name:       name,
+		stages:     stages,
+		stageNames: names,
+	}
+}
+
+// stageName resolves the constructor name of a stage closure
+// (e.g. "channelpost.StageSendTelego.func1" -> "StageSendTelego") for metrics.
+func stageName(stage StageTelego) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(stage).Pointer())
+	if fn == nil {
+		return "unknown"
 	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	if parts := strings.Split(name, "."); len(parts) > 1 {
+		return parts[1]
+	}
+	return name
 }
 
 // Execute runs the pipeline for a given context starting from the first stage.
@@ -71,22 +113,30 @@ func (p *PipelineTelego) Execute(ctx *ProcessingContextTelego) error {
 
 // ExecuteFrom runs the pipeline starting from a specific stage index.
 func (p *PipelineTelego) ExecuteFrom(ctx *ProcessingContextTelego, startIndex int) error {
+	if ctx.Ctx == nil {
+		ctx.Ctx = context.Background()
+	}
+	ctx.Ctx = logger.With(ctx.Ctx, logger.FieldPipeline, p.Name)
+
 	defer func() {
 		if r := recover(); r != nil {
-			logger.Error("PIPELINE", "[%s] Recovered from panic: %v", p.Name, r)
+			logger.ErrorCtx(ctx.Ctx, "PIPELINE", "[%s] Recovered from panic: %v", p.Name, r)
 			ctx.Error = fmt.Errorf("pipeline panic: %v", r)
 		}
 	}()
 
 	for i := startIndex; i < len(p.stages); i++ {
 		if ctx.StopPipeline {
-			logger.Bot("⏹️ Pipeline [%s] stopped at stage %d", p.Name, i+1)
+			logger.BotCtx(ctx.Ctx, "⏹️ Pipeline [%s] stopped at stage %d", p.Name, i+1)
 			break
 		}
 
-		if err := p.stages[i](ctx); err != nil {
+		start := time.Now()
+		err := p.stages[i](ctx)
+		p.observeStage(ctx, i, time.Since(start), err)
+		if err != nil {
 			ctx.Error = err
-			logger.Error("PIPELINE", "[%s] Error at stage %d: %v", p.Name, i+1, err)
+			logger.ErrorCtx(ctx.Ctx, "PIPELINE", "[%s] Error at stage %d: %v", p.Name, i+1, err)
 			return err
 		}
 	}
@@ -94,6 +144,31 @@ func (p *PipelineTelego) ExecuteFrom(ctx *ProcessingContextTelego, startIndex in
 	return nil
 }
 
+func (p *PipelineTelego) observeStage(ctx *ProcessingContextTelego, index int, elapsed time.Duration, err error) {
+	result := "ok"
+	switch {
+	case err != nil:
+		result = "error"
+	case ctx.StopPipeline:
+		result = "stopped"
+	}
+	metrics.PipelineStageDuration.
+		WithLabelValues(p.Name, p.stageNames[index], channelTypeTelego(ctx), metrics.LabelOrUnknown(string(ctx.MessageType)), result).
+		Observe(elapsed.Seconds())
+}
+
+// channelTypeTelego distingue canais públicos (com @username) de privados.
+func channelTypeTelego(ctx *ProcessingContextTelego) string {
+	post := ctx.Update.ChannelPost
+	if post == nil {
+		return "unknown"
+	}
+	if post.Chat.Username != "" {
+		return "public"
+	}
+	return "private"
+}
+
 // NewProcessingContextTelego creates a fresh context for a telego update.
 func NewProcessingContextTelego(ctx context.Context, b *telego.Bot, update telego.Update, p *PipelineTelego) *ProcessingContextTelego {
 	return &ProcessingContextTelego{
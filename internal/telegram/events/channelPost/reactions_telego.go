@@ -0,0 +1,43 @@
+package channelpost
+
+import (
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
+	"github.com/mymmrac/telego"
+)
+
+// usesNativeReactionsTelego indica se o post recebe reação nativa do bot em vez
+// da linha de botões "vote:<emoji>".
+func usesNativeReactionsTelego(pCtx *ProcessingContextTelego) bool {
+	ch := pCtx.Channel
+	return ch != nil && ch.Reactions != "" && pCtx.Permissions.CanAddReactions &&
+		reactions.ModeOf(ch.ReactionMode) == reactions.ModeNative
+}
+
+// setNativeReactionTelego reage ao post com o primeiro emoji do canal. Bots só
+// podem deixar uma reação por mensagem; os votos vêm das reações dos inscritos,
+// contadas pelos updates message_reaction_count. Em álbuns a reação vai na
+// primeira mídia.
+func setNativeReactionTelego(pCtx *ProcessingContextTelego) error {
+	emoji := reactions.BotEmoji(pCtx.Channel.Reactions)
+	if emoji == "" {
+		return nil
+	}
+
+	messageID := 0
+	if pCtx.IsMediaGroup {
+		if len(pCtx.GroupMessages) > 0 {
+			messageID = pCtx.GroupMessages[0].MessageID
+		}
+	} else if post := pCtx.Update.ChannelPost; post != nil {
+		messageID = post.MessageID
+	}
+	if messageID == 0 {
+		return nil
+	}
+
+	return pCtx.Bot.SetMessageReaction(pCtx.Ctx, &telego.SetMessageReactionParams{
+		ChatID:    telego.ChatID{ID: pCtx.Channel.ID},
+		MessageID: messageID,
+		Reaction:  []telego.ReactionType{&telego.ReactionTypeEmoji{Type: telego.ReactionEmoji, Emoji: emoji}},
+	})
+}
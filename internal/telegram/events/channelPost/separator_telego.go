@@ -0,0 +1,111 @@
+package channelpost
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/separatorpool"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/ratelimit"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+)
+
+// scheduleSeparatorTelego envia o sticker separador depois do post. O sticker
+// é escolhido pelo separatorpool (legenda customizada, tipo da mensagem ou
+// rodízio). Sem intervalo configurado o separador sai um segundo depois de
+// cada post; com intervalo, ele só sai quando o canal fica esse tempo sem
+// posts, fechando a sequência em vez de cair entre o primeiro e o segundo post.
+func scheduleSeparatorTelego(c *container.AppContainer, pCtx *ProcessingContextTelego) {
+	channel := pCtx.Channel
+	// Edições reaproveitam o post original, o separador já foi enviado.
+	if pCtx.IsEdit || channel.Separator == nil {
+		return
+	}
+
+	post := separatorpool.Post{MessageType: string(pCtx.MessageType)}
+	if pCtx.CustomCaption != nil {
+		post.Hashtag = pCtx.CustomCaption.Code
+	}
+	candidates := separatorpool.Candidates(channel.Separator, channel.SeparatorVariants, post)
+	if len(candidates) == 0 {
+		return
+	}
+
+	rotation := channel.Separator.Rotation
+	quiet := time.Duration(channel.Separator.CooldownMinutes) * time.Minute
+
+	delay := 1 * time.Second
+	var seq int64
+	if quiet > 0 {
+		// Cada post adia o separador dos anteriores: só o timer do último post
+		// do canal o envia.
+		next, err := c.CacheService.MarkSeparatorPost(pCtx.Ctx, channel.ID, quiet)
+		if err != nil {
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao registrar post do canal %d para o separador: %v", channel.ID, err)
+		} else {
+			delay, seq = quiet, next
+		}
+	}
+
+	time.AfterFunc(delay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if seq > 0 {
+			last, err := c.CacheService.IsLastSeparatorPost(ctx, channel.ID, seq)
+			if err != nil {
+				logger.Error("PIPELINE", "❌ Erro ao conferir o silêncio do canal %d: %v", channel.ID, err)
+				return
+			}
+			if !last {
+				logger.Debug("PIPELINE", "⏭️ Separador do canal %d adiado por um post mais recente", channel.ID)
+				return
+			}
+		}
+
+		var rotationSeq int64
+		if rotation == separatorpool.ModeRoundRobin && len(candidates) > 1 {
+			next, err := c.CacheService.NextSeparatorRotation(ctx, channel.ID)
+			if err != nil {
+				logger.Error("PIPELINE", "❌ Erro ao avançar rodízio de separadores do canal %d: %v", channel.ID, err)
+				next = rand.Int64()
+			}
+			rotationSeq = next
+		}
+
+		sticker := separatorpool.Pick(candidates, rotation, rotationSeq, rand.IntN)
+		if err := ProcessSeparatorTelego(ctx, pCtx.Bot, channel.ID, sticker); err != nil {
+			logger.Error("PIPELINE", "❌ Erro ao enviar separador no canal %d: %v", channel.ID, err)
+		}
+	})
+}
+
+func ProcessSeparatorTelego(ctx context.Context, b *telego.Bot, chatID int64, stickerID string) error {
+	if stickerID == "" {
+		return nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	maxRetries := 2
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		_, err := b.SendSticker(sendCtx, &telego.SendStickerParams{
+			ChatID:  telego.ChatID{ID: chatID},
+			Sticker: telego.InputFile{FileID: stickerID},
+		})
+		if err == nil {
+			return nil
+		}
+
+		// O limiter segura a próxima tentativa pelo retry_after informado.
+		if _, ok := ratelimit.RetryAfter(err); ok {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("failed after %d attempts", maxRetries)
+}
@@ -9,17 +9,16 @@ import (
 func StageDecorateTelego(c *container.AppContainer) StageTelego {
 	return func(pCtx *ProcessingContextTelego) error {
 		// 1. Determine which buttons/reactions to use
-		hashtag := extractHashtag(pCtx.OriginalCaption)
-		custom := findCustomCaption(pCtx.Channel, hashtag)
+		custom := pCtx.CustomCaption
 
 		// 2. Build Keyboard
 		pCtx.FinalKeyboard = CreateInlineKeyboardTelego(pCtx.FinalButtons, custom, pCtx.Channel, pCtx.MessageType)
 
 		if pCtx.FinalKeyboard != nil {
-			logger.Bot("🎹 Teclado construído com %d linhas", len(pCtx.FinalKeyboard.InlineKeyboard))
+			logger.BotCtx(pCtx.Ctx, "🎹 Teclado construído com %d linhas", len(pCtx.FinalKeyboard.InlineKeyboard))
 			recordChannelPostEvent(c, pCtx, "buttons_applied", services.ChannelEventStatusInfo, map[string]any{"rows": len(pCtx.FinalKeyboard.InlineKeyboard), "buttons": len(pCtx.FinalButtons)}, nil)
 		} else {
-			logger.Bot("⏭️ Nenhum teclado necessário")
+			logger.BotCtx(pCtx.Ctx, "⏭️ Nenhum teclado necessário")
 		}
 
 		return nil
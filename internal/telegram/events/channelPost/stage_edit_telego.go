@@ -0,0 +1,263 @@
+package channelpost
+
+import (
+	"html"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+// handleEditedChannelPostTelego reaplica legenda e teclado quando um post já
+// processado é editado. O post editado é tratado como ChannelPost para que os
+// stages de Transform/Decorate/Send sejam reaproveitados sem alteração.
+func handleEditedChannelPostTelego(ctx *telegohandler.Context, c *container.AppContainer, update telego.Update) {
+	edited := *update.EditedChannelPost
+	normalized := update
+	normalized.ChannelPost = &edited
+
+	executionPipeline := newEditExecutionPipelineTelego(c)
+
+	discoveryPipeline := NewPipelineTelego(
+		"EditDiscovery",
+		StagePreflightTelego(c),
+		StageFilterTelego(c),
+		StageEditPrepareTelego(c),
+		StageQueueTelego(c, executionPipeline),
+	)
+
+	pCtx := NewProcessingContextTelego(updateContextTelego(ctx), ctx.Bot(), normalized, discoveryPipeline)
+	pCtx.IsEdit = true
+	_ = discoveryPipeline.Execute(pCtx)
+}
+
+func newEditExecutionPipelineTelego(c *container.AppContainer) *PipelineTelego {
+	return NewPipelineTelego(
+		"Edit",
+		StageTransformTelego(c),
+		StageTranslateTelego(c),
+		StageDecorateTelego(c),
+		StageSanitizeTelego(c),
+		StageEditGuardTelego(c),
+		StageSendTelego(c),
+	)
+}
+
+// StageEditPrepareTelego verifica se o canal aceita reprocessar edições e remove
+// do texto editado a legenda que o bot já tinha aplicado, evitando duplicação.
+func StageEditPrepareTelego(c *container.AppContainer) StageTelego {
+	return func(pCtx *ProcessingContextTelego) error {
+		post := pCtx.Update.ChannelPost
+		if post == nil || pCtx.Channel == nil {
+			pCtx.StopPipeline = true
+			return nil
+		}
+
+		if !pCtx.Channel.ProcessEdits {
+			logger.BotCtx(pCtx.Ctx, "⏭️ Edição de post de canal ignorada: %d (desativado no canal %d)", post.MessageID, pCtx.Channel.ID)
+			pCtx.StopPipeline = true
+			return nil
+		}
+
+		if pCtx.MessageType == MessageTypeSticker {
+			pCtx.StopPipeline = true
+			return nil
+		}
+
+		// Em álbuns só a mídia com legenda é reprocessada; áudios e documentos
+		// agrupados são reenviados no fluxo normal e não podem ser editados aqui.
+		if post.MediaGroupID != "" && (post.Caption == "" || pCtx.MessageType == MessageTypeAudio || pCtx.MessageType == MessageTypeDocument) {
+			recordChannelPostEvent(c, pCtx, "post_edit_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "media_group"}, nil)
+			pCtx.StopPipeline = true
+			return nil
+		}
+
+		cleanPost := *post
+		text, entities := &cleanPost.Caption, &cleanPost.CaptionEntities
+		if pCtx.MessageType == MessageTypeText {
+			text, entities = &cleanPost.Text, &cleanPost.Entities
+		}
+		var applied appliedCaptionTelego
+		var signed, stripped bool
+		tplData := captionTemplateDataTelego(pCtx, "")
+		// O rodapé de assinatura fecha o texto, então sai antes da legenda.
+		if pCtx.Channel.SignatureFooter && tplData.Author != "" {
+			if base, ents, ok := cutAppliedCaptionTelego(*text, *entities, signatureFormatTelego(pCtx.Channel), tplData, captiontpl.PositionAppend, ""); ok {
+				*text, *entities, signed = base, ents, true
+			}
+		}
+		*text, *entities, applied, stripped = stripAppliedCaptionTelego(pCtx.Channel, tplData, *text, *entities)
+		pCtx.Update.ChannelPost = &cleanPost
+		metadata := map[string]any{"caption_stripped": stripped, "signature_stripped": signed}
+		if applied.Custom != nil {
+			pCtx.AppliedHashtag = applied.Custom.Code
+			metadata["custom_caption"] = applied.Custom.Code
+		}
+		if applied.Variant != nil {
+			pCtx.AppliedVariantID = applied.Variant.VariantID
+			metadata["variant_id"] = applied.Variant.VariantID
+		}
+		recordChannelPostEvent(c, pCtx, "post_edit_received", services.ChannelEventStatusInfo, metadata, nil)
+		logger.BotCtx(pCtx.Ctx, "✏️ [%d] Edição recebida no canal %d (legenda removida: %v)", post.MessageID, pCtx.Channel.ID, stripped)
+		return nil
+	}
+}
+
+// StageEditGuardTelego interrompe o reprocessamento quando o post editado já está
+// no estado final. Isso cobre edições que mantiveram legenda e teclado e também
+// o update gerado pela própria edição do bot.
+func StageEditGuardTelego(c *container.AppContainer) StageTelego {
+	return func(pCtx *ProcessingContextTelego) error {
+		current := pCtx.Update.EditedChannelPost
+		if current == nil {
+			return nil
+		}
+
+		currentText := current.Text
+		if pCtx.MessageType != MessageTypeText {
+			currentText = current.Caption
+		}
+
+		sameText := !pCtx.Permissions.CanEdit || htmlToPlainText(pCtx.FormattedText) == strings.TrimSpace(currentText)
+		hasKeyboard := pCtx.FinalKeyboard == nil || (current.ReplyMarkup != nil && len(current.ReplyMarkup.InlineKeyboard) > 0)
+		if sameText && hasKeyboard {
+			logger.BotCtx(pCtx.Ctx, "⏭️ Edição %d já está com legenda e teclado aplicados", current.MessageID)
+			recordChannelPostEvent(c, pCtx, "post_edit_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "already_applied"}, nil)
+			pCtx.StopPipeline = true
+			return nil
+		}
+
+		// Se o teclado sobreviveu à edição, ele é mantido para não zerar a contagem de votos.
+		if pCtx.FinalKeyboard != nil && current.ReplyMarkup != nil && len(current.ReplyMarkup.InlineKeyboard) > 0 {
+			pCtx.FinalKeyboard = current.ReplyMarkup
+		}
+		return nil
+	}
+}
+
+// appliedCaptionTelego identifica a legenda encontrada no post editado.
+type appliedCaptionTelego struct {
+	Custom  *dbmodels.CustomCaption
+	Variant *dbmodels.CaptionVariant
+}
+
+// stripAppliedCaptionTelego remove do texto a legenda do canal (padrão,
+// customizada ou do pool) quando ela já está presente, respeitando a posição
+// configurada, e ajusta as entidades ao trecho que sobrou. Templates são
+// renderizados com os dados do próprio post antes da comparação.
+func stripAppliedCaptionTelego(channel *dbmodels.Channel, data captiontpl.Data, text string, entities []telego.MessageEntity) (string, []telego.MessageEntity, appliedCaptionTelego, bool) {
+	for i := range channel.CustomCaptions {
+		custom := &channel.CustomCaptions[i]
+		position, separator := captionLayoutTelego(channel, custom)
+		if base, ents, ok := cutAppliedCaptionTelego(text, entities, custom.Caption, data, position, separator); ok {
+			return base, ents, appliedCaptionTelego{Custom: custom}, true
+		}
+	}
+
+	position, separator := captionLayoutTelego(channel, nil)
+	for i := range channel.CaptionVariants {
+		variant := &channel.CaptionVariants[i]
+		if base, ents, ok := cutAppliedCaptionTelego(text, entities, variant.Caption, data, position, separator); ok {
+			return base, ents, appliedCaptionTelego{Variant: variant}, true
+		}
+	}
+
+	if channel.DefaultCaption != nil {
+		if base, ents, ok := cutAppliedCaptionTelego(text, entities, channel.DefaultCaption.Caption, data, position, separator); ok {
+			return base, ents, appliedCaptionTelego{}, true
+		}
+	}
+
+	return text, entities, appliedCaptionTelego{}, false
+}
+
+func cutAppliedCaptionTelego(text string, entities []telego.MessageEntity, caption string, data captiontpl.Data, position, separator string) (string, []telego.MessageEntity, bool) {
+	rendered, placesOriginal := renderCaptionTelego(caption, data)
+	if placesOriginal {
+		// O texto original está no meio do template; não há como separá-lo.
+		return "", nil, false
+	}
+
+	var header, footer string
+	switch position {
+	case captiontpl.PositionPrepend:
+		header = rendered
+	case captiontpl.PositionWrap:
+		header, footer = captiontpl.SplitWrap(rendered)
+	case captiontpl.PositionReplace:
+		// O texto original foi substituído; a edição recomeça do zero.
+		plain := htmlToPlainText(rendered)
+		if plain == "" || strings.TrimSpace(text) != plain {
+			return "", nil, false
+		}
+		return "", nil, true
+	default:
+		footer = rendered
+	}
+
+	headerPlain, footerPlain := htmlToPlainText(header), htmlToPlainText(footer)
+	if headerPlain == "" && footerPlain == "" {
+		return "", nil, false
+	}
+	sep := strings.TrimSpace(separator)
+
+	lo, hi := 0, len(text)
+	if footerPlain != "" {
+		hi = lo + len(strings.TrimRightFunc(text[lo:hi], isTrailingSpace))
+		if !strings.HasSuffix(text[lo:hi], footerPlain) {
+			return "", nil, false
+		}
+		hi -= len(footerPlain)
+		hi = lo + len(strings.TrimRightFunc(text[lo:hi], isTrailingSpace))
+		if sep != "" && strings.HasSuffix(text[lo:hi], sep) {
+			hi -= len(sep)
+			hi = lo + len(strings.TrimRightFunc(text[lo:hi], isTrailingSpace))
+		}
+	}
+	if headerPlain != "" {
+		lo = hi - len(strings.TrimLeftFunc(text[lo:hi], isTrailingSpace))
+		if !strings.HasPrefix(text[lo:hi], headerPlain) {
+			return "", nil, false
+		}
+		lo += len(headerPlain)
+		lo = hi - len(strings.TrimLeftFunc(text[lo:hi], isTrailingSpace))
+		if sep != "" && strings.HasPrefix(text[lo:hi], sep) {
+			lo += len(sep)
+			lo = hi - len(strings.TrimLeftFunc(text[lo:hi], isTrailingSpace))
+		}
+	}
+
+	return text[lo:hi], sliceEntitiesTelego(entities, utils.UTF16Len(text[:lo]), utils.UTF16Len(text[:hi])), true
+}
+
+// sliceEntitiesTelego mantém as entidades dentro de [start, end) em UTF-16,
+// recortando as que ultrapassam os limites e deslocando para o novo início.
+func sliceEntitiesTelego(entities []telego.MessageEntity, start, end int) []telego.MessageEntity {
+	if len(entities) == 0 {
+		return entities
+	}
+	sliced := make([]telego.MessageEntity, 0, len(entities))
+	for _, e := range entities {
+		from, to := max(e.Offset, start), min(e.Offset+e.Length, end)
+		if from >= to {
+			continue
+		}
+		e.Offset, e.Length = from-start, to-from
+		sliced = append(sliced, e)
+	}
+	return sliced
+}
+
+func htmlToPlainText(text string) string {
+	return strings.TrimSpace(html.UnescapeString(utils.RemoveHTMLTags(text)))
+}
+
+func isTrailingSpace(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
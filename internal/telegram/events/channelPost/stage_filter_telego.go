@@ -0,0 +1,169 @@
+package channelpost
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/contentfilter"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/leirbagxis/FreddyBot/pkg/parser"
+	"github.com/mymmrac/telego"
+)
+
+// StageFilterTelego aplica o filtro de conteúdo do canal logo depois do
+// Preflight. Na ação strip os trechos proibidos são removidos pelo Transform;
+// quando isso não basta (tipo de mensagem proibido, expressão que atravessa a
+// formatação ou post que o bot não pode editar) o post é barrado como no block.
+// Partes de álbuns barradas seguem marcadas para o agrupamento, que descarta o
+// álbum inteiro.
+func StageFilterTelego(c *container.AppContainer) StageTelego {
+	return func(pCtx *ProcessingContextTelego) error {
+		rules := contentfilter.FromSettings(pCtx.Channel.ContentFilter)
+		if rules == nil {
+			return nil
+		}
+
+		post := pCtx.Update.ChannelPost
+		text, entities := post.Caption, post.CaptionEntities
+		if pCtx.MessageType == MessageTypeText {
+			text, entities = post.Text, post.Entities
+		}
+		formatted := ProcessTextWithFormattingTelego(text, entities)
+
+		violation := rules.Check(formatted, string(pCtx.MessageType))
+		if violation == nil {
+			return nil
+		}
+
+		metadata := map[string]any{"reasons": violation.Reasons(), "violation": violation}
+		action := rules.Action
+		if action == contentfilter.ActionStrip {
+			stripped, removed := rules.Strip(formatted)
+			metadata["removed"] = removed
+			editable := pCtx.Permissions.CanEdit && (!pCtx.IsEdit || pCtx.Channel.ProcessEdits)
+			if violation.MessageType != "" || !editable || rules.Check(stripped, "") != nil {
+				action = contentfilter.ActionBlock
+			}
+		}
+		metadata["action"] = action
+
+		var err error
+		switch action {
+		case contentfilter.ActionStrip:
+			logger.BotCtx(pCtx.Ctx, "🛡️ [%d] Trechos proibidos serão removidos do post (%s)", post.MessageID, strings.Join(violation.Reasons(), ", "))
+			recordChannelPostEvent(c, pCtx, "content_filtered", services.ChannelEventStatusFiltered, metadata, nil)
+			return nil
+		case contentfilter.ActionDelete:
+			err = pCtx.Bot.DeleteMessage(pCtx.Ctx, &telego.DeleteMessageParams{
+				ChatID:    telego.ChatID{ID: pCtx.Channel.ID},
+				MessageID: post.MessageID,
+			})
+			if err != nil {
+				logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao apagar post %d barrado pelo filtro: %v", post.MessageID, err)
+			} else {
+				notifyFilteredPostTelego(pCtx, violation)
+			}
+		}
+
+		logger.BotCtx(pCtx.Ctx, "🛡️ [%d] Post barrado pelo filtro de conteúdo (%s, ação %s)", post.MessageID, strings.Join(violation.Reasons(), ", "), action)
+		recordChannelPostEvent(c, pCtx, "content_filtered", services.ChannelEventStatusFiltered, metadata, err)
+
+		if post.MediaGroupID != "" && !pCtx.IsEdit {
+			pCtx.Filtered = true
+			return nil
+		}
+		pCtx.StopPipeline = true
+		return nil
+	}
+}
+
+// stripFilteredTextTelego remove do texto do post os trechos proibidos quando
+// o canal usa a ação strip. Textos sem violação não são alterados.
+func stripFilteredTextTelego(pCtx *ProcessingContextTelego, text string) string {
+	rules := contentfilter.FromSettings(pCtx.Channel.ContentFilter)
+	if rules == nil || rules.Action != contentfilter.ActionStrip || rules.Check(text, "") == nil {
+		return text
+	}
+	text, _ = rules.Strip(text)
+	return text
+}
+
+// skipFilteredMediaGroupTelego descarta o álbum quando alguma parte foi barrada
+// pelo filtro. Na ação delete as demais partes também são apagadas.
+func skipFilteredMediaGroupTelego(pCtx *ProcessingContextTelego, mediaGroupID string, msgs []MediaMessageTelego) bool {
+	if !slices.ContainsFunc(msgs, func(m MediaMessageTelego) bool { return m.Filtered }) {
+		return false
+	}
+
+	if rules := contentfilter.FromSettings(pCtx.Channel.ContentFilter); rules != nil && rules.Action == contentfilter.ActionDelete {
+		for _, m := range msgs {
+			if m.Filtered {
+				continue
+			}
+			if err := pCtx.Bot.DeleteMessage(pCtx.Ctx, &telego.DeleteMessageParams{
+				ChatID:    telego.ChatID{ID: pCtx.Channel.ID},
+				MessageID: m.MessageID,
+			}); err != nil {
+				logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao apagar parte %d do álbum %s: %v", m.MessageID, mediaGroupID, err)
+			}
+		}
+	}
+
+	logger.BotCtx(pCtx.Ctx, "🛡️ Media group %s descartado pelo filtro de conteúdo", mediaGroupID)
+	return true
+}
+
+var filterReasonLabels = map[string]string{
+	"words":    "palavras proibidas",
+	"patterns": "expressão proibida",
+	"domains":  "domínio bloqueado",
+	"type":     "tipo de mensagem proibido",
+}
+
+// notifyFilteredPostTelego avisa o dono do canal que um post foi apagado.
+func notifyFilteredPostTelego(pCtx *ProcessingContextTelego, violation *contentfilter.Violation) {
+	if pCtx.Channel.OwnerID == 0 {
+		return
+	}
+
+	reasons := make([]string, 0, 4)
+	for _, reason := range violation.Reasons() {
+		label := filterReasonLabels[reason]
+		switch reason {
+		case "words":
+			label += fmt.Sprintf(" (<code>%s</code>)", html.EscapeString(strings.Join(violation.Words, ", ")))
+		case "domains":
+			label += fmt.Sprintf(" (<code>%s</code>)", html.EscapeString(strings.Join(violation.Domains, ", ")))
+		case "type":
+			label += " (" + captiontpl.MessageTypeLabel(violation.MessageType) + ")"
+		}
+		reasons = append(reasons, label)
+	}
+
+	channelName := pCtx.Channel.Title
+	if channelName == "" {
+		channelName = fmt.Sprintf("Canal %d", pCtx.Channel.ID)
+	}
+
+	text, kb := parser.GetMessageTelego("content-filter-deleted", map[string]string{
+		"channelName": html.EscapeString(channelName),
+		"channelId":   fmt.Sprintf("%d", pCtx.Channel.ID),
+		"reasons":     strings.Join(reasons, "; "),
+	})
+	params := &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: pCtx.Channel.OwnerID},
+		Text:      text,
+		ParseMode: telego.ModeHTML,
+	}
+	if kb != nil {
+		params.ReplyMarkup = kb
+	}
+	if _, err := pCtx.Bot.SendMessage(pCtx.Ctx, params); err != nil {
+		logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao avisar o dono do canal %d sobre post filtrado: %v", pCtx.Channel.ID, err)
+	}
+}
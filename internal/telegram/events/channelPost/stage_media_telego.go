@@ -2,7 +2,8 @@ package channelpost
 
 import (
 	"context"
-	"time"
+	"encoding/json"
+	"sort"
 
 	"github.com/mymmrac/telego"
 	"github.com/leirbagxis/FreddyBot/internal/container"
@@ -17,80 +18,88 @@ func StageMediaGroupingTelego(c *container.AppContainer, executionPipeline *Pipe
 		}
 
 		mediaGroupID := post.MediaGroupID
-		mgm := GetMediaGroupManagerTelego()
-
-		group, loaded := mgm.GetMediaGroup(mediaGroupID)
-		if !loaded {
-			group = &MediaGroupTelego{
-				Messages:           make([]MediaMessageTelego, 0, 10),
-				Processed:          false,
-				MessageEditAllowed: true,
-				ChatID:             post.Chat.ID,
-			}
-			mgm.SetMediaGroup(mediaGroupID, group)
-		}
-
-		group.mu.Lock()
-		defer group.mu.Unlock()
-
-		if group.Processed || mgm.IsProcessed(mediaGroupID) {
-			pCtx.StopPipeline = true
-			return nil
-		}
-
-		fileID := getFileIDTelego(post)
-
-		group.Messages = append(group.Messages, MediaMessageTelego{
+		item, err := json.Marshal(MediaMessageTelego{
 			MessageID:       post.MessageID,
-			FileID:          fileID,
+			FileID:          getFileIDTelego(post),
 			HasCaption:      post.Caption != "",
 			Caption:         post.Caption,
 			CaptionEntities: post.CaptionEntities,
+			Filtered:        pCtx.Filtered,
 		})
-
-		if group.Timer != nil {
-			group.Timer.Stop()
+		if err != nil {
+			return err
 		}
 
-		timeout := time.Duration(800+len(group.Messages)*200) * time.Millisecond
-		if timeout > 2*time.Second {
-			timeout = 2 * time.Second
+		// A parte vai para o Redis; qualquer réplica que recebeu uma parte pode fechar o álbum.
+		window, ok, err := c.CacheService.AppendMediaGroupItem(pCtx.Ctx, mediaGroupID, item)
+		if err != nil {
+			return err
+		}
+		pCtx.StopPipeline = true
+		if !ok {
+			return nil
 		}
-		
-		group.Timer = time.AfterFunc(timeout, func() {
-			group.mu.Lock()
-			if group.Processed {
-				group.mu.Unlock()
-				return
-			}
-			group.Processed = true
-			msgs := group.Messages
-			group.mu.Unlock()
-
-			mgm.MarkProcessed(mediaGroupID)
-			mgm.DeleteMediaGroup(mediaGroupID)
 
-			logger.Bot("📸 Media group ready Telego: %s (%d messages)", mediaGroupID, len(msgs))
+		GetMediaGroupManagerTelego().Schedule(mediaGroupID, window, func() {
+			flushMediaGroupTelego(c, pCtx, executionPipeline, mediaGroupID)
+		})
+		return nil
+	}
+}
 
-			groupCtx := &ProcessingContextTelego{
-				Ctx:           context.Background(),
-				Bot:           pCtx.Bot,
-				Update:        pCtx.Update,
-				MessageType:   pCtx.MessageType,
-				Channel:       pCtx.Channel,
-				Permissions:   pCtx.Permissions,
-				IsMediaGroup:  true,
-				MediaGroupID:  mediaGroupID,
-				GroupMessages: msgs,
-				Pipeline:      executionPipeline,
-			}
-			
-			messageQueue.AddTelegoToQueue(groupCtx, executionPipeline)
+// flushMediaGroupTelego tenta fechar o álbum. Se outra réplica recebeu uma parte
+// depois, o fechamento é reagendado; se outra réplica já fechou, nada é feito.
+func flushMediaGroupTelego(c *container.AppContainer, pCtx *ProcessingContextTelego, executionPipeline *PipelineTelego, mediaGroupID string) {
+	items, wait, err := c.CacheService.ClaimMediaGroup(pCtx.Ctx, mediaGroupID)
+	if err != nil {
+		logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Erro ao fechar media group %s: %v", mediaGroupID, err)
+		return
+	}
+	if wait > 0 {
+		GetMediaGroupManagerTelego().Schedule(mediaGroupID, wait, func() {
+			flushMediaGroupTelego(c, pCtx, executionPipeline, mediaGroupID)
 		})
+		return
+	}
+	if items == nil {
+		return
+	}
 
-		pCtx.StopPipeline = true
-		return nil
+	msgs := make([]MediaMessageTelego, 0, len(items))
+	for _, raw := range items {
+		var msg MediaMessageTelego
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Parte inválida no media group %s: %v", mediaGroupID, err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return
+	}
+	// Partes recebidas por réplicas diferentes podem chegar fora de ordem.
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].MessageID < msgs[j].MessageID })
+
+	if skipFilteredMediaGroupTelego(pCtx, mediaGroupID, msgs) {
+		return
 	}
+
+	logger.BotCtx(pCtx.Ctx, "📸 Media group ready Telego: %s (%d messages)", mediaGroupID, len(msgs))
+
+	groupCtx := &ProcessingContextTelego{
+		Ctx:           context.WithoutCancel(pCtx.Ctx),
+		Bot:           pCtx.Bot,
+		Update:        pCtx.Update,
+		MessageType:   pCtx.MessageType,
+		Channel:       pCtx.Channel,
+		Permissions:   pCtx.Permissions,
+		IsMediaGroup:  true,
+		MediaGroupID:  mediaGroupID,
+		GroupMessages: msgs,
+		Pipeline:      executionPipeline,
+	}
+
+	messageQueue.AddTelegoToQueue(groupCtx, executionPipeline)
 }
 
 func getFileIDTelego(post *telego.Message) string {
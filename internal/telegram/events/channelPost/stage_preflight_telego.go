@@ -22,7 +22,7 @@ func StagePreflightTelego(c *container.AppContainer) StageTelego {
 		// 1. Basic Filters
 		botInfo, _ := pCtx.Bot.GetMe(context.Background())
 		if post.ViaBot != nil && post.ViaBot.ID == botInfo.ID {
-			logger.Bot("⏭️ Ignoring inline message.")
+			logger.BotCtx(pCtx.Ctx, "⏭️ Ignoring inline message.")
 			recordChannelPostEvent(c, pCtx, "post_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "via_bot"}, nil)
 			pCtx.StopPipeline = true
 			return nil
@@ -38,17 +38,17 @@ func StagePreflightTelego(c *container.AppContainer) StageTelego {
 		// 2. Load Channel
 		channel, err := c.ChannelService.GetChannelWithRelations(context.Background(), post.Chat.ID)
 		if err != nil {
-			logger.Error("PIPELINE", "❌ Canal %d não encontrado no banco: %v", post.Chat.ID, err)
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Canal %d não encontrado no banco: %v", post.Chat.ID, err)
 			recordChannelPostEvent(c, pCtx, "post_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "channel_not_found"}, err)
 			pCtx.StopPipeline = true
 			return nil
 		}
 		pCtx.Channel = channel
-		logger.Bot("📁 Canal carregado: %s (%d)", channel.Title, channel.ID)
+		logger.BotCtx(pCtx.Ctx, "📁 Canal carregado: %s (%d)", channel.Title, channel.ID)
 
 		// 3. Blacklist Check
 		if channel.Owner != nil && channel.Owner.IsBlacklisted {
-			logger.Bot("🚫 Canal %d ignorado: Proprietário está na Blacklist", channel.ID)
+			logger.BotCtx(pCtx.Ctx, "🚫 Canal %d ignorado: Proprietário está na Blacklist", channel.ID)
 			recordChannelPostEvent(c, pCtx, "post_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "owner_blacklisted", "owner_id": channel.OwnerID}, nil)
 			pCtx.StopPipeline = true
 			return nil
@@ -60,23 +60,23 @@ func StagePreflightTelego(c *container.AppContainer) StageTelego {
 		// 5. Detect Message Type
 		pCtx.MessageType = GetMessageTypeTelego(post)
 		if pCtx.MessageType == "" {
-			logger.Bot("⏭️ Tipo de mensagem não suportado ignorado")
+			logger.BotCtx(pCtx.Ctx, "⏭️ Tipo de mensagem não suportado ignorado")
 			recordChannelPostEvent(c, pCtx, "post_skipped", services.ChannelEventStatusSkipped, map[string]any{"reason": "unsupported_message_type"}, nil)
 			pCtx.StopPipeline = true
 			return nil
 		}
-		logger.Bot("📝 Tipo detectado: %s", pCtx.MessageType)
+		logger.BotCtx(pCtx.Ctx, "📝 Tipo detectado: %s", pCtx.MessageType)
 
 		// 6. Check Permissions
 		pm := GetPermissionManager()
 		pCtx.Permissions = pm.CheckPermissions(channel, pCtx.MessageType)
 		if !pCtx.Permissions.CanEdit && !pCtx.Permissions.CanAddButtons {
-			logger.Error("PIPELINE", "❌ Sem permissões de Edição ou Botões para o canal %d (Tipo: %s)", channel.ID, pCtx.MessageType)
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Sem permissões de Edição ou Botões para o canal %d (Tipo: %s)", channel.ID, pCtx.MessageType)
 			recordChannelPostEvent(c, pCtx, "permission_missing", services.ChannelEventStatusSkipped, map[string]any{"can_edit": false, "can_add_buttons": false}, nil)
 			pCtx.StopPipeline = true
 			return nil
 		}
-		logger.Bot("⚖️ Permissões: Edit=%v, Buttons=%v", pCtx.Permissions.CanEdit, pCtx.Permissions.CanAddButtons)
+		logger.BotCtx(pCtx.Ctx, "⚖️ Permissões: Edit=%v, Buttons=%v", pCtx.Permissions.CanEdit, pCtx.Permissions.CanAddButtons)
 
 		return nil
 	}
@@ -98,7 +98,7 @@ func syncMetadataTelego(pCtx *ProcessingContextTelego, c *container.AppContainer
 	}
 
 	go func() {
-		updateCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		updateCtx, cancel := context.WithTimeout(context.WithoutCancel(pCtx.Ctx), 20*time.Second)
 		defer cancel()
 
 		var chatObj interface{}
@@ -111,7 +111,7 @@ func syncMetadataTelego(pCtx *ProcessingContextTelego, c *container.AppContainer
 
 		if hasChanges {
 			if err := c.ChannelService.UpdateChannelBasicInfoAndFirstButton(updateCtx, updatedChannel); err != nil {
-				logger.Error("PIPELINE", "❌ Error saving metadata for %d: %v", post.Chat.ID, err)
+				logger.ErrorCtx(updateCtx, "PIPELINE", "❌ Error saving metadata for %d: %v", post.Chat.ID, err)
 				recordChannelPostEvent(c, pCtx, "metadata_updated", services.ChannelEventStatusError, map[string]any{"title_changed": titleChanged, "username_changed": usernameChanged}, err)
 			} else {
 				recordChannelPostEvent(c, pCtx, "metadata_updated", services.ChannelEventStatusInfo, map[string]any{"title_changed": titleChanged, "username_changed": usernameChanged}, nil)
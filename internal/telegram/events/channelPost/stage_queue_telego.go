@@ -9,7 +9,8 @@ import (
 func StageQueueTelego(c *container.AppContainer, executionPipeline *PipelineTelego) StageTelego {
 	return func(pCtx *ProcessingContextTelego) error {
 		workerCtx := *pCtx
-		workerCtx.Ctx = context.Background()
+		// Mantém os campos de log (correlation_id...) sem herdar o cancelamento do update.
+		workerCtx.Ctx = context.WithoutCancel(pCtx.Ctx)
 		workerCtx.StopPipeline = false
 		
 		messageQueue.AddTelegoToQueue(&workerCtx, executionPipeline)
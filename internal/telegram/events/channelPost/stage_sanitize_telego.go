@@ -0,0 +1,44 @@
+package channelpost
+
+import (
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+// StageSanitizeTelego corrige o HTML do texto final e aplica o limite de
+// tamanho do Telegram antes do envio, evitando que a edição falhe com "can't
+// parse entities" ou "caption is too long" e o post fique sem legenda.
+func StageSanitizeTelego(c *container.AppContainer) StageTelego {
+	return func(pCtx *ProcessingContextTelego) error {
+		if pCtx.FormattedText == "" || pCtx.MessageType == MessageTypeSticker {
+			return nil
+		}
+
+		text, problems := tghtml.Sanitize(pCtx.FormattedText)
+		if len(problems) > 0 {
+			logger.BotCtx(pCtx.Ctx, "🩹 HTML do post corrigido (%d problemas): %s", len(problems), problems[0])
+			recordChannelPostEvent(c, pCtx, "caption_repaired", services.ChannelEventStatusInfo, map[string]any{"problems": len(problems), "first_problem": problems[0]}, nil)
+		}
+
+		limit := textLimitTelego(pCtx)
+		if length := tghtml.TextLen(text); length > limit {
+			text, _ = tghtml.Truncate(text, limit)
+			logger.BotCtx(pCtx.Ctx, "✂️ Texto do post truncado de %d para %d caracteres", length, limit)
+			recordChannelPostEvent(c, pCtx, "caption_truncated", services.ChannelEventStatusInfo, map[string]any{"length": length, "limit": limit}, nil)
+		}
+
+		pCtx.FormattedText = text
+		return nil
+	}
+}
+
+// textLimitTelego é o limite do Telegram para o texto do post: mensagens de
+// texto aceitam 4096 caracteres e legendas de mídia 1024.
+func textLimitTelego(pCtx *ProcessingContextTelego) int {
+	if pCtx.MessageType == MessageTypeText && !pCtx.IsMediaGroup {
+		return tghtml.MaxTextLength
+	}
+	return tghtml.MaxCaptionLength
+}
@@ -3,17 +3,19 @@ package channelpost
 import (
 	"context"
 	"fmt"
-	"strings"
-	"time"
 
 	"github.com/leirbagxis/FreddyBot/internal/container"
 	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/ratelimit"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
 func StageSendTelego(c *container.AppContainer) StageTelego {
 	return func(pCtx *ProcessingContextTelego) error {
-		logger.Bot("📤 Iniciando envio final Telego (Tipo: %s, Album: %v)", pCtx.MessageType, pCtx.IsMediaGroup)
+		logger.BotCtx(pCtx.Ctx, "📤 Iniciando envio final Telego (Tipo: %s, Album: %v)", pCtx.MessageType, pCtx.IsMediaGroup)
+
+		// Edição de posts de canal tem prioridade sobre broadcasts no limiter.
+		pCtx.Ctx = ratelimit.WithPriority(pCtx.Ctx, ratelimit.PriorityHigh)
 
 		err := processWithRetryTelego(pCtx.Ctx, func() error {
 			if pCtx.IsMediaGroup {
@@ -31,14 +33,39 @@ func StageSendTelego(c *container.AppContainer) StageTelego {
 			return nil
 		})
 
+		failedEvent, processedEvent := "post_failed", "post_processed"
+		if pCtx.IsEdit {
+			failedEvent, processedEvent = "post_edit_failed", "post_edit_processed"
+		}
+
 		if err != nil {
-			logger.Error("BOT", "❌ Falha final no envio Telego: %v", err)
-			recordChannelPostEvent(c, pCtx, "post_failed", services.ChannelEventStatusError, map[string]any{"album": pCtx.IsMediaGroup}, err)
+			logger.ErrorCtx(pCtx.Ctx, "BOT", "❌ Falha final no envio Telego: %v", err)
+			recordChannelPostEvent(c, pCtx, failedEvent, services.ChannelEventStatusError, map[string]any{"album": pCtx.IsMediaGroup}, err)
 			return err
 		}
 
-		recordChannelPostEvent(c, pCtx, "post_processed", services.ChannelEventStatusSuccess, map[string]any{"album": pCtx.IsMediaGroup, "buttons": len(pCtx.FinalButtons), "has_caption": pCtx.FormattedText != ""}, nil)
-		logger.Bot("✅ Postagem Telego concluída com sucesso no canal %d", pCtx.Channel.ID)
+		if pCtx.OverflowText != "" && !stepDoneTelego(pCtx, jobStepOverflowReply) {
+			if err := processWithRetryTelego(pCtx.Ctx, func() error { return sendOverflowReplyTelego(pCtx) }); err != nil {
+				logger.ErrorCtx(pCtx.Ctx, "BOT", "❌ Falha ao enviar o excedente da legenda: %v", err)
+				recordChannelPostEvent(c, pCtx, "caption_overflow_failed", services.ChannelEventStatusError, nil, err)
+			} else {
+				markStepDoneTelego(pCtx, jobStepOverflowReply)
+			}
+		}
+
+		if pCtx.SeparatorDue {
+			scheduleSeparatorTelego(c, pCtx)
+		}
+
+		if !pCtx.IsEdit && usesNativeReactionsTelego(pCtx) {
+			if err := processWithRetryTelego(pCtx.Ctx, func() error { return setNativeReactionTelego(pCtx) }); err != nil {
+				logger.ErrorCtx(pCtx.Ctx, "BOT", "❌ Falha ao reagir ao post: %v", err)
+				recordChannelPostEvent(c, pCtx, "native_reaction_failed", services.ChannelEventStatusError, nil, err)
+			}
+		}
+
+		recordChannelPostEvent(c, pCtx, processedEvent, services.ChannelEventStatusSuccess, map[string]any{"album": pCtx.IsMediaGroup, "buttons": len(pCtx.FinalButtons), "has_caption": pCtx.FormattedText != ""}, nil)
+		logger.BotCtx(pCtx.Ctx, "✅ Postagem Telego concluída com sucesso no canal %d", pCtx.Channel.ID)
 		return nil
 	}
 }
@@ -51,13 +78,9 @@ func processWithRetryTelego(ctx context.Context, fn func() error) error {
 			return nil
 		}
 
-		if strings.Contains(err.Error(), "Too Many Requests") || strings.Contains(err.Error(), "429") {
-			retryAfter := extractRetryAfter(err.Error())
-			if retryAfter == 0 {
-				retryAfter = (attempt + 1) * 2
-			}
-			logger.Bot("⏳ Rate limit Telego, aguardando %d segundos...", retryAfter)
-			time.Sleep(time.Duration(retryAfter) * time.Second)
+		// O limiter já bloqueou o chat pelo retry_after, a próxima chamada aguarda.
+		if retryAfter, ok := ratelimit.RetryAfter(err); ok {
+			logger.BotCtx(ctx, "⏳ Rate limit Telego, aguardando %s...", retryAfter)
 			continue
 		}
 		return err
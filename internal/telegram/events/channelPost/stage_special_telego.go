@@ -1,7 +1,6 @@
 package channelpost
 
 import (
-	"context"
 	"github.com/leirbagxis/FreddyBot/internal/container"
 )
 
@@ -12,7 +11,7 @@ func StageSpecialFlowsTelego(c *container.AppContainer) StageTelego {
 			return nil
 		}
 
-		handled, err := TryHandleNewPackTelego(context.Background(), pCtx.Bot, *pCtx.Channel, *post)
+		handled, err := TryHandleNewPackTelego(pCtx.Ctx, pCtx.Bot, c.CacheService, *pCtx.Channel, *post)
 		if err != nil {
 			return err
 		}
@@ -1,9 +1,11 @@
 package channelpost
 
 import (
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
 	"github.com/leirbagxis/FreddyBot/internal/container"
 	"github.com/leirbagxis/FreddyBot/internal/core/services"
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 	"github.com/mymmrac/telego"
 )
@@ -40,12 +42,16 @@ func StageTransformTelego(c *container.AppContainer) StageTelego {
 		// 2. Format base text
 		formattedBase := ProcessTextWithFormattingTelego(baseText, entities)
 
-		// 2.1 Dynamic Links
+		// 2.1 Content filter (strip action): banned words, patterns and blocked domains
+		formattedBase = stripFilteredTextTelego(pCtx, formattedBase)
+
+		// 2.2 Dynamic Links
+		links := linkrewrite.FromSettings(pCtx.Channel.LinkSettings)
 		extractedDynLinks := false
 		if pCtx.Channel.DynamicLinks {
-			dynButtons, cleanBase := ExtractDynamicLinks(formattedBase)
+			dynButtons, cleanBase := ExtractDynamicLinks(formattedBase, links)
 			if len(dynButtons) > 0 {
-				logger.Bot("🔗 Extraídos %d botões dinâmicos do conteúdo original", len(dynButtons))
+				logger.BotCtx(pCtx.Ctx, "🔗 Extraídos %d botões dinâmicos do conteúdo original", len(dynButtons))
 				recordChannelPostEvent(c, pCtx, "dynamic_links_extracted", services.ChannelEventStatusInfo, map[string]any{"count": len(dynButtons)}, nil)
 				formattedBase = cleanBase
 				extractedDynLinks = true
@@ -53,63 +59,110 @@ func StageTransformTelego(c *container.AppContainer) StageTelego {
 			}
 		}
 
-		// 3. Extract Hashtag
-		hashtag := extractHashtag(formattedBase)
-		var dbCaption string
+		// 3. Select custom caption (rules first, then hashtags)
+		selection := selectCustomCaptionTelego(pCtx, formattedBase)
+		custom := selection.Custom
+		pCtx.CustomCaption = custom
+		var dbCaption, captionTemplate string
 		var finalButtons []dbmodels.Button = pCtx.Channel.Buttons
-		var custom *dbmodels.CustomCaption
 
 		if extractedDynLinks && !pCtx.Channel.DLBotButtons {
 			finalButtons = []dbmodels.Button{}
 		}
 
-		if hashtag != "" {
-			custom = findCustomCaption(pCtx.Channel, hashtag)
-			if custom != nil {
-				cleanBase := removeHashtag(formattedBase, hashtag)
-				formattedBase = cleanBase
-				dbCaption = DetectParseMode(custom.Caption)
+		if custom != nil {
+			if selection.Hashtag != "" {
+				formattedBase = removeHashtag(formattedBase, selection.Hashtag)
+			}
+			captionTemplate = custom.Caption
 
-				if len(custom.Buttons) > 0 {
-					finalButtons = convertCustomButtons(custom.Buttons)
-				}
+			if len(custom.Buttons) > 0 {
+				finalButtons = convertCustomButtons(custom.Buttons)
+			}
 
-				if pCtx.MessageType == MessageTypeText && !custom.LinkPreview {
-					pCtx.DisableLinkPreview = true
-				}
+			if pCtx.MessageType == MessageTypeText && !custom.LinkPreview {
+				pCtx.DisableLinkPreview = true
 			}
 		}
 
-		// 4. Fallback to Default
-		if custom == nil && pCtx.Channel.DefaultCaption != nil {
-			dbCaption = DetectParseMode(pCtx.Channel.DefaultCaption.Caption)
+		// 4. Fallback to the caption pool, then to Default
+		var variant *dbmodels.CaptionVariant
+		var variantReused bool
+		if custom == nil {
+			variant, variantReused = pickCaptionVariantTelego(c, pCtx)
+		}
+		if variant != nil {
+			captionTemplate = variant.Caption
+			if len(variant.Buttons) > 0 && !(extractedDynLinks && !pCtx.Channel.DLBotButtons) {
+				finalButtons = convertVariantButtons(variant.Buttons)
+			}
+		} else if custom == nil && pCtx.Channel.DefaultCaption != nil {
+			captionTemplate = pCtx.Channel.DefaultCaption.Caption
 		}
 
 		if extractedDynLinks && !pCtx.Channel.DLBotCaptions {
-			dbCaption = ""
+			captionTemplate = ""
 		}
 
-		// 5. Final Assembly
-		if pCtx.MessageType == MessageTypeText {
-			pCtx.FormattedText = composeMessage(formattedBase, dbCaption, "\n\n", "append")
-		} else if pCtx.MessageType == MessageTypeAudio {
-			if dbCaption != "" {
-				pCtx.FormattedText = dbCaption
-			} else {
-				pCtx.FormattedText = formattedBase
+		// 4.1 Render template; {original_caption} places the original text itself
+		var placesOriginal bool
+		if captionTemplate != "" {
+			dbCaption, placesOriginal = renderCaptionTelego(captionTemplate, captionTemplateDataTelego(pCtx, formattedBase))
+		}
+
+		// 4.2 Link rules (UTM, allowed/blocked domains and host rewrites) on the caption output
+		if links != nil {
+			var baseLinks, captionLinks int
+			formattedBase, baseLinks = links.HTML(formattedBase)
+			dbCaption, captionLinks = links.HTML(dbCaption)
+			if n := baseLinks + captionLinks; n > 0 {
+				logger.BotCtx(pCtx.Ctx, "🔗 %d links reescritos pelas regras do canal", n)
+				recordChannelPostEvent(c, pCtx, "links_rewritten", services.ChannelEventStatusInfo, map[string]any{"count": n}, nil)
 			}
-		} else {
-			if dbCaption != "" {
-				pCtx.FormattedText = composeMessage(formattedBase, dbCaption, "\n\n", "append")
-			} else {
-				pCtx.FormattedText = formattedBase
+		}
+
+		// 5. Final Assembly (position and separator come from the custom caption or the channel),
+		// closed by the author signature footer and fitted to the Telegram limit by the channel overflow policy
+		position, separator := captionLayoutTelego(pCtx.Channel, custom)
+		footer := signatureFooterTelego(pCtx)
+		compose := func(base string) string {
+			caption := dbCaption
+			if placesOriginal {
+				caption, _ = renderCaptionTelego(captionTemplate, captionTemplateDataTelego(pCtx, base))
+				caption, _ = links.HTML(caption)
+				base = ""
 			}
+			composed := captiontpl.Compose(base, caption, separator, position)
+			return captiontpl.Compose(composed, footer, "", captiontpl.PositionAppend)
+		}
+		pCtx.BaseText, pCtx.ComposeText = formattedBase, compose
+		fitted := fitCaptionTelego(c, pCtx, formattedBase, compose)
+		pCtx.FormattedText = fitted.Text
+		pCtx.OverflowText = fitted.Overflow
+		if fitted.Action == captiontpl.OverflowDrop {
+			dbCaption = ""
 		}
 
 		pCtx.FinalButtons = append(finalButtons, pCtx.FinalButtons...)
 
 		if dbCaption != "" {
-			recordChannelPostEvent(c, pCtx, "caption_applied", services.ChannelEventStatusInfo, map[string]any{"custom_caption": custom != nil, "message_type": pCtx.MessageType}, nil)
+			metadata := map[string]any{"custom_caption": custom != nil, "message_type": pCtx.MessageType}
+			if selection.Rule != nil {
+				metadata["rule_id"] = selection.Rule.RuleID
+				metadata["rule_name"] = selection.Rule.Name
+			}
+			if selection.Hashtag != "" {
+				metadata["hashtag"] = selection.Hashtag
+			}
+			if variant != nil {
+				metadata["variant_id"] = variant.VariantID
+				metadata["variant_name"] = variant.Name
+				metadata["rotation"] = pCtx.Channel.CaptionRotation
+				if !variantReused {
+					c.CaptionVariantService.RecordUsage(pCtx.Ctx, variant.VariantID)
+				}
+			}
+			recordChannelPostEvent(c, pCtx, "caption_applied", services.ChannelEventStatusInfo, metadata, nil)
 		}
 
 		if extractedDynLinks && !pCtx.Channel.DLBotReactions {
@@ -0,0 +1,70 @@
+package channelpost
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/tghtml"
+	"github.com/leirbagxis/FreddyBot/internal/translate"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+)
+
+const translateTimeout = 20 * time.Second
+
+// StageTranslateTelego traduz o texto original do post para o idioma do canal
+// e recompõe o texto final com a legenda. Roda depois do Transform para que
+// só o texto do post seja traduzido, e não a legenda e os botões do canal.
+// Falhas do tradutor não interrompem o envio: o post segue sem tradução.
+func StageTranslateTelego(c *container.AppContainer) StageTelego {
+	return func(pCtx *ProcessingContextTelego) error {
+		language := pCtx.Channel.TranslateLanguage
+		if language == "" || c.Translator == nil || pCtx.ComposeText == nil || !pCtx.Permissions.CanEdit {
+			return nil
+		}
+
+		base := pCtx.BaseText
+		// Em edições o texto já traz a tradução acrescentada no envio anterior.
+		if pCtx.IsEdit {
+			if idx := strings.LastIndex(base, translate.AppendMarker); idx >= 0 {
+				base = base[:idx]
+			}
+		}
+		if htmlToPlainText(base) == "" {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(pCtx.Ctx, translateTimeout)
+		defer cancel()
+		translated, err := c.Translator.Translate(ctx, base, language)
+		if err != nil {
+			logger.ErrorCtx(pCtx.Ctx, "PIPELINE", "❌ Falha ao traduzir o post para %s: %v", language, err)
+			recordChannelPostEvent(c, pCtx, "translation_failed", services.ChannelEventStatusError, map[string]any{"language": language}, err)
+			return nil
+		}
+
+		// O tradutor pode devolver HTML que o Telegram não aceita.
+		translated, _ = tghtml.Sanitize(strings.TrimSpace(translated))
+		if translated == "" || htmlToPlainText(translated) == htmlToPlainText(base) {
+			logger.BotCtx(pCtx.Ctx, "⏭️ Post já está em %s, tradução ignorada", language)
+			return nil
+		}
+
+		mode := pCtx.Channel.TranslateMode
+		if mode != translate.ModeReplace {
+			mode = translate.ModeAppend
+			translated = base + translate.AppendMarker + translated
+		}
+
+		fitted := fitCaptionTelego(c, pCtx, translated, pCtx.ComposeText)
+		pCtx.BaseText = translated
+		pCtx.FormattedText = fitted.Text
+		pCtx.OverflowText = fitted.Overflow
+
+		logger.BotCtx(pCtx.Ctx, "🌐 Post traduzido para %s (%s)", language, mode)
+		recordChannelPostEvent(c, pCtx, "post_translated", services.ChannelEventStatusInfo, map[string]any{"language": language, "mode": mode}, nil)
+		return nil
+	}
+}
@@ -17,8 +17,7 @@ const (
 )
 
 const (
-	CleanupTimeout = 30 * time.Minute
-	CacheTTL       = 10 * time.Minute
+	CacheTTL = 10 * time.Minute
 )
 
 type PermissionCheckResult struct {
@@ -30,8 +29,4 @@ type PermissionCheckResult struct {
 	Reason            string
 }
 
-type ProcessedGroup struct {
-	Timestamp time.Time
-}
-
 type PermissionMap map[string]interface{}
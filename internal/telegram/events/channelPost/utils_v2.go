@@ -1,12 +1,13 @@
 package channelpost
 
 import (
-	"html"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
 	dbmodels "github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/linkrewrite"
 	"github.com/leirbagxis/FreddyBot/internal/utils"
 	"github.com/mymmrac/telego"
 )
@@ -36,25 +37,16 @@ func GetMessageTypeTelego(post *telego.Message) MessageType {
 }
 
 var (
-	retryAfterRegex = regexp.MustCompile(`retry after (\d+)`)
-	hashtagRegex    = regexp.MustCompile(`#(\w+)`)
+	hashtagRegex = regexp.MustCompile(`#(\w+)`)
 )
 
-func extractRetryAfter(errStr string) int {
-	matches := retryAfterRegex.FindStringSubmatch(errStr)
-	if len(matches) > 1 {
-		retryAfter, _ := strconv.Atoi(matches[1])
-		return retryAfter
+// extractHashtags retorna todas as hashtags do texto, na ordem em que aparecem.
+func extractHashtags(text string) []string {
+	var hashtags []string
+	for _, m := range hashtagRegex.FindAllStringSubmatch(text, -1) {
+		hashtags = append(hashtags, m[1])
 	}
-	return 0
-}
-
-func extractHashtag(text string) string {
-	matches := hashtagRegex.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
+	return hashtags
 }
 
 func removeHashtag(text, hashtag string) string {
@@ -73,25 +65,6 @@ func findCustomCaption(channel *dbmodels.Channel, hashtag string) *dbmodels.Cust
 	return nil
 }
 
-func composeMessage(o, d, sep, order string) string {
-	if o == "" && d == "" {
-		return ""
-	}
-	if o == "" {
-		return d
-	}
-	if d == "" {
-		return o
-	}
-	if sep == "" {
-		sep = "\n\n"
-	}
-	if order == "prepend" {
-		return d + sep + o
-	}
-	return o + sep + d
-}
-
 func IsMarkdown(text string) bool {
 	// Verifica se contém marcadores de Markdown (suporta básicos do Telegram)
 	mdChars := []string{"*", "_", "`", "["}
@@ -104,36 +77,16 @@ func IsMarkdown(text string) bool {
 }
 
 func DetectParseMode(text string) string {
-	if text == "" {
-		return ""
-	}
-
-	// Escapar caracteres HTML ANTES de aplicar as regexes de Markdown
-	// Isso garante que '&' vire '&amp;', mas as tags que inserirmos depois (<b>, <i>) fiquem intactas.
-	res := html.EscapeString(text)
-	res, protectedLinks := utils.ProtectMarkdownLinks(res, "channelpost.DetectParseMode")
-
-	// Bold: *text* -> <b>text</b>
-	boldRegex := regexp.MustCompile(`\*([^\*\n]+)\*`)
-	res = boldRegex.ReplaceAllString(res, "<b>$1</b>")
-
-	// Italic: _text_ -> <i>text</i>
-	italicRegex := regexp.MustCompile(`_([^\_\n]+)_`)
-	res = italicRegex.ReplaceAllString(res, "<i>$1</i>")
-
-	// Code: `text` -> <code>text</code>
-	codeRegex := regexp.MustCompile("`([^`\\n]+)`")
-	res = codeRegex.ReplaceAllString(res, "<code>$1</code>")
-
-	res = utils.RestoreProtectedMarkdownLinks(res, protectedLinks)
-
-	return res
+	return captiontpl.MarkdownToHTML(text)
 }
 func int64ToStr(v int64) string {
 	return strconv.FormatInt(v, 10)
 }
 
-func ExtractDynamicLinks(text string) ([]dbmodels.Button, string) {
+// ExtractDynamicLinks transforma os links do texto em botões. As regras de
+// links do canal são aplicadas aos botões; links bloqueados ficam no texto e
+// são tratados junto com a legenda.
+func ExtractDynamicLinks(text string, links *linkrewrite.Rules) ([]dbmodels.Button, string) {
 	var buttons []dbmodels.Button
 	cleanText := text
 	linkPattern := `(?:https?://[^\s<>")]+|tg://[^\s<>")]+|@[^\s<>")]+|(?:t\.me|telegram\.me)/[^\s<>")]+)`
@@ -144,6 +97,10 @@ func ExtractDynamicLinks(text string) ([]dbmodels.Button, string) {
 		if name == "" || !utils.IsValidButtonURL(buttonURL) {
 			return
 		}
+		buttonURL, allowed := links.URL(buttonURL)
+		if !allowed {
+			return
+		}
 
 		buttons = append(buttons, dbmodels.Button{
 			NameButton: name,
@@ -0,0 +1,154 @@
+package mychannel
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/leirbagxis/FreddyBot/pkg/parser"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+var captionPositionLabels = map[string]string{
+	captiontpl.PositionAppend:  "Depois do texto",
+	captiontpl.PositionPrepend: "Antes do texto",
+	captiontpl.PositionWrap:    "Envolvendo o texto",
+	captiontpl.PositionReplace: "Substituindo o texto",
+}
+
+// Separadores oferecidos no menu. Outros valores podem ser definidos pela API.
+var captionSeparatorPresets = map[string]string{
+	"blank": "",
+	"line":  "\n",
+	"space": " ",
+}
+
+var captionSeparatorLabels = map[string]string{
+	"blank": "Linha em branco",
+	"line":  "Quebra de linha",
+	"space": "Espaço",
+}
+
+// CaptionPositionHandlerTelego exibe e altera a posição da legenda e o separador
+// do canal selecionado. Atende "cpos", "cpos:<posição>" e "csep:<separador>".
+func CaptionPositionHandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		if update.CallbackQuery == nil || update.CallbackQuery.Message == nil {
+			return nil
+		}
+
+		bot := ctx.Bot()
+		userId := update.CallbackQuery.From.ID
+		session, err := c.CacheService.GetSelectedChannel(context.Background(), userId)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Seção Expirada. Selecione o canal novamente!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		channel, err := c.ChannelService.GetChannelByTwoID(context.Background(), userId, session)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Canal não encontrado ou não pertence a você!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		position := channel.CaptionPosition
+		if !captiontpl.IsValidPosition(position) {
+			position = captiontpl.PositionAppend
+		}
+		separator := channel.CaptionSeparator
+
+		action, value, _ := strings.Cut(update.CallbackQuery.Data, ":")
+		changed := false
+		switch action {
+		case "cpos":
+			if value != "" && value != position && captiontpl.IsValidPosition(value) {
+				position, changed = value, true
+			}
+		case "csep":
+			if preset, ok := captionSeparatorPresets[value]; ok && preset != separator {
+				separator, changed = preset, true
+			}
+		}
+
+		answer := ""
+		if changed {
+			_, err = c.CaptionService.UpdateCaptionLayout(context.Background(), session, types.CaptionLayoutUpdateRequest{
+				Position:  position,
+				Separator: separator,
+			})
+			if err != nil {
+				logger.Error("BOT", "Erro ao atualizar posição da legenda: %v", err)
+				_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+					CallbackQueryID: update.CallbackQuery.ID,
+					Text:            "❌ Não foi possível salvar a alteração.",
+					ShowAlert:       true,
+				})
+				return nil
+			}
+			answer = "✅ Configuração salva!"
+		}
+
+		channelName := channel.Title
+		if channelName == "" {
+			channelName = fmt.Sprintf("Canal %d", session)
+		}
+
+		text, kb := parser.GetMessageTelego("caption-position-message", captionPositionVars(channel, channelName, position, separator))
+		params := &telego.EditMessageTextParams{
+			ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
+			Text:      text,
+			ParseMode: telego.ModeHTML,
+			MessageID: update.CallbackQuery.Message.GetMessageID(),
+		}
+		if kb != nil {
+			params.ReplyMarkup = kb
+		}
+		_, _ = bot.EditMessageText(context.Background(), params)
+
+		_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: update.CallbackQuery.ID,
+			Text:            answer,
+		})
+		return nil
+	}
+}
+
+func captionPositionVars(channel *models.Channel, channelName, position, separator string) map[string]string {
+	vars := map[string]string{
+		"channelName": channelName,
+		"channelId":   fmt.Sprintf("%d", channel.ID),
+		"position":    captionPositionLabels[position],
+	}
+
+	for _, p := range []string{captiontpl.PositionAppend, captiontpl.PositionPrepend, captiontpl.PositionWrap, captiontpl.PositionReplace} {
+		vars[p+"Mark"] = ""
+		if p == position {
+			vars[p+"Mark"] = "✅ "
+		}
+	}
+
+	vars["separator"] = fmt.Sprintf("personalizado (<code>%s</code>)", html.EscapeString(separator))
+	for key, preset := range captionSeparatorPresets {
+		vars[key+"Mark"] = ""
+		if preset == separator {
+			vars[key+"Mark"] = "✅ "
+			vars["separator"] = captionSeparatorLabels[key]
+		}
+	}
+	return vars
+}
@@ -47,12 +47,7 @@ func AskStickerSeparatorHandlerTelego(c *container.AppContainer) telegohandler.H
 			return nil
 		}
 
-		data := map[string]string{
-			"channelName": channel.Title,
-			"channelId":   fmt.Sprintf("%d", session),
-		}
-
-		text, kb := parser.GetMessageTelego("ask-separator-message", data)
+		text, kb := parser.GetMessageTelego("ask-separator-message", separatorMenuVars(channel))
 		params := &telego.EditMessageTextParams{
 			ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
 			Text:      text,
@@ -99,7 +94,17 @@ func RequireStickerSeparatorHandlerTelego(c *container.AppContainer) telegohandl
 			return nil
 		}
 
-		c.CacheService.SetAwaitingStickerSeparator(context.Background(), userId, session)
+		target, alert := separatorTarget(channel, update.CallbackQuery.Data)
+		if alert != "" {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            alert,
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		c.CacheService.SetAwaitingStickerSeparator(context.Background(), userId, session, target)
 
 		channelName := channel.Title
 		if channelName == "" {
@@ -109,6 +114,7 @@ func RequireStickerSeparatorHandlerTelego(c *container.AppContainer) telegohandl
 		vars := map[string]string{
 			"channelName": channelName,
 			"channelId":   fmt.Sprintf("%d", session),
+			"target":      separatorTargetLabel(target),
 		}
 
 		text, kb := parser.GetMessageTelego("require-separator-message", vars)
@@ -138,7 +144,7 @@ func SetStickerSeparatorHandlerTelego(c *container.AppContainer) telegohandler.H
 
 		bot := ctx.Bot()
 		userId := update.Message.From.ID
-		channelId, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), userId)
+		channelId, target, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), userId)
 		if channelId == 0 {
 			return nil
 		}
@@ -159,16 +165,23 @@ func SetStickerSeparatorHandlerTelego(c *container.AppContainer) telegohandler.H
 			stickerLink = fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", config.TelegramBotToken, file.FilePath)
 		}
 
-		separator := &separatorModels.Separator{
-			ID:             uuid.NewString(),
-			OwnerChannelID: channelId,
-			SeparatorID:    stickerId,
-			SeparatorURL:   stickerLink,
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
+		if target == "" {
+			separator := &separatorModels.Separator{
+				ID:             uuid.NewString(),
+				OwnerChannelID: channelId,
+				SeparatorID:    stickerId,
+				SeparatorURL:   stickerLink,
+				CreatedAt:      time.Now(),
+				UpdatedAt:      time.Now(),
+			}
+			err = c.SeparatorService.SaveSeparator(context.Background(), separator)
+		} else {
+			// Variantes do pool: rodízio, tipo de mensagem ou legenda customizada.
+			err = c.SeparatorService.AddVariant(context.Background(), newSeparatorVariant(channelId, target, stickerId, stickerLink))
 		}
 
-		if err = c.SeparatorService.SaveSeparator(context.Background(), separator); err != nil {
+		if err != nil {
+			logger.Error("BOT", "Erro ao salvar separador do canal %d: %v", channelId, err)
 			text, kb := parser.GetMessageTelego("failed-save-separator", map[string]string{
 				"channelId": fmt.Sprintf("%d", channelId),
 			})
@@ -0,0 +1,368 @@
+package mychannel
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/captiontpl"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/separatorpool"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/leirbagxis/FreddyBot/pkg/parser"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+// Destinos do sticker aguardado pelo fluxo "sptc": vazio troca o principal,
+// "pool" cria uma variante do rodízio e "type:<tipo>" ou "tag:<código>" uma
+// variante por tipo de mensagem ou por legenda customizada.
+const (
+	separatorTargetPool = "pool"
+	separatorTargetType = "type:"
+	separatorTargetTag  = "tag:"
+)
+
+var separatorRotationCycle = []string{separatorpool.ModeOff, separatorpool.ModeRoundRobin, separatorpool.ModeRandom}
+
+var separatorRotationLabels = map[string]string{
+	separatorpool.ModeOff:        "desligado",
+	separatorpool.ModeRoundRobin: "em sequência",
+	separatorpool.ModeRandom:     "aleatório",
+}
+
+// Intervalos de silêncio oferecidos no menu. Outros valores podem ser
+// definidos pela API.
+var separatorCooldownPresets = []int{0, 5, 15, 30, 60}
+
+// SeparatorPoolHandlerTelego altera o rodízio e o intervalo de silêncio do
+// separador e limpa as variantes. Atende "sptc-rot", "sptc-cd", "sptc-clear"
+// (que só pede confirmação) e "sptc-clear-ok", e redesenha o menu do separador.
+func SeparatorPoolHandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		if update.CallbackQuery == nil || update.CallbackQuery.Message == nil {
+			return nil
+		}
+
+		bot := ctx.Bot()
+		userId := update.CallbackQuery.From.ID
+		session, err := c.CacheService.GetSelectedChannel(context.Background(), userId)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Seção Expirada. Selecione o canal novamente!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		channel, err := c.ChannelService.GetChannelByTwoID(context.Background(), userId, session)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Canal não encontrado ou não pertence a você!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		if channel.Separator == nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "❌ Adicione primeiro o sticker principal do separador.",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		answer := "✅ Configuração salva!"
+		switch update.CallbackQuery.Data {
+		case "sptc-clear":
+			if len(channel.SeparatorVariants) == 0 {
+				_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+					CallbackQueryID: update.CallbackQuery.ID,
+					Text:            "❌ O separador não tem variantes.",
+					ShowAlert:       true,
+				})
+				return nil
+			}
+
+			vars := separatorMenuVars(channel)
+			vars["count"] = fmt.Sprintf("%d", len(channel.SeparatorVariants))
+			text, kb := parser.GetMessageTelego("separator-clear-message", vars)
+			params := &telego.EditMessageTextParams{
+				ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
+				Text:      text,
+				ParseMode: telego.ModeHTML,
+				MessageID: update.CallbackQuery.Message.GetMessageID(),
+			}
+			if kb != nil {
+				params.ReplyMarkup = kb
+			}
+			_, _ = bot.EditMessageText(context.Background(), params)
+
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+			})
+			return nil
+		case "sptc-clear-ok":
+			removed, err := c.SeparatorService.DeleteVariants(context.Background(), session)
+			if err != nil {
+				logger.Error("BOT", "Erro ao remover variantes do separador: %v", err)
+				answer = "❌ Não foi possível salvar a alteração."
+				break
+			}
+			channel.SeparatorVariants = nil
+			answer = fmt.Sprintf("🧹 %d variantes removidas", removed)
+		default:
+			settings := types.SeparatorSettingsRequest{
+				Rotation:        channel.Separator.Rotation,
+				CooldownMinutes: channel.Separator.CooldownMinutes,
+			}
+			if update.CallbackQuery.Data == "sptc-rot" {
+				settings.Rotation = nextInCycle(separatorRotationCycle, settings.Rotation)
+			} else {
+				settings.CooldownMinutes = nextInCycle(separatorCooldownPresets, settings.CooldownMinutes)
+			}
+
+			sep, err := c.SeparatorService.UpdateSettings(context.Background(), session, settings)
+			if err != nil {
+				logger.Error("BOT", "Erro ao atualizar separador: %v", err)
+				answer = "❌ Não foi possível salvar a alteração."
+				break
+			}
+			channel.Separator = sep
+		}
+
+		text, kb := parser.GetMessageTelego("ask-separator-message", separatorMenuVars(channel))
+		params := &telego.EditMessageTextParams{
+			ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
+			Text:      text,
+			ParseMode: telego.ModeHTML,
+			MessageID: update.CallbackQuery.Message.GetMessageID(),
+		}
+		if kb != nil {
+			params.ReplyMarkup = kb
+		}
+		_, _ = bot.EditMessageText(context.Background(), params)
+
+		_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: update.CallbackQuery.ID,
+			Text:            answer,
+		})
+		return nil
+	}
+}
+
+// SeparatorTargetsHandlerTelego lista os tipos de mensagem ("sptc-types") ou as
+// legendas customizadas ("sptc-tags") que podem ganhar um separador próprio.
+func SeparatorTargetsHandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		if update.CallbackQuery == nil || update.CallbackQuery.Message == nil {
+			return nil
+		}
+
+		bot := ctx.Bot()
+		userId := update.CallbackQuery.From.ID
+		session, err := c.CacheService.GetSelectedChannel(context.Background(), userId)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Seção Expirada. Selecione o canal novamente!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		channel, err := c.ChannelService.GetChannelByTwoID(context.Background(), userId, session)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Canal não encontrado ou não pertence a você!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		alert := ""
+		if channel.Separator == nil {
+			alert = "❌ Adicione primeiro o sticker principal do separador."
+		} else if update.CallbackQuery.Data == "sptc-tags" && len(channel.CustomCaptions) == 0 {
+			alert = "❌ O canal ainda não tem legendas customizadas."
+		}
+		if alert != "" {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            alert,
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		vars := map[string]string{
+			"channelName": html.EscapeString(channelDisplayName(channel)),
+			"channelId":   fmt.Sprintf("%d", session),
+		}
+
+		name := "separator-types-message"
+		if update.CallbackQuery.Data == "sptc-tags" {
+			name = "separator-hashtags-message"
+		}
+		text, kb := parser.GetMessageTelego(name, vars)
+		if update.CallbackQuery.Data == "sptc-tags" {
+			kb = separatorHashtagsKeyboard(channel)
+		}
+
+		params := &telego.EditMessageTextParams{
+			ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
+			Text:      text,
+			ParseMode: telego.ModeHTML,
+			MessageID: update.CallbackQuery.Message.GetMessageID(),
+		}
+		if kb != nil {
+			params.ReplyMarkup = kb
+		}
+		_, _ = bot.EditMessageText(context.Background(), params)
+
+		_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: update.CallbackQuery.ID,
+		})
+		return nil
+	}
+}
+
+// separatorHashtagsKeyboard monta um botão por legenda customizada, dois por
+// linha. O callback leva o ID da legenda para caber no limite do Telegram.
+func separatorHashtagsKeyboard(channel *models.Channel) *telego.InlineKeyboardMarkup {
+	var rows [][]telego.InlineKeyboardButton
+	var row []telego.InlineKeyboardButton
+	for _, cc := range channel.CustomCaptions {
+		row = append(row, telego.InlineKeyboardButton{
+			Text:         "#" + cc.Code,
+			CallbackData: "sptc-tag:" + cc.CaptionID,
+		})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, []telego.InlineKeyboardButton{{Text: "🔙 Voltar", CallbackData: "sptc"}})
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// separatorTarget interpreta o botão que pediu o sticker ("sptc-config",
+// "sptc-pool", "sptc-type:<tipo>" ou "sptc-tag:<legenda>"). Retorna o destino
+// guardado na espera ou o aviso para o usuário quando não é possível usá-lo.
+func separatorTarget(channel *models.Channel, data string) (target string, alert string) {
+	if data == "sptc-config" {
+		return "", ""
+	}
+	if channel.Separator == nil {
+		return "", "❌ Adicione primeiro o sticker principal do separador."
+	}
+
+	switch {
+	case data == "sptc-pool":
+		return separatorTargetPool, ""
+	case strings.HasPrefix(data, "sptc-type:"):
+		messageType := strings.TrimPrefix(data, "sptc-type:")
+		if !slices.Contains(captiontpl.MessageTypes, messageType) {
+			return "", "❌ Tipo de mensagem inválido."
+		}
+		return separatorTargetType + messageType, ""
+	case strings.HasPrefix(data, "sptc-tag:"):
+		captionID := strings.TrimPrefix(data, "sptc-tag:")
+		for _, cc := range channel.CustomCaptions {
+			if cc.CaptionID == captionID {
+				return separatorTargetTag + separatorpool.NormalizeHashtag(cc.Code), ""
+			}
+		}
+		return "", "❌ Legenda customizada não encontrada."
+	}
+	return "", "❌ Opção inválida."
+}
+
+// newSeparatorVariant monta a variante para um destino diferente do principal.
+func newSeparatorVariant(channelID int64, target, stickerID, stickerURL string) *models.SeparatorVariant {
+	variant := &models.SeparatorVariant{
+		OwnerChannelID: channelID,
+		SeparatorID:    stickerID,
+		SeparatorURL:   stickerURL,
+	}
+	if messageType, ok := strings.CutPrefix(target, separatorTargetType); ok {
+		variant.MessageType = messageType
+	} else if hashtag, ok := strings.CutPrefix(target, separatorTargetTag); ok {
+		variant.Hashtag = hashtag
+	}
+	return variant
+}
+
+func separatorTargetLabel(target string) string {
+	switch {
+	case target == "":
+		return "sticker principal"
+	case target == separatorTargetPool:
+		return "variante do rodízio"
+	case strings.HasPrefix(target, separatorTargetType):
+		return "posts de " + captiontpl.MessageTypeLabel(strings.TrimPrefix(target, separatorTargetType))
+	}
+	return "posts com a legenda <code>#" + html.EscapeString(strings.TrimPrefix(target, separatorTargetTag)) + "</code>"
+}
+
+func separatorMenuVars(channel *models.Channel) map[string]string {
+	vars := map[string]string{
+		"channelName": html.EscapeString(channelDisplayName(channel)),
+		"channelId":   fmt.Sprintf("%d", channel.ID),
+		"status":      "❌ não definido",
+		"variants":    "nenhuma",
+		"rotation":    separatorRotationLabels[separatorpool.ModeOff],
+		"cooldown":    "desligado",
+	}
+
+	if sep := channel.Separator; sep != nil {
+		vars["status"] = "✅ definido"
+		if label, ok := separatorRotationLabels[sep.Rotation]; ok {
+			vars["rotation"] = label
+		}
+		if sep.CooldownMinutes > 0 {
+			vars["cooldown"] = fmt.Sprintf("%d min", sep.CooldownMinutes)
+		}
+	}
+
+	if len(channel.SeparatorVariants) > 0 {
+		general, byType, byTag := 0, 0, 0
+		for _, v := range channel.SeparatorVariants {
+			switch {
+			case v.MessageType != "":
+				byType++
+			case v.Hashtag != "":
+				byTag++
+			default:
+				general++
+			}
+		}
+		vars["variants"] = fmt.Sprintf("%d (%d no rodízio, %d por tipo, %d por legenda)", len(channel.SeparatorVariants), general, byType, byTag)
+	}
+	return vars
+}
+
+func channelDisplayName(channel *models.Channel) string {
+	if channel.Title == "" {
+		return fmt.Sprintf("Canal %d", channel.ID)
+	}
+	return channel.Title
+}
+
+// nextInCycle devolve o item seguinte ao atual; valores fora da lista voltam
+// para o primeiro.
+func nextInCycle[T comparable](cycle []T, current T) T {
+	i := slices.Index(cycle, current)
+	return cycle[(i+1)%len(cycle)]
+}
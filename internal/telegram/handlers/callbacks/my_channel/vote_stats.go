@@ -0,0 +1,113 @@
+package mychannel
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/leirbagxis/FreddyBot/internal/api/types"
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/leirbagxis/FreddyBot/pkg/parser"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+const voteStatsTopPosts = 5
+
+// VoteStatsHandlerTelego exibe o resumo dos votos de reação do canal
+// selecionado nos últimos 30 dias.
+func VoteStatsHandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		if update.CallbackQuery == nil || update.CallbackQuery.Message == nil {
+			return nil
+		}
+
+		bot := ctx.Bot()
+		userId := update.CallbackQuery.From.ID
+		session, err := c.CacheService.GetSelectedChannel(context.Background(), userId)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Seção Expirada. Selecione o canal novamente!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		channel, err := c.ChannelService.GetChannelByTwoID(context.Background(), userId, session)
+		if err != nil {
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "⌛ Canal não encontrado ou não pertence a você!",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		stats, err := c.VoteService.Analytics(context.Background(), session, services.DefaultVoteAnalyticsDays, voteStatsTopPosts)
+		if err != nil {
+			logger.Error("BOT", "Erro ao carregar estatísticas de votos: %v", err)
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "❌ Não foi possível carregar as estatísticas.",
+				ShowAlert:       true,
+			})
+			return nil
+		}
+
+		channelName := channel.Title
+		if channelName == "" {
+			channelName = fmt.Sprintf("Canal %d", session)
+		}
+
+		text, kb := parser.GetMessageTelego("vote-stats-message", voteStatsVars(stats, channelName, session))
+		params := &telego.EditMessageTextParams{
+			ChatID:    update.CallbackQuery.Message.GetChat().ChatID(),
+			Text:      text,
+			ParseMode: telego.ModeHTML,
+			MessageID: update.CallbackQuery.Message.GetMessageID(),
+		}
+		if kb != nil {
+			params.ReplyMarkup = kb
+		}
+		_, _ = bot.EditMessageText(context.Background(), params)
+
+		_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: update.CallbackQuery.ID,
+		})
+		return nil
+	}
+}
+
+func voteStatsVars(stats *types.VoteAnalyticsResponse, channelName string, channelID int64) map[string]string {
+	emojis := make([]string, 0, len(stats.ByEmoji))
+	for _, e := range stats.ByEmoji {
+		emojis = append(emojis, fmt.Sprintf("%s <b>%d</b> (%.1f%%)", html.EscapeString(e.Emoji), e.Votes, e.Share))
+	}
+	if len(emojis) == 0 {
+		emojis = append(emojis, "<i>Nenhum voto no período.</i>")
+	}
+
+	topPosts := make([]string, 0, len(stats.TopPosts))
+	for i, p := range stats.TopPosts {
+		topPosts = append(topPosts, fmt.Sprintf("%d. <a href=\"%s\">Post %d</a> — <b>%d</b> votos", i+1, p.Link, p.MessageID, p.Votes))
+	}
+	if len(topPosts) == 0 {
+		topPosts = append(topPosts, "<i>Nenhum post votado no período.</i>")
+	}
+
+	return map[string]string{
+		"channelName":     html.EscapeString(channelName),
+		"channelId":       fmt.Sprintf("%d", channelID),
+		"days":            fmt.Sprintf("%d", stats.Days),
+		"totalVotes":      fmt.Sprintf("%d", stats.TotalVotes),
+		"uniqueVoters":    fmt.Sprintf("%d", stats.UniqueVoters),
+		"nativeReactions": fmt.Sprintf("%d", stats.NativeReactions),
+		"posts":           fmt.Sprintf("%d", stats.Posts),
+		"emojis":          strings.Join(emojis, " · "),
+		"topPosts":        strings.Join(topPosts, "\n"),
+	}
+}
@@ -0,0 +1,76 @@
+package vote
+
+import (
+	"context"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+// ResultsHandlerTelego mostra os percentuais da votação do post em um alerta.
+// Com as contagens ocultas, só quem já votou vê o resultado antes do prazo.
+func ResultsHandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		query := update.CallbackQuery
+		if query == nil {
+			return nil
+		}
+
+		var chatID int64
+		var messageID int
+		var replyMarkup *telego.InlineKeyboardMarkup
+		postedAt := time.Now()
+		if query.Message != nil {
+			chatID = query.Message.GetChat().ID
+			messageID = query.Message.GetMessageID()
+			if m, ok := query.Message.(*telego.Message); ok {
+				replyMarkup = m.ReplyMarkup
+				if m.Date > 0 {
+					postedAt = time.Unix(m.Date, 0)
+				}
+			}
+		}
+		inlineMessageID := query.InlineMessageID
+
+		answer := &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID, ShowAlert: true}
+		defer func() { _ = ctx.Bot().AnswerCallbackQuery(context.Background(), answer) }()
+
+		poll, err := c.VoteService.Poll(context.Background(), chatID, messageID, inlineMessageID, postedAt)
+		if err != nil {
+			logger.Error("VOTE", "Erro ao carregar votação: %v", err)
+			answer.Text = "Erro ao carregar o resultado."
+			return nil
+		}
+
+		now := time.Now()
+		if votepoll.HidesCounts(poll, now) {
+			voted, err := c.VoteService.HasVoted(context.Background(), chatID, messageID, inlineMessageID, query.From.ID)
+			if err != nil {
+				logger.Error("VOTE", "Erro ao verificar voto: %v", err)
+				answer.Text = "Erro ao carregar o resultado."
+				return nil
+			}
+			if !voted {
+				answer.Text = "🙈 Vote para ver o resultado."
+				return nil
+			}
+		}
+
+		counts, err := c.VoteService.GetVoteCounts(context.Background(), chatID, messageID, inlineMessageID)
+		if err != nil {
+			logger.Error("VOTE", "Erro ao buscar contagens: %v", err)
+			answer.Text = "Erro ao carregar o resultado."
+			return nil
+		}
+
+		answer.Text = votepoll.Results("📊 Resultado\n\n", counts, voteEmojis(replyMarkup, counts))
+		if votepoll.IsClosed(poll, now) {
+			answer.Text = votepoll.Results("🔒 Votação encerrada\n\n", counts, voteEmojis(replyMarkup, counts))
+		}
+		return nil
+	}
+}
@@ -3,11 +3,16 @@ package vote
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/mymmrac/telego"
 	"github.com/mymmrac/telego/telegohandler"
 	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 )
 
@@ -47,10 +52,14 @@ func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 			return nil
 		}
 
-		// 1. Registrar/Alternar voto no banco de dados
-		added, _, err := c.VoteService.ToggleVote(context.Background(), chatID, messageID, inlineMessageID, update.CallbackQuery.From.ID, votedEmoji)
+		postedAt := time.Now()
+		if m, ok := update.CallbackQuery.Message.(*telego.Message); ok && m.Date > 0 {
+			postedAt = time.Unix(m.Date, 0)
+		}
+
+		poll, err := c.VoteService.Poll(context.Background(), chatID, messageID, inlineMessageID, postedAt)
 		if err != nil {
-			logger.Error("VOTE", "Erro ao processar voto no banco: %v", err)
+			logger.Error("VOTE", "Erro ao carregar votação: %v", err)
 			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
 				CallbackQueryID: update.CallbackQuery.ID,
 				Text:            "Erro ao computar voto.",
@@ -58,117 +67,197 @@ func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 			return nil
 		}
 
-		// Feedback visual para o usuário
-		feedback := fmt.Sprintf("Voto removido de %s", votedEmoji)
-		if added {
-			feedback = fmt.Sprintf("Você votou em %s!", votedEmoji)
+		// 1. Registrar/Alternar o voto no Redis, que já devolve as contagens
+		// (votações encerradas não aceitam votos, só revelam as contagens)
+		now := time.Now()
+		closed := votepoll.IsClosed(poll, now)
+		hidden := votepoll.HidesCounts(poll, now)
+		added := false
+		var counts map[string]int64
+		if closed {
+			counts, err = c.VoteService.GetVoteCounts(context.Background(), chatID, messageID, inlineMessageID)
+		} else {
+			added, counts, err = c.VoteService.CastVote(context.Background(), poll, chatID, messageID, inlineMessageID, update.CallbackQuery.From.ID, votedEmoji)
 		}
-		_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
-			CallbackQueryID: update.CallbackQuery.ID,
-			Text:            feedback,
-		})
-
-		// 2. Buscar contagens atualizadas para todos os emojis desta mensagem
-		counts, err := c.VoteService.GetVoteCounts(context.Background(), chatID, messageID, inlineMessageID)
 		if err != nil {
-			logger.Error("VOTE", "Erro ao buscar contagens: %v", err)
+			logger.Error("VOTE", "Erro ao processar voto: %v", err)
+			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+				CallbackQueryID: update.CallbackQuery.ID,
+				Text:            "Erro ao computar voto.",
+			})
 			return nil
 		}
 
-		ikb := replyMarkup
-		updated := false
-
-		// Reconstrução de teclado para mensagens inline (se necessário)
-		if ikb == nil && inlineMessageID != "" {
-			var sessionID string
-			key := fmt.Sprintf("pb_inline_map:%s", inlineMessageID)
-			err := c.CacheService.Get(context.Background(), key, &sessionID)
-			if err == nil && sessionID != "" {
-				state, _ := c.CacheService.GetPostBuilderSession(context.Background(), sessionID)
-				if state != nil {
-					ikb = &telego.InlineKeyboardMarkup{}
-					// Botões de URL
-					for _, btn := range state.Buttons {
-						ikb.InlineKeyboard = append(ikb.InlineKeyboard, []telego.InlineKeyboardButton{
-							{Text: btn.Text, URL: btn.URL},
+		// 2. Feedback imediato para o usuário. Com as contagens ocultas, quem
+		// vota vê o resultado parcial no alerta.
+		answer := &telego.AnswerCallbackQueryParams{
+			CallbackQueryID: update.CallbackQuery.ID,
+			Text:            fmt.Sprintf("Voto removido de %s", votedEmoji),
+		}
+		switch {
+		case closed:
+			answer.Text = votepoll.Results("🔒 Votação encerrada.\n\n", counts, voteEmojis(replyMarkup, counts))
+			answer.ShowAlert = true
+		case added && hidden:
+			answer.Text = votepoll.Results(fmt.Sprintf("Você votou em %s!\n\n", votedEmoji), counts, voteEmojis(replyMarkup, counts))
+			answer.ShowAlert = true
+		case added:
+			answer.Text = fmt.Sprintf("Você votou em %s!", votedEmoji)
+		}
+		_ = bot.AnswerCallbackQuery(context.Background(), answer)
+
+		// 3. O teclado é redesenhado uma vez por janela, com as contagens do
+		// fim dela, em vez de uma edição por toque
+		scheduleKeyboardRender(c, bot, poll, chatID, messageID, inlineMessageID, replyMarkup)
+
+		return nil
+	}
+}
+
+// keyboardRenderWindow agrupa os votos de uma mensagem em um único
+// EditMessageReplyMarkup.
+const keyboardRenderWindow = 1500 * time.Millisecond
+
+// scheduleKeyboardRender agenda o redesenho do teclado da mensagem, a menos
+// que outro voto (nesta ou em outra instância) já tenha agendado um.
+func scheduleKeyboardRender(c *container.AppContainer, bot *telego.Bot, poll *models.VotePoll, chatID int64, messageID int, inlineMessageID string, replyMarkup *telego.InlineKeyboardMarkup) {
+	key := services.VoteMessageKey(chatID, messageID, inlineMessageID)
+	claimed, err := c.CacheService.ClaimVoteRender(context.Background(), key, keyboardRenderWindow)
+	if err != nil {
+		logger.Error("VOTE", "Erro ao agendar atualização do teclado: %v", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	time.AfterFunc(keyboardRenderWindow, func() {
+		renderKeyboard(c, bot, poll, key, chatID, messageID, inlineMessageID, replyMarkup)
+	})
+}
+
+// renderKeyboard libera a reserva e só depois lê as contagens: votos que
+// chegarem durante a edição agendam um novo redesenho.
+func renderKeyboard(c *container.AppContainer, bot *telego.Bot, poll *models.VotePoll, key string, chatID int64, messageID int, inlineMessageID string, replyMarkup *telego.InlineKeyboardMarkup) {
+	ctx := context.Background()
+	if err := c.CacheService.ReleaseVoteRender(ctx, key); err != nil {
+		logger.Error("VOTE", "Erro ao liberar atualização do teclado: %v", err)
+	}
+
+	counts, err := c.VoteService.GetVoteCounts(ctx, chatID, messageID, inlineMessageID)
+	if err != nil {
+		logger.Error("VOTE", "Erro ao buscar contagens: %v", err)
+		return
+	}
+	hidden := votepoll.HidesCounts(poll, time.Now())
+
+	ikb := replyMarkup
+	updated := false
+
+	// Reconstrução de teclado para mensagens inline (se necessário)
+	if ikb == nil && inlineMessageID != "" {
+		var sessionID string
+		key := fmt.Sprintf("pb_inline_map:%s", inlineMessageID)
+		err := c.CacheService.Get(ctx, key, &sessionID)
+		if err == nil && sessionID != "" {
+			state, _ := c.CacheService.GetPostBuilderSession(ctx, sessionID)
+			if state != nil {
+				ikb = &telego.InlineKeyboardMarkup{}
+				// Botões de URL
+				for _, btn := range state.Buttons {
+					ikb.InlineKeyboard = append(ikb.InlineKeyboard, []telego.InlineKeyboardButton{
+						{Text: btn.Text, URL: btn.URL},
+					})
+				}
+				// Reações
+				if state.Reactions != "" {
+					reactions := strings.Split(state.Reactions, ",")
+					var reactionRow []telego.InlineKeyboardButton
+					for _, r := range reactions {
+						emoji := strings.TrimSpace(r)
+						if emoji == "" {
+							continue
+						}
+
+						reactionRow = append(reactionRow, telego.InlineKeyboardButton{
+							Text:         votepoll.ButtonText(emoji, counts[emoji], hidden),
+							CallbackData: "vote:" + emoji,
 						})
 					}
-					// Reações
-					if state.Reactions != "" {
-						reactions := strings.Split(state.Reactions, ",")
-						var reactionRow []telego.InlineKeyboardButton
-						for _, r := range reactions {
-							emoji := strings.TrimSpace(r)
-							if emoji == "" {
-								continue
-							}
-
-							count := counts[emoji]
-							text := emoji
-							if count > 0 {
-								text = fmt.Sprintf("%s %d", emoji, count)
-							}
-
-							reactionRow = append(reactionRow, telego.InlineKeyboardButton{
-								Text:         text,
-								CallbackData: "vote:" + emoji,
-							})
-						}
-						if len(reactionRow) > 0 {
-							ikb.InlineKeyboard = append(ikb.InlineKeyboard, reactionRow)
-						}
+					if len(reactionRow) > 0 {
+						ikb.InlineKeyboard = append(ikb.InlineKeyboard, reactionRow)
 					}
-					updated = true
 				}
+				updated = true
 			}
 		}
+	}
 
-		if ikb == nil {
-			return nil
-		}
+	if ikb == nil {
+		return
+	}
 
-		// Atualizar o teclado (seja o original ou o reconstruído)
-		for i, row := range ikb.InlineKeyboard {
-			for j, btn := range row {
-				if strings.HasPrefix(btn.CallbackData, "vote:") {
-					emoji := strings.TrimPrefix(btn.CallbackData, "vote:")
-					count := counts[emoji]
-
-					var newText string
-					if count > 0 {
-						newText = fmt.Sprintf("%s %d", emoji, count)
-					} else {
-						newText = emoji
-					}
+	// Atualizar o teclado (seja o original ou o reconstruído)
+	for i, row := range ikb.InlineKeyboard {
+		for j, btn := range row {
+			if strings.HasPrefix(btn.CallbackData, "vote:") {
+				emoji := strings.TrimPrefix(btn.CallbackData, "vote:")
+				newText := votepoll.ButtonText(emoji, counts[emoji], hidden)
 
-					if ikb.InlineKeyboard[i][j].Text != newText {
-						ikb.InlineKeyboard[i][j].Text = newText
-						updated = true
-					}
+				if ikb.InlineKeyboard[i][j].Text != newText {
+					ikb.InlineKeyboard[i][j].Text = newText
+					updated = true
 				}
 			}
 		}
+	}
 
-		if updated {
-			editParams := &telego.EditMessageReplyMarkupParams{
-				ReplyMarkup: ikb,
-			}
-			if inlineMessageID != "" {
-				editParams.InlineMessageID = inlineMessageID
-			} else {
-				editParams.ChatID = telego.ChatID{ID: chatID}
-				editParams.MessageID = messageID
-			}
+	if !updated {
+		return
+	}
+
+	editParams := &telego.EditMessageReplyMarkupParams{
+		ReplyMarkup: ikb,
+	}
+	if inlineMessageID != "" {
+		editParams.InlineMessageID = inlineMessageID
+	} else {
+		editParams.ChatID = telego.ChatID{ID: chatID}
+		editParams.MessageID = messageID
+	}
+
+	if _, err := bot.EditMessageReplyMarkup(ctx, editParams); err != nil {
+		if !strings.Contains(err.Error(), "message is not modified") {
+			logger.Error("VOTE", "Erro ao editar teclado: %v", err)
+		}
+	}
+}
 
-			_, err := bot.EditMessageReplyMarkup(context.Background(), editParams)
-			if err != nil {
-				errStr := err.Error()
-				if !strings.Contains(errStr, "message is not modified") {
-					logger.Error("VOTE", "Erro ao editar teclado: %v", err)
+// voteEmojis lista os emojis votáveis na ordem dos botões. Sem teclado
+// (mensagens inline sem sessão), usa os emojis que já têm votos.
+func voteEmojis(ikb *telego.InlineKeyboardMarkup, counts map[string]int64) []string {
+	var emojis []string
+	if ikb != nil {
+		for _, row := range ikb.InlineKeyboard {
+			for _, btn := range row {
+				if strings.HasPrefix(btn.CallbackData, "vote:") {
+					emojis = append(emojis, strings.TrimPrefix(btn.CallbackData, "vote:"))
 				}
 			}
 		}
+	}
+	if len(emojis) > 0 {
+		return emojis
+	}
 
-		return nil
+	for emoji := range counts {
+		emojis = append(emojis, emoji)
 	}
+	sort.Slice(emojis, func(i, j int) bool {
+		if counts[emojis[i]] != counts[emojis[j]] {
+			return counts[emojis[i]] > counts[emojis[j]]
+		}
+		return emojis[i] < emojis[j]
+	})
+	return emojis
 }
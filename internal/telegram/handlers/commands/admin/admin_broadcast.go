@@ -10,6 +10,7 @@ import (
 	"github.com/mymmrac/telego"
 	"github.com/mymmrac/telego/telegohandler"
 	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/ratelimit"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
 	"github.com/leirbagxis/FreddyBot/pkg/parser"
 )
@@ -49,8 +50,9 @@ func NoticeCommandHandlerTelego(app *container.AppContainer) telegohandler.Handl
 		var failedUsers []string
 		sentCount := 0
 
+		noticeCtx := ratelimit.WithPriority(context.Background(), ratelimit.PriorityLow)
 		for _, user := range users {
-			_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			_, err := bot.SendMessage(noticeCtx, &telego.SendMessageParams{
 				ChatID:    telego.ChatID{ID: user.UserId},
 				Text:      noticeText,
 				ParseMode: telego.ModeHTML,
@@ -106,8 +108,9 @@ func NoticeChannelsHandlerTelego(app *container.AppContainer) telegohandler.Hand
 		var failedChannels []string
 		sentCount := 0
 
+		noticeCtx := ratelimit.WithPriority(context.Background(), ratelimit.PriorityLow)
 		for _, ch := range channels {
-			_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			_, err := bot.SendMessage(noticeCtx, &telego.SendMessageParams{
 				ChatID:      telego.ChatID{ID: ch.ID},
 				Text:        text,
 				ParseMode:   telego.ModeHTML,
@@ -238,8 +241,9 @@ func NoticeUsersReplyHandlerTelego(app *container.AppContainer) telegohandler.Ha
 		var failedUsers []string
 		sentCount := 0
 
+		noticeCtx := ratelimit.WithPriority(context.Background(), ratelimit.PriorityLow)
 		for _, user := range users {
-			_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			_, err := bot.SendMessage(noticeCtx, &telego.SendMessageParams{
 				ChatID:    telego.ChatID{ID: user.UserId},
 				Text:      noticeText,
 				ParseMode: telego.ModeHTML,
@@ -308,8 +312,9 @@ func NoticeChannelsReplyHandlerTelego(app *container.AppContainer) telegohandler
 		var failedChannels []string
 		sentCount := 0
 
+		noticeCtx := ratelimit.WithPriority(context.Background(), ratelimit.PriorityLow)
 		for _, ch := range channels {
-			_, err := bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			_, err := bot.SendMessage(noticeCtx, &telego.SendMessageParams{
 				ChatID:    telego.ChatID{ID: ch.ID},
 				Text:      noticeText,
 				ParseMode: telego.ModeHTML,
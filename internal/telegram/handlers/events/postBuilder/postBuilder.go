@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 	"unicode"
-	"unicode/utf16"
 	"unicode/utf8"
 
 	"github.com/leirbagxis/FreddyBot/internal/cache"
@@ -29,14 +28,6 @@ func isEmoji(s string) bool {
 	return true
 }
 
-func utf16CodeUnitLen(text string) int {
-	units := 0
-	for _, r := range text {
-		units += len(utf16.Encode([]rune{r}))
-	}
-	return units
-}
-
 func stripLeadingEmojiFallback(label string) string {
 	label = strings.TrimSpace(label)
 	if label == "" {
@@ -114,7 +105,7 @@ func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 		var mediaType string
 
 		// Se o usuário estiver configurando um sticker separador, o PostBuilder não deve interceptar
-		awaitingStickerChannel, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), update.Message.From.ID)
+		awaitingStickerChannel, _, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), update.Message.From.ID)
 		if awaitingStickerChannel != 0 && update.Message.Sticker != nil {
 			return nil
 		}
@@ -162,6 +153,7 @@ func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
 			MediaType:   mediaType,
 			MediaFileID: mediaID,
 			Step:        "",
+			OwnerID:     update.Message.From.ID,
 		}
 		c.CacheService.SetPostBuilderState(context.Background(), update.Message.From.ID, state)
 		recordPostBuilderEvent(c, "postbuilder_started", services.ChannelEventStatusInfo, update.Message.From.ID, 0, "", map[string]any{"media_type": mediaType, "chat_id": update.Message.Chat.ID}, nil)
@@ -271,7 +263,7 @@ func handleTextInputTelego(ctx *telegohandler.Context, update telego.Update, c *
 		// Extrair CustomEmojiID do nome (primeira linha). Mantemos o emoji textual
 		// como fallback para resultados inline, onde IconCustomEmojiID pode ser ignorado.
 		var customEmojiID string
-		firstLineLen := utf16CodeUnitLen(rawName)
+		firstLineLen := utils.UTF16Len(rawName)
 		for _, entity := range update.Message.Entities {
 			if entity.Type == "custom_emoji" && entity.Offset < firstLineLen {
 				customEmojiID = entity.CustomEmojiID
@@ -489,7 +481,7 @@ func CallbackHandlerTelego(c *container.AppContainer) telegohandler.Handler {
 		}
 
 		state, _ := c.CacheService.GetPostBuilderState(context.Background(), userID)
-		if state == nil && data != "pb-cancel" && !strings.HasPrefix(data, "pb-send-") {
+		if state == nil && data != "pb-cancel" && !strings.HasPrefix(data, "pb-send-") && !strings.HasPrefix(data, "pb-sched") {
 			_ = bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
 				CallbackQueryID: update.CallbackQuery.ID,
 				Text:            "Sessão expirada ou não encontrada.",
@@ -557,6 +549,11 @@ func CallbackHandlerTelego(c *container.AppContainer) telegohandler.Handler {
 			return nil
 		}
 
+		if strings.HasPrefix(data, "pb-sched") {
+			handleScheduleCallbackTelego(ctx, chatID, userID, data, c)
+			return nil
+		}
+
 		if strings.HasPrefix(data, "pb-send-apply:") {
 			parts := strings.Split(strings.TrimPrefix(data, "pb-send-apply:"), ":")
 			if len(parts) == 2 {
@@ -700,6 +697,10 @@ func CallbackHandlerTelego(c *container.AppContainer) telegohandler.Handler {
 					{
 						{Text: "📢 Enviar para Canais", CallbackData: "pb-send-to-channels:" + id},
 					},
+					{
+						{Text: "⏰ Agendar", CallbackData: "pb-sched:" + id},
+						{Text: "📋 Agendados", CallbackData: "pb-sched-list"},
+					},
 				},
 			}
 
@@ -780,8 +781,18 @@ func handleSendApplyTelego(ctx *telegohandler.Context, chatID, userID int64, cha
 }
 
 func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *container.AppContainer, state *cache.PostBuilderState, deleteState bool) error {
+	_, err := sendPostBuilderStateTelego(context.Background(), ctx.Bot(), chatID, state)
+
+	if deleteState {
+		c.CacheService.DeletePostBuilderState(context.Background(), userID)
+	}
+	return err
+}
+
+// sendPostBuilderStateTelego renderiza e envia a postagem sem depender do contexto
+// do handler, permitindo o reuso pelo dispatcher de agendamentos.
+func sendPostBuilderStateTelego(ctx context.Context, bot *telego.Bot, chatID int64, state *cache.PostBuilderState) (*telego.Message, error) {
 	var sb strings.Builder
-	bot := ctx.Bot()
 
 	if state.Title != "" {
 		sb.WriteString(state.Title + "\n\n")
@@ -849,10 +860,11 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		paramsPhoto.ReplyMarkup = kb
 	}
 
+	var msg *telego.Message
 	var err error
 	switch state.MediaType {
 	case "photo":
-		_, err = bot.SendPhoto(context.Background(), paramsPhoto)
+		msg, err = bot.SendPhoto(ctx, paramsPhoto)
 	case "video":
 		params := &telego.SendVideoParams{
 			ChatID:    telego.ChatID{ID: chatID},
@@ -863,7 +875,7 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendVideo(context.Background(), params)
+		msg, err = bot.SendVideo(ctx, params)
 	case "animation":
 		params := &telego.SendAnimationParams{
 			ChatID:    telego.ChatID{ID: chatID},
@@ -874,7 +886,7 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendAnimation(context.Background(), params)
+		msg, err = bot.SendAnimation(ctx, params)
 	case "audio":
 		params := &telego.SendAudioParams{
 			ChatID:    telego.ChatID{ID: chatID},
@@ -885,7 +897,7 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendAudio(context.Background(), params)
+		msg, err = bot.SendAudio(ctx, params)
 	case "document":
 		params := &telego.SendDocumentParams{
 			ChatID:    telego.ChatID{ID: chatID},
@@ -896,7 +908,7 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendDocument(context.Background(), params)
+		msg, err = bot.SendDocument(ctx, params)
 	case "sticker":
 		params := &telego.SendStickerParams{
 			ChatID:  telego.ChatID{ID: chatID},
@@ -905,7 +917,7 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendSticker(context.Background(), params)
+		msg, err = bot.SendSticker(ctx, params)
 	default:
 		params := &telego.SendMessageParams{
 			ChatID:    telego.ChatID{ID: chatID},
@@ -915,17 +927,13 @@ func sendFinalPostTelego(ctx *telegohandler.Context, chatID, userID int64, c *co
 		if kb != nil {
 			params.ReplyMarkup = kb
 		}
-		_, err = bot.SendMessage(context.Background(), params)
+		msg, err = bot.SendMessage(ctx, params)
 	}
 
 	if err != nil {
 		logger.Error("BOT", "PostBuilder: Error sending final post: %v", err)
 	}
-
-	if deleteState {
-		c.CacheService.DeletePostBuilderState(context.Background(), userID)
-	}
-	return err
+	return msg, err
 }
 
 func ChosenInlineResultHandlerTelego(c *container.AppContainer) telegohandler.ChosenInlineResultHandler {
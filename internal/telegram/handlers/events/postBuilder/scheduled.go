@@ -0,0 +1,322 @@
+package postbuilder
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/core/services"
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+const scheduledPostDispatchInterval = 20 * time.Second
+
+// Horário exibido no bot. O Brasil não tem horário de verão desde 2019.
+var scheduleDisplayLocation = time.FixedZone("BRT", -3*60*60)
+
+var scheduleDelayOptions = []struct {
+	Label   string
+	Minutes int
+}{
+	{"⏱ 30 min", 30},
+	{"⏱ 1 hora", 60},
+	{"⏱ 3 horas", 180},
+	{"⏱ 6 horas", 360},
+	{"⏱ 12 horas", 720},
+	{"⏱ 24 horas", 1440},
+}
+
+// StartScheduledPostDispatcherTelego envia as postagens agendadas quando vencem.
+// Os agendamentos ficam no banco, então tudo que venceu durante uma parada do
+// bot é enviado na primeira rodada após o restart.
+func StartScheduledPostDispatcherTelego(ctx context.Context, c *container.AppContainer) {
+	if c == nil || c.ScheduledPostService == nil || c.TelegoBot == nil {
+		return
+	}
+
+	if released, err := c.ScheduledPostService.ReleaseStale(ctx); err != nil {
+		logger.Error("BOT", "Agendamentos: erro ao liberar envios presos: %v", err)
+	} else if released > 0 {
+		logger.Warn("BOT", "Agendamentos: %d envio(s) preso(s) devolvido(s) para a fila", released)
+	}
+
+	ticker := time.NewTicker(scheduledPostDispatchInterval)
+	defer ticker.Stop()
+
+	dispatchDueScheduledPostsTelego(ctx, c)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dispatchDueScheduledPostsTelego(ctx, c)
+		}
+	}
+}
+
+func dispatchDueScheduledPostsTelego(ctx context.Context, c *container.AppContainer) {
+	posts, err := c.ScheduledPostService.ClaimDue(ctx, time.Now())
+	if err != nil {
+		logger.Error("BOT", "Agendamentos: erro ao buscar postagens vencidas: %v", err)
+	}
+
+	for i := range posts {
+		sendScheduledPostTelego(ctx, c, &posts[i])
+	}
+}
+
+func sendScheduledPostTelego(ctx context.Context, c *container.AppContainer, post *models.ScheduledPost) {
+	metadata := map[string]any{
+		"scheduled_post_id": post.ID,
+		"scheduled_at":      post.ScheduledAt,
+		"attempts":          post.Attempts,
+		"media_type":        post.MediaType,
+	}
+
+	state, err := services.DecodeScheduledPostState(post)
+	var msg *telego.Message
+	if err == nil {
+		msg, err = sendPostBuilderStateTelego(ctx, c.TelegoBot, post.ChannelID, state)
+	}
+
+	if err != nil {
+		logger.Error("BOT", "Agendamentos: falha ao enviar %s para o canal %d: %v", post.ID, post.ChannelID, err)
+		if markErr := c.ScheduledPostService.MarkFailed(ctx, post.ID, err); markErr != nil {
+			logger.Error("BOT", "Agendamentos: erro ao marcar %s como falho: %v", post.ID, markErr)
+		}
+		recordPostBuilderEvent(c, "postbuilder_scheduled_failed", services.ChannelEventStatusError, post.CreatedBy, post.ChannelID, post.SessionID, metadata, err)
+		notifyScheduledPostCreatorTelego(ctx, c, post, fmt.Sprintf("❌ Não foi possível enviar a postagem agendada para <b>%s</b>.\n\n<code>%s</code>", html.EscapeString(scheduledChannelTitle(ctx, c, post.ChannelID)), html.EscapeString(err.Error())))
+		return
+	}
+
+	messageID := 0
+	if msg != nil {
+		messageID = msg.MessageID
+	}
+	metadata["message_id"] = messageID
+	if markErr := c.ScheduledPostService.MarkSent(ctx, post.ID, messageID); markErr != nil {
+		logger.Error("BOT", "Agendamentos: erro ao marcar %s como enviado: %v", post.ID, markErr)
+	}
+	recordPostBuilderEvent(c, "postbuilder_scheduled_sent", services.ChannelEventStatusSuccess, post.CreatedBy, post.ChannelID, post.SessionID, metadata, nil)
+	logger.Bot("⏰ Postagem agendada %s enviada para o canal %d", post.ID, post.ChannelID)
+}
+
+func notifyScheduledPostCreatorTelego(ctx context.Context, c *container.AppContainer, post *models.ScheduledPost, text string) {
+	if post.CreatedBy == 0 {
+		return
+	}
+	_, _ = c.TelegoBot.SendMessage(ctx, &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: post.CreatedBy},
+		Text:      text,
+		ParseMode: telego.ModeHTML,
+	})
+}
+
+func scheduledChannelTitle(ctx context.Context, c *container.AppContainer, channelID int64) string {
+	channel, err := c.ChannelService.GetChannelWithRelations(ctx, channelID)
+	if err != nil || channel == nil || channel.Title == "" {
+		return strconv.FormatInt(channelID, 10)
+	}
+	return channel.Title
+}
+
+func userOwnsChannel(ctx context.Context, c *container.AppContainer, userID, channelID int64) bool {
+	channels, err := c.ChannelService.GetUserChannels(ctx, userID)
+	if err != nil {
+		return false
+	}
+	for _, ch := range channels {
+		if ch.ID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleScheduleCallbackTelego trata os callbacks pb-sched-*. Eles não dependem
+// do estado de edição do PostBuilder, apenas da sessão salva.
+func handleScheduleCallbackTelego(ctx *telegohandler.Context, chatID, userID int64, data string, c *container.AppContainer) {
+	switch {
+	case data == "pb-sched-list":
+		handleScheduleListTelego(ctx, chatID, userID, c)
+	case strings.HasPrefix(data, "pb-sched-cancel:"):
+		handleScheduleCancelTelego(ctx, chatID, userID, strings.TrimPrefix(data, "pb-sched-cancel:"), c)
+	case strings.HasPrefix(data, "pb-sched-at:"):
+		parts := strings.Split(strings.TrimPrefix(data, "pb-sched-at:"), ":")
+		if len(parts) != 3 {
+			return
+		}
+		channelID, _ := strconv.ParseInt(parts[0], 10, 64)
+		minutes, _ := strconv.Atoi(parts[2])
+		handleScheduleApplyTelego(ctx, chatID, userID, channelID, parts[1], minutes, c)
+	case strings.HasPrefix(data, "pb-sched-ch:"):
+		parts := strings.Split(strings.TrimPrefix(data, "pb-sched-ch:"), ":")
+		if len(parts) != 2 {
+			return
+		}
+		channelID, _ := strconv.ParseInt(parts[0], 10, 64)
+		handleScheduleTimesTelego(ctx, chatID, channelID, parts[1])
+	case strings.HasPrefix(data, "pb-sched:"):
+		handleScheduleChannelsTelego(ctx, chatID, userID, strings.TrimPrefix(data, "pb-sched:"), c)
+	}
+}
+
+func handleScheduleChannelsTelego(ctx *telegohandler.Context, chatID, userID int64, sessionID string, c *container.AppContainer) {
+	bot := ctx.Bot()
+	channels, err := c.ChannelService.GetUserChannels(context.Background(), userID)
+	if err != nil || len(channels) == 0 {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID:    telego.ChatID{ID: chatID},
+			Text:      "❌ Você não possui canais cadastrados para agendar.",
+			ParseMode: telego.ModeHTML,
+		})
+		return
+	}
+
+	var rows [][]telego.InlineKeyboardButton
+	for _, ch := range channels {
+		rows = append(rows, []telego.InlineKeyboardButton{
+			{Text: "📣 " + ch.Title, CallbackData: fmt.Sprintf("pb-sched-ch:%d:%s", ch.ID, sessionID)},
+		})
+	}
+
+	_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		Text:        "⏰ <b>Agendar Postagem</b>\n\nSelecione o canal que vai receber esta postagem:",
+		ParseMode:   telego.ModeHTML,
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+}
+
+func handleScheduleTimesTelego(ctx *telegohandler.Context, chatID, channelID int64, sessionID string) {
+	var rows [][]telego.InlineKeyboardButton
+	var row []telego.InlineKeyboardButton
+	for _, opt := range scheduleDelayOptions {
+		row = append(row, telego.InlineKeyboardButton{
+			Text:         opt.Label,
+			CallbackData: fmt.Sprintf("pb-sched-at:%d:%s:%d", channelID, sessionID, opt.Minutes),
+		})
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	_, _ = ctx.Bot().SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		Text:        "⏰ <b>Agendar Postagem</b>\n\nDaqui a quanto tempo a postagem deve ser enviada?\n\n<i>Para um horário exato, use a Dashboard.</i>",
+		ParseMode:   telego.ModeHTML,
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+}
+
+func handleScheduleApplyTelego(ctx *telegohandler.Context, chatID, userID, channelID int64, sessionID string, minutes int, c *container.AppContainer) {
+	bot := ctx.Bot()
+	if minutes <= 0 || !userOwnsChannel(context.Background(), c, userID, channelID) {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "❌ Canal inválido para agendamento.",
+		})
+		return
+	}
+
+	scheduledAt := time.Now().Add(time.Duration(minutes) * time.Minute)
+	post, err := c.ScheduledPostService.ScheduleFromSession(context.Background(), channelID, userID, sessionID, scheduledAt)
+	if err != nil {
+		recordPostBuilderEvent(c, "postbuilder_failed", services.ChannelEventStatusError, userID, channelID, sessionID, map[string]any{"action": "schedule"}, err)
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "❌ Erro ao agendar postagem: " + err.Error(),
+		})
+		return
+	}
+	recordPostBuilderEvent(c, "postbuilder_scheduled", services.ChannelEventStatusInfo, userID, channelID, sessionID, map[string]any{"scheduled_post_id": post.ID, "scheduled_at": post.ScheduledAt, "media_type": post.MediaType}, nil)
+
+	_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: chatID},
+		Text:      fmt.Sprintf("✅ Postagem agendada para <b>%s</b> (horário de Brasília).", post.ScheduledAt.In(scheduleDisplayLocation).Format("02/01/2006 15:04")),
+		ParseMode: telego.ModeHTML,
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+			{{Text: "📋 Meus Agendamentos", CallbackData: "pb-sched-list"}},
+		}},
+	})
+}
+
+func handleScheduleListTelego(ctx *telegohandler.Context, chatID, userID int64, c *container.AppContainer) {
+	bot := ctx.Bot()
+	posts, err := c.ScheduledPostService.ListPendingByCreator(context.Background(), userID)
+	if err != nil {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "❌ Erro ao carregar agendamentos.",
+		})
+		return
+	}
+	if len(posts) == 0 {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "📭 Você não possui postagens agendadas.",
+		})
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📋 <b>Postagens Agendadas</b>\n\n")
+	var rows [][]telego.InlineKeyboardButton
+	titles := make(map[int64]string)
+	for i, post := range posts {
+		title, ok := titles[post.ChannelID]
+		if !ok {
+			title = scheduledChannelTitle(context.Background(), c, post.ChannelID)
+			titles[post.ChannelID] = title
+		}
+		when := post.ScheduledAt.In(scheduleDisplayLocation).Format("02/01 15:04")
+		sb.WriteString(fmt.Sprintf("%d. <b>%s</b> — %s\n", i+1, html.EscapeString(title), when))
+		rows = append(rows, []telego.InlineKeyboardButton{
+			{Text: fmt.Sprintf("❌ Cancelar %d (%s)", i+1, when), CallbackData: "pb-sched-cancel:" + post.ID},
+		})
+	}
+
+	_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		Text:        sb.String(),
+		ParseMode:   telego.ModeHTML,
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+}
+
+func handleScheduleCancelTelego(ctx *telegohandler.Context, chatID, userID int64, postID string, c *container.AppContainer) {
+	bot := ctx.Bot()
+	post, err := c.ScheduledPostService.GetByID(context.Background(), postID)
+	if err != nil || (post.CreatedBy != userID && !userOwnsChannel(context.Background(), c, userID, post.ChannelID)) {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "❌ Agendamento não encontrado.",
+		})
+		return
+	}
+
+	if err := c.ScheduledPostService.Cancel(context.Background(), post.ChannelID, post.ID); err != nil {
+		_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+			ChatID: telego.ChatID{ID: chatID},
+			Text:   "❌ Este agendamento já foi enviado ou cancelado.",
+		})
+		return
+	}
+	recordPostBuilderEvent(c, "postbuilder_schedule_canceled", services.ChannelEventStatusInfo, userID, post.ChannelID, post.SessionID, map[string]any{"scheduled_post_id": post.ID, "scheduled_at": post.ScheduledAt}, nil)
+
+	_, _ = bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID: telego.ChatID{ID: chatID},
+		Text:   "✅ Agendamento cancelado.",
+	})
+}
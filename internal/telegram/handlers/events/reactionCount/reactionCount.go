@@ -0,0 +1,48 @@
+package reactioncount
+
+import (
+	"context"
+
+	"github.com/leirbagxis/FreddyBot/internal/container"
+	"github.com/leirbagxis/FreddyBot/internal/reactions"
+	"github.com/leirbagxis/FreddyBot/pkg/logger"
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegohandler"
+)
+
+// HandlerTelego grava as contagens de reações nativas dos posts de canais no
+// modo native, sem a reação do próprio bot. Reações pagas e com emoji
+// customizado são ignoradas.
+func HandlerTelego(c *container.AppContainer) telegohandler.Handler {
+	return func(ctx *telegohandler.Context, update telego.Update) error {
+		updated := update.MessageReactionCount
+		if updated == nil || updated.Chat.Type != telego.ChatTypeChannel {
+			return nil
+		}
+
+		channel, err := c.ChannelService.GetChannelWithRelations(context.Background(), updated.Chat.ID)
+		if err != nil || reactions.ModeOf(channel.ReactionMode) != reactions.ModeNative {
+			return nil
+		}
+
+		counts := make(map[string]int64, len(updated.Reactions))
+		for _, r := range updated.Reactions {
+			if emoji, ok := r.Type.(*telego.ReactionTypeEmoji); ok && r.TotalCount > 0 {
+				counts[reactions.Normalize(emoji.Emoji)] += int64(r.TotalCount)
+			}
+		}
+
+		// A reação do próprio bot (primeiro emoji do canal) não é voto.
+		if own := reactions.BotEmoji(channel.Reactions); counts[own] > 0 {
+			counts[own]--
+			if counts[own] == 0 {
+				delete(counts, own)
+			}
+		}
+
+		if err := c.VoteService.SetReactionCounts(context.Background(), updated.Chat.ID, updated.MessageID, counts); err != nil {
+			logger.Error("VOTE", "Erro ao salvar reações do post %d no canal %d: %v", updated.MessageID, updated.Chat.ID, err)
+		}
+		return nil
+	}
+}
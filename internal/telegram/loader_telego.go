@@ -20,6 +20,8 @@ import (
 	"github.com/leirbagxis/FreddyBot/internal/telegram/handlers/commands/tutorial"
 	"github.com/leirbagxis/FreddyBot/internal/telegram/handlers/events/addChannel"
 	"github.com/leirbagxis/FreddyBot/internal/telegram/handlers/events/postBuilder"
+	"github.com/leirbagxis/FreddyBot/internal/telegram/handlers/events/reactionCount"
+	"github.com/leirbagxis/FreddyBot/internal/votepoll"
 	"github.com/leirbagxis/FreddyBot/pkg/config"
 	"github.com/mymmrac/telego"
 	"github.com/mymmrac/telego/telegohandler"
@@ -27,6 +29,7 @@ import (
 
 func LoadHandlersTelegoWithBH(bh *telegohandler.BotHandler, c *container.AppContainer) {
 	// Middlewares
+	bh.Use(middleware.LogContextMiddlewareTelego())
 	bh.Use(middleware.SaveUserMiddlewareTelego(c))
 	bh.Use(middleware.CheckBlacklistMiddlewareTelego(c))
 	bh.Use(middleware.CheckMaintenanceMiddlewareTelego(c))
@@ -34,6 +37,9 @@ func LoadHandlersTelegoWithBH(bh *telegohandler.BotHandler, c *container.AppCont
 	// Channel Post Handler
 	bh.Handle(channelpost.HandlerTelego(c), telegohandler.AnyChannelPost())
 
+	// Reações nativas dos posts de canal
+	bh.Handle(reactioncount.HandlerTelego(c), telegohandler.AnyMessageReactionCount())
+
 	// Add Channel Handlers
 	addChannelGroup := bh.Group(telegohandler.AnyMyChatMember())
 	addChannelGroup.Use(middleware.CheckAddBotMiddlewareTelego(c))
@@ -89,6 +95,7 @@ func LoadHandlersTelegoWithBH(bh *telegohandler.BotHandler, c *container.AppCont
 	bh.Handle(callbackMyChannel.ConfigHandlerTelego(c), telegohandler.CallbackDataPrefix("config:"))
 	bh.Handle(callbackMyChannel.GroupChannelHandlerTelego(c), telegohandler.CallbackDataPrefix("gc-info:"))
 	bh.Handle(callbackVote.HandlerTelego(c), telegohandler.CallbackDataPrefix("vote:"))
+	bh.Handle(callbackVote.ResultsHandlerTelego(c), telegohandler.CallbackDataEqual(votepoll.ResultsCallback))
 	bh.Handle(callbackMyChannel.AskDeleteChannelHandlerTelego(c), telegohandler.CallbackDataEqual("del"))
 	bh.Handle(callbackMyChannel.ConfirmDeleteChannelHandlerTelego(c), telegohandler.CallbackDataPrefix("confirm-del:"))
 
@@ -99,8 +106,25 @@ func LoadHandlersTelegoWithBH(bh *telegohandler.BotHandler, c *container.AppCont
 	// Sticker Separator Callbacks
 	bh.Handle(callbackMyChannel.AskStickerSeparatorHandlerTelego(c), telegohandler.CallbackDataEqual("sptc"))
 	bh.Handle(callbackMyChannel.RequireStickerSeparatorHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-config"))
+	bh.Handle(callbackMyChannel.RequireStickerSeparatorHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-pool"))
+	bh.Handle(callbackMyChannel.RequireStickerSeparatorHandlerTelego(c), telegohandler.CallbackDataPrefix("sptc-type:"))
+	bh.Handle(callbackMyChannel.RequireStickerSeparatorHandlerTelego(c), telegohandler.CallbackDataPrefix("sptc-tag:"))
+	bh.Handle(callbackMyChannel.SeparatorTargetsHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-types"))
+	bh.Handle(callbackMyChannel.SeparatorTargetsHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-tags"))
+	bh.Handle(callbackMyChannel.SeparatorPoolHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-rot"))
+	bh.Handle(callbackMyChannel.SeparatorPoolHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-cd"))
+	bh.Handle(callbackMyChannel.SeparatorPoolHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-clear"))
+	bh.Handle(callbackMyChannel.SeparatorPoolHandlerTelego(c), telegohandler.CallbackDataEqual("sptc-clear-ok"))
 	bh.Handle(callbackMyChannel.DeleteSeparatorHandlerTelego(c), telegohandler.CallbackDataEqual("spex"))
 
+	// Caption Position Callbacks
+	bh.Handle(callbackMyChannel.CaptionPositionHandlerTelego(c), telegohandler.CallbackDataEqual("cpos"))
+	bh.Handle(callbackMyChannel.CaptionPositionHandlerTelego(c), telegohandler.CallbackDataPrefix("cpos:"))
+	bh.Handle(callbackMyChannel.CaptionPositionHandlerTelego(c), telegohandler.CallbackDataPrefix("csep:"))
+
+	// Vote Stats Callback
+	bh.Handle(callbackMyChannel.VoteStatsHandlerTelego(c), telegohandler.CallbackDataEqual("vstats"))
+
 	// Transfer Access Callbacks
 	bh.Handle(callbackMyChannel.AskTransferAccessHandlerTelego(c), telegohandler.CallbackDataEqual("paccess-info"))
 	bh.Handle(callbackMyChannel.TransferAcessHandlerTelego(c), telegohandler.CallbackDataEqual("transfer"))
@@ -133,7 +157,7 @@ func matchAwaitingStickerSeparatorTelego(c *container.AppContainer) telegohandle
 		if update.Message == nil || update.Message.From == nil {
 			return false
 		}
-		id, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), update.Message.From.ID)
+		id, _, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), update.Message.From.ID)
 		return id != 0 && update.Message.Sticker != nil
 	}
 }
@@ -195,7 +219,7 @@ func matchPostBuilderTelego(c *container.AppContainer) telegohandler.Predicate {
 
 		// Prioridade para sessões ativas
 		userId := update.Message.From.ID
-		if id, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), userId); id != 0 {
+		if id, _, _ := c.CacheService.GetAwaitingStickerSeparator(context.Background(), userId); id != 0 {
 			return false
 		}
 		if id, _ := c.CacheService.GetTransferChannel(context.Background(), userId); id != 0 {
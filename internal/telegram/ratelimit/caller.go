@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/metrics"
+	"github.com/mymmrac/telego/telegoapi"
+)
+
+// limitedMethods são os prefixos de métodos que contam para os limites de
+// mensagens do Telegram, incluindo a reação que o bot deixa em cada post.
+// Consultas (getMe, getChat...) e o long polling não passam pelo limiter.
+var limitedMethods = []string{"send", "edit", "copy", "forward", "setMessageReaction"}
+
+// editMethods usam o bucket de edição do chat em vez do bucket de envios.
+var editMethods = []string{"edit", "setMessageReaction"}
+
+// Caller envolve o caller HTTP do telego aplicando o Limiter antes de cada
+// requisição de envio e registrando o retry_after de respostas 429.
+type Caller struct {
+	next    telegoapi.Caller
+	limiter *Limiter
+}
+
+func NewCaller(next telegoapi.Caller, limiter *Limiter) *Caller {
+	return &Caller{next: next, limiter: limiter}
+}
+
+func (c *Caller) Call(ctx context.Context, url string, data *telegoapi.RequestData) (*telegoapi.Response, error) {
+	method := path.Base(url)
+	if !isLimitedMethod(method) {
+		resp, err := c.next.Call(ctx, url, data)
+		// O long polling fica fora das métricas para não dominar a contagem.
+		if method != "getUpdates" {
+			metrics.TelegramRequests.WithLabelValues(method, "none", callResult(resp, err)).Inc()
+		}
+		return resp, err
+	}
+
+	chatID := requestChatID(data)
+	kind := methodKind(method)
+	priority := PriorityFrom(ctx)
+
+	waitStart := time.Now()
+	if err := c.limiter.Wait(ctx, chatID, kind, priority); err != nil {
+		metrics.TelegramRequests.WithLabelValues(method, metrics.ChatType(chatID), "canceled").Inc()
+		return nil, err
+	}
+	metrics.TelegramRateLimitWait.WithLabelValues(priority.String()).Observe(time.Since(waitStart).Seconds())
+
+	callStart := time.Now()
+	resp, err := c.next.Call(ctx, url, data)
+	metrics.TelegramRequestDuration.WithLabelValues(method).Observe(time.Since(callStart).Seconds())
+	metrics.TelegramRequests.WithLabelValues(method, metrics.ChatType(chatID), callResult(resp, err)).Inc()
+
+	if err == nil && resp != nil && resp.Error != nil {
+		if retryAfter, ok := retryAfterFromAPIError(resp.Error); ok {
+			c.limiter.Block(chatID, kind, retryAfter)
+		}
+	}
+	return resp, err
+}
+
+func callResult(resp *telegoapi.Response, err error) string {
+	switch {
+	case err != nil:
+		return "transport_error"
+	case resp != nil && resp.Error != nil:
+		return strconv.Itoa(resp.Error.ErrorCode)
+	default:
+		return "ok"
+	}
+}
+
+func isLimitedMethod(method string) bool {
+	return hasPrefix(method, limitedMethods)
+}
+
+func methodKind(method string) Kind {
+	if hasPrefix(method, editMethods) {
+		return KindEdit
+	}
+	return KindSend
+}
+
+func hasPrefix(method string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestChatID extrai o chat_id numérico do corpo JSON ou do upload multipart
+// montado pelo Constructor. Chats por @username ficam apenas no limite global.
+func requestChatID(data *telegoapi.RequestData) int64 {
+	if data == nil {
+		return 0
+	}
+	if body, ok := data.BodyStream.(*multipartBody); ok {
+		return body.chatID
+	}
+	if len(data.BodyRaw) == 0 {
+		return 0
+	}
+	var body struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+	if err := json.Unmarshal(data.BodyRaw, &body); err != nil || len(body.ChatID) == 0 {
+		return 0
+	}
+	var chatID int64
+	if err := json.Unmarshal(body.ChatID, &chatID); err != nil {
+		return 0
+	}
+	return chatID
+}
+
+// Constructor monta as requisições como o construtor padrão do telego, mas
+// marca o corpo dos uploads multipart com o chat_id. O corpo multipart é um
+// stream, então sem isso o Caller não saberia o chat e um 429 bloquearia o
+// bucket global de todos os chats.
+type Constructor struct {
+	telegoapi.DefaultConstructor
+}
+
+type multipartBody struct {
+	io.ReadCloser
+	chatID int64
+}
+
+func (c Constructor) MultipartRequest(parameters map[string]string, filesParameters map[string]telegoapi.NamedReader) (*telegoapi.RequestData, error) {
+	data, err := c.DefaultConstructor.MultipartRequest(parameters, filesParameters)
+	if err != nil {
+		return nil, err
+	}
+	chatID, err := strconv.ParseInt(parameters["chat_id"], 10, 64)
+	if err != nil {
+		return data, nil
+	}
+	body, ok := data.BodyStream.(io.ReadCloser)
+	if !ok {
+		body = io.NopCloser(data.BodyStream)
+	}
+	data.BodyStream = &multipartBody{ReadCloser: body, chatID: chatID}
+	return data, nil
+}
+
+// RetryAfter retorna o tempo de espera de um erro 429 da Bot API.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *telegoapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	return retryAfterFromAPIError(apiErr)
+}
+
+func retryAfterFromAPIError(apiErr *telegoapi.Error) (time.Duration, bool) {
+	if apiErr.ErrorCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if apiErr.Parameters == nil || apiErr.Parameters.RetryAfter <= 0 {
+		return time.Second, true
+	}
+	return time.Duration(apiErr.Parameters.RetryAfter) * time.Second, true
+}
@@ -0,0 +1,287 @@
+// Package ratelimit controla o envio de requisições para a Bot API com token
+// buckets compartilhados: um global e um por chat, seguindo os limites do
+// Telegram. Requisições de maior prioridade passam na frente das demais.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	// PriorityLow é usada em envios em massa (broadcasts e avisos).
+	PriorityLow Priority = iota
+	// PriorityNormal é o padrão para respostas a usuários e PostBuilder.
+	PriorityNormal
+	// PriorityHigh é usada na edição de posts de canal.
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority marca o contexto com a prioridade das chamadas feitas com ele.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom retorna a prioridade do contexto, PriorityNormal quando ausente.
+func PriorityFrom(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// Kind separa os buckets por chat: o limite de 20/min de grupos e canais vale
+// para mensagens novas, então edições e reações têm um bucket próprio e não
+// disputam a cota dos envios.
+type Kind int
+
+const (
+	KindSend Kind = iota
+	KindEdit
+)
+
+// Limits define as taxas (por segundo) e rajadas de cada bucket.
+type Limits struct {
+	GlobalRate   float64
+	GlobalBurst  float64
+	PrivateRate  float64
+	PrivateBurst float64
+	GroupRate    float64
+	GroupBurst   float64
+	EditRate     float64
+	EditBurst    float64
+}
+
+// DefaultLimits segue os limites documentados do Telegram: 30 mensagens/s no
+// total, 1 mensagem/s por chat privado e 20 mensagens/min por grupo ou canal.
+// Edições e reações ficam em 1/s por chat, de qualquer tipo.
+var DefaultLimits = Limits{
+	GlobalRate:   30,
+	GlobalBurst:  30,
+	PrivateRate:  1,
+	PrivateBurst: 3,
+	GroupRate:    20.0 / 60.0,
+	GroupBurst:   10,
+	EditRate:     1,
+	EditBurst:    5,
+}
+
+// reserveFraction é a parte do bucket global que prioridades menores deixam livre.
+var reserveFraction = map[Priority]float64{
+	PriorityLow:    0.3,
+	PriorityNormal: 0.1,
+	PriorityHigh:   0,
+}
+
+const (
+	idleBucketTTL = 10 * time.Minute
+	sweepInterval = time.Minute
+	minWait       = 5 * time.Millisecond
+)
+
+type bucket struct {
+	tokens       float64
+	rate         float64
+	burst        float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newBucket(rate, burst float64, now time.Time) *bucket {
+	return &bucket{tokens: burst, rate: rate, burst: burst, last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// waitFor retorna quanto falta para o bucket ter `need` tokens disponíveis.
+func (b *bucket) waitFor(need float64, now time.Time) time.Duration {
+	if now.Before(b.blockedUntil) {
+		return b.blockedUntil.Sub(now)
+	}
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+}
+
+type chatKey struct {
+	chatID int64
+	kind   Kind
+}
+
+// Limiter aplica o bucket global e os buckets por chat.
+type Limiter struct {
+	mu        sync.Mutex
+	limits    Limits
+	global    *bucket
+	chats     map[chatKey]*bucket
+	waiting   map[Priority]int
+	lastSweep time.Time
+}
+
+func NewLimiter(limits Limits) *Limiter {
+	now := time.Now()
+	return &Limiter{
+		limits:    limits,
+		global:    newBucket(limits.GlobalRate, limits.GlobalBurst, now),
+		chats:     make(map[chatKey]*bucket),
+		waiting:   make(map[Priority]int),
+		lastSweep: now,
+	}
+}
+
+// Wait bloqueia até a requisição para chatID poder ser enviada. chatID 0
+// aplica apenas o limite global.
+func (l *Limiter) Wait(ctx context.Context, chatID int64, kind Kind, priority Priority) error {
+	// registered indica que o chamador está contado em waiting, ou seja, está
+	// aguardando o bucket global e deve ter preferência sobre prioridades menores.
+	registered := false
+	defer func() {
+		if registered {
+			l.mu.Lock()
+			l.waiting[priority]--
+			l.mu.Unlock()
+		}
+	}()
+
+	for {
+		l.mu.Lock()
+		delay, globalBound := l.reserve(chatID, kind, priority, time.Now())
+		if delay == 0 {
+			if registered {
+				l.waiting[priority]--
+				registered = false
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		if globalBound != registered {
+			if globalBound {
+				l.waiting[priority]++
+			} else {
+				l.waiting[priority]--
+			}
+			registered = globalBound
+		}
+		l.mu.Unlock()
+
+		if delay < minWait {
+			delay = minWait
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve consome os tokens quando possível e retorna 0; caso contrário
+// retorna quanto tempo esperar e se a espera é pelo bucket global. Deve ser
+// chamado com mu.
+func (l *Limiter) reserve(chatID int64, kind Kind, priority Priority, now time.Time) (time.Duration, bool) {
+	l.sweep(now)
+
+	var chatDelay time.Duration
+	var chat *bucket
+	if chatID != 0 {
+		chat = l.chatBucket(chatKey{chatID: chatID, kind: kind}, now)
+		chat.refill(now)
+		chatDelay = chat.waitFor(1, now)
+	}
+
+	// Prioridades menores esperam enquanto houver alguém mais urgente
+	// aguardando o bucket global.
+	for p := priority + 1; p <= PriorityHigh; p++ {
+		if l.waiting[p] > 0 {
+			return max(chatDelay, minWait), chatDelay == 0
+		}
+	}
+
+	l.global.refill(now)
+	need := 1 + reserveFraction[priority]*l.global.burst
+	globalDelay := l.global.waitFor(need, now)
+
+	if globalDelay > 0 || chatDelay > 0 {
+		return max(globalDelay, chatDelay), globalDelay >= chatDelay
+	}
+
+	l.global.tokens--
+	if chat != nil {
+		chat.tokens--
+	}
+	return 0, false
+}
+
+func (l *Limiter) chatBucket(key chatKey, now time.Time) *bucket {
+	b := l.chats[key]
+	if b == nil {
+		switch {
+		case key.kind == KindEdit:
+			b = newBucket(l.limits.EditRate, l.limits.EditBurst, now)
+		case key.chatID > 0:
+			b = newBucket(l.limits.PrivateRate, l.limits.PrivateBurst, now)
+		default:
+			b = newBucket(l.limits.GroupRate, l.limits.GroupBurst, now)
+		}
+		l.chats[key] = b
+	}
+	return b
+}
+
+// Block suspende o bucket do chat (ou todo o envio, quando chatID é 0) pelo
+// tempo informado pelo Telegram em um erro 429.
+func (l *Limiter) Block(chatID int64, kind Kind, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b := l.global
+	if chatID != 0 {
+		b = l.chatBucket(chatKey{chatID: chatID, kind: kind}, now)
+	}
+	if until := now.Add(retryAfter); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	b.tokens = 0
+	b.last = now
+}
+
+// sweep descarta buckets de chats ociosos para o mapa não crescer sem limite.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.chats {
+		if now.Sub(b.last) > idleBucketTTL && now.After(b.blockedUntil) {
+			delete(l.chats, key)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mymmrac/telego/telegoapi"
+)
+
+func TestLimiterPerChatBudget(t *testing.T) {
+	l := NewLimiter(Limits{GlobalRate: 100, GlobalBurst: 100, PrivateRate: 1, PrivateBurst: 2, GroupRate: 1, GroupBurst: 1})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if delay, _ := l.reserve(10, KindSend, PriorityNormal, now); delay != 0 {
+			t.Fatalf("expected burst request %d to pass, got delay %s", i, delay)
+		}
+	}
+	delay, globalBound := l.reserve(10, KindSend, PriorityNormal, now)
+	if delay == 0 || globalBound {
+		t.Fatalf("expected third request to wait on the chat bucket, got delay=%s global=%v", delay, globalBound)
+	}
+
+	// Outro chat não é afetado pelo limite do primeiro.
+	if delay, _ := l.reserve(11, KindSend, PriorityNormal, now); delay != 0 {
+		t.Fatalf("expected another chat to pass, got delay %s", delay)
+	}
+}
+
+func TestLimiterEditsDoNotUseSendBudget(t *testing.T) {
+	l := NewLimiter(Limits{GlobalRate: 100, GlobalBurst: 100, PrivateRate: 1, PrivateBurst: 1, GroupRate: 1, GroupBurst: 1, EditRate: 1, EditBurst: 3})
+	now := time.Now()
+
+	if delay, _ := l.reserve(-100123, KindSend, PriorityNormal, now); delay != 0 {
+		t.Fatalf("expected first send to pass, got delay %s", delay)
+	}
+	// O canal esgotou os envios, mas edições e reações seguem no bucket próprio.
+	for i := 0; i < 3; i++ {
+		if delay, _ := l.reserve(-100123, KindEdit, PriorityHigh, now); delay != 0 {
+			t.Fatalf("expected edit %d to pass, got delay %s", i, delay)
+		}
+	}
+	if delay, _ := l.reserve(-100123, KindEdit, PriorityHigh, now); delay == 0 {
+		t.Fatal("expected edits past the burst to wait")
+	}
+	if methodKind("editMessageCaption") != KindEdit || methodKind("setMessageReaction") != KindEdit || methodKind("sendPhoto") != KindSend {
+		t.Fatal("unexpected method kind")
+	}
+}
+
+func TestLimiterPriorityReserve(t *testing.T) {
+	l := NewLimiter(Limits{GlobalRate: 1, GlobalBurst: 10, PrivateRate: 100, PrivateBurst: 100, GroupRate: 100, GroupBurst: 100})
+	now := time.Now()
+	l.global.tokens = 3
+
+	// Com 3 tokens, broadcasts (reserva de 30%) esperam e a edição de posts passa.
+	if delay, globalBound := l.reserve(0, KindSend, PriorityLow, now); delay == 0 || !globalBound {
+		t.Fatalf("expected low priority to wait for the reserve, got delay=%s global=%v", delay, globalBound)
+	}
+	if delay, _ := l.reserve(0, KindSend, PriorityHigh, now); delay != 0 {
+		t.Fatalf("expected high priority to pass, got delay %s", delay)
+	}
+
+	// Enquanto houver alguém de prioridade maior aguardando o bucket global,
+	// prioridades menores não passam.
+	l.global.tokens = 10
+	l.waiting[PriorityHigh] = 1
+	if delay, _ := l.reserve(0, KindSend, PriorityNormal, now); delay == 0 {
+		t.Fatal("expected normal priority to yield to a waiting high priority request")
+	}
+}
+
+func TestLimiterBlockAndContext(t *testing.T) {
+	l := NewLimiter(DefaultLimits)
+	l.Block(-100123, KindSend, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, -100123, KindSend, PriorityHigh); err == nil {
+		t.Fatal("expected wait on a blocked chat to end with the context")
+	}
+	if err := l.Wait(context.Background(), -100999, KindSend, PriorityHigh); err != nil {
+		t.Fatalf("expected other chats to keep sending, got %v", err)
+	}
+	if l.waiting[PriorityHigh] != 0 {
+		t.Fatalf("expected waiting counter to be released, got %d", l.waiting[PriorityHigh])
+	}
+}
+
+type fakeCaller struct {
+	calls int
+	resp  *telegoapi.Response
+}
+
+func (f *fakeCaller) Call(ctx context.Context, url string, data *telegoapi.RequestData) (*telegoapi.Response, error) {
+	f.calls++
+	return f.resp, nil
+}
+
+func TestCallerBlocksChatOnTooManyRequests(t *testing.T) {
+	next := &fakeCaller{resp: &telegoapi.Response{Error: &telegoapi.Error{
+		ErrorCode:  429,
+		Parameters: &telegoapi.ResponseParameters{RetryAfter: 30},
+	}}}
+	l := NewLimiter(DefaultLimits)
+	caller := NewCaller(next, l)
+
+	body := []byte(fmt.Sprintf(`{"chat_id":%d,"text":"oi"}`, -100555))
+	if _, err := caller.Call(context.Background(), "https://api.telegram.org/botX/sendMessage", &telegoapi.RequestData{BodyRaw: body}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b := l.chats[chatKey{chatID: -100555, kind: KindSend}]
+	if b == nil || time.Until(b.blockedUntil) < 29*time.Second {
+		t.Fatal("expected chat to be blocked for the retry_after period")
+	}
+
+	// Métodos de consulta não passam pelo limiter.
+	if _, err := caller.Call(context.Background(), "https://api.telegram.org/botX/getMe", &telegoapi.RequestData{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", next.calls)
+	}
+}
+
+func TestCallerBlocksChatOnMultipartTooManyRequests(t *testing.T) {
+	next := &fakeCaller{resp: &telegoapi.Response{Error: &telegoapi.Error{
+		ErrorCode:  429,
+		Parameters: &telegoapi.ResponseParameters{RetryAfter: 30},
+	}}}
+	l := NewLimiter(DefaultLimits)
+	caller := NewCaller(next, l)
+
+	data, err := Constructor{}.MultipartRequest(map[string]string{"chat_id": "-100777"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer data.BodyStream.(io.Closer).Close()
+	if _, err := caller.Call(context.Background(), "https://api.telegram.org/botX/sendPhoto", data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// O upload tem chat conhecido: o 429 bloqueia só esse chat, não o global.
+	if b := l.chats[chatKey{chatID: -100777, kind: KindSend}]; b == nil || time.Until(b.blockedUntil) < 29*time.Second {
+		t.Fatal("expected the upload chat to be blocked")
+	}
+	if !l.global.blockedUntil.IsZero() {
+		t.Fatal("expected the global bucket to stay open")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := fmt.Errorf("api: %w", &telegoapi.Error{ErrorCode: 429, Parameters: &telegoapi.ResponseParameters{RetryAfter: 7}})
+	if delay, ok := RetryAfter(err); !ok || delay != 7*time.Second {
+		t.Fatalf("expected 7s retry after, got %s ok=%v", delay, ok)
+	}
+	if _, ok := RetryAfter(fmt.Errorf("api: %w", &telegoapi.Error{ErrorCode: 400})); ok {
+		t.Fatal("expected 400 not to be a rate limit error")
+	}
+}
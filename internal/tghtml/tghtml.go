@@ -0,0 +1,419 @@
+// Package tghtml valida e corrige o subconjunto de HTML aceito pela Bot API do
+// Telegram (parse_mode HTML) e mede/trunca textos pelo limite do Telegram, que
+// é contado em unidades UTF-16 do texto visível.
+package tghtml
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+// Limites da Bot API para legendas de mídia e mensagens de texto.
+const (
+	MaxCaptionLength = 1024
+	MaxTextLength    = 4096
+)
+
+// Ellipsis é anexado ao texto truncado.
+const Ellipsis = "…"
+
+var allowedTags = map[string]bool{
+	"b": true, "strong": true,
+	"i": true, "em": true,
+	"u": true, "ins": true,
+	"s": true, "strike": true, "del": true,
+	"span": true, "tg-spoiler": true,
+	"a": true, "tg-emoji": true,
+	"code": true, "pre": true,
+	"blockquote": true,
+}
+
+var (
+	tagRegex    = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)([^<>]*)>`)
+	attrRegex   = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9-]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+)))?`)
+	entityRegex = regexp.MustCompile(`^&(?:lt|gt|amp|quot|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+	langRegex   = regexp.MustCompile(`^language-[a-zA-Z0-9_+#-]+$`)
+)
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	openToken
+	closeToken
+)
+
+// token é um pedaço do HTML já normalizado. Textos sem entidades têm html igual
+// a visible; cada entidade vira um token próprio para nunca ser cortada ao meio.
+type token struct {
+	kind    tokenKind
+	name    string
+	html    string
+	visible string
+}
+
+type openTag struct {
+	name    string
+	html    string
+	dropped bool
+}
+
+// Sanitize corrige o HTML para o formato aceito pelo Telegram: tags não
+// suportadas e caracteres soltos são escapados, atributos inválidos removidos,
+// tags cruzadas reordenadas e tags abertas fechadas no final. Retorna também a
+// descrição de cada problema encontrado.
+func Sanitize(s string) (string, []string) {
+	tokens, problems := scan(s)
+	return join(tokens), problems
+}
+
+// Validate retorna o primeiro problema do HTML, ou nil quando o Telegram
+// aceitaria o texto como está.
+func Validate(s string) error {
+	if _, problems := scan(s); len(problems) > 0 {
+		return errors.New(problems[0])
+	}
+	return nil
+}
+
+// TextLen mede o texto visível (sem tags e com entidades decodificadas) em
+// unidades UTF-16, como o Telegram conta os limites.
+func TextLen(s string) int {
+	tokens, _ := scan(s)
+	return visibleLen(tokens)
+}
+
+// Truncate corta o texto visível para caber em limit unidades UTF-16,
+// preferindo terminar em um espaço, anexa Ellipsis e fecha as tags abertas.
+// O resultado é sempre HTML corrigido; truncated indica se houve corte.
+func Truncate(s string, limit int) (result string, truncated bool) {
+	tokens, _ := scan(s)
+	if visibleLen(tokens) <= limit {
+		return join(tokens), false
+	}
+
+	head, open, _ := cutTokens(tokens, limit-utils.UTF16Len(Ellipsis))
+	head = append(head, token{kind: textToken, html: Ellipsis, visible: Ellipsis})
+	return join(closeAll(head, open)), true
+}
+
+// Split divide o texto em duas partes: head cabe em limit unidades UTF-16 e
+// termina em limite de palavra, tail traz o restante com as tags que estavam
+// abertas no corte reabertas. Ambas são HTML corrigido; tail vazio indica que
+// o texto já cabia.
+func Split(s string, limit int) (head, tail string) {
+	tokens, _ := scan(s)
+	if visibleLen(tokens) <= limit {
+		return join(tokens), ""
+	}
+
+	kept, open, rest := cutTokens(tokens, limit)
+	if len(rest) > 0 && rest[0].kind == textToken && rest[0].html == rest[0].visible {
+		rest[0].html = strings.TrimLeftFunc(rest[0].html, isSpace)
+		rest[0].visible = rest[0].html
+	}
+	return join(closeAll(kept, open)), join(append(open, rest...))
+}
+
+// cutTokens mantém os tokens até budget unidades UTF-16 de texto visível.
+// Retorna os tokens mantidos (sem os fechamentos pendentes), as tags que
+// ficaram abertas e os tokens que sobraram a partir do corte.
+func cutTokens(tokens []token, budget int) (kept, open, rest []token) {
+	used := 0
+	for i, t := range tokens {
+		switch t.kind {
+		case openToken:
+			kept = append(kept, t)
+			open = append(open, t)
+			continue
+		case closeToken:
+			kept = append(kept, t)
+			open = open[:len(open)-1]
+			continue
+		}
+
+		n := utils.UTF16Len(t.visible)
+		if used+n <= budget {
+			kept = append(kept, t)
+			used += n
+			continue
+		}
+
+		rest = append(rest, tokens[i+1:]...)
+		// Entidades não são divididas; texto puro é cortado no limite.
+		if t.html == t.visible {
+			if cut := cutText(t.visible, budget-used); cut != "" {
+				kept = append(kept, token{kind: textToken, html: cut, visible: cut})
+				t = token{kind: textToken, html: t.html[len(cut):], visible: t.visible[len(cut):]}
+			}
+		}
+		rest = append([]token{t}, rest...)
+		break
+	}
+
+	// Tags abertas sem conteúdo no final passam para o restante.
+	for len(kept) > 0 && kept[len(kept)-1].kind == openToken {
+		rest = append([]token{kept[len(kept)-1]}, rest...)
+		kept = kept[:len(kept)-1]
+		open = open[:len(open)-1]
+	}
+	if len(kept) > 0 && kept[len(kept)-1].kind == textToken {
+		last := &kept[len(kept)-1]
+		if last.html == last.visible {
+			last.html = strings.TrimRightFunc(last.html, isSpace)
+			last.visible = last.html
+		}
+	}
+	return kept, open, rest
+}
+
+func closeAll(tokens, open []token) []token {
+	for i := len(open) - 1; i >= 0; i-- {
+		tokens = append(tokens, closeTokenFor(open[i].name))
+	}
+	return tokens
+}
+
+func visibleLen(tokens []token) int {
+	n := 0
+	for _, t := range tokens {
+		if t.kind == textToken {
+			n += utils.UTF16Len(t.visible)
+		}
+	}
+	return n
+}
+
+// cutText corta text em no máximo limit unidades UTF-16, recuando até o último
+// espaço quando ele está perto do fim para não partir palavras.
+func cutText(text string, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	end, n := 0, 0
+	for end < len(text) {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if n+utf16.RuneLen(r) > limit {
+			break
+		}
+		n += utf16.RuneLen(r)
+		end += size
+	}
+	cut := text[:end]
+	if idx := strings.LastIndexFunc(cut, isSpace); idx > 0 && len(cut)-idx <= 32 {
+		cut = cut[:idx]
+	}
+	return cut
+}
+
+func scan(s string) ([]token, []string) {
+	var tokens []token
+	var problems []string
+	var stack []openTag
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{kind: textToken, html: text.String(), visible: text.String()})
+			text.Reset()
+		}
+	}
+	emitEscaped := func(visible string) {
+		flush()
+		tokens = append(tokens, token{kind: textToken, html: html.EscapeString(visible), visible: visible})
+	}
+
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '&':
+			if m := entityRegex.FindString(s[i:]); m != "" {
+				flush()
+				tokens = append(tokens, token{kind: textToken, html: m, visible: html.UnescapeString(m)})
+				i += len(m)
+				continue
+			}
+			problems = append(problems, "'&' precisa ser escrito como &amp;")
+			emitEscaped("&")
+			i++
+		case '>':
+			problems = append(problems, "'>' precisa ser escrito como &gt;")
+			emitEscaped(">")
+			i++
+		case '<':
+			m := tagRegex.FindStringSubmatch(s[i:])
+			if m == nil {
+				problems = append(problems, "'<' precisa ser escrito como &lt;")
+				emitEscaped("<")
+				i++
+				continue
+			}
+			raw := m[0]
+			i += len(raw)
+			name := strings.ToLower(m[2])
+			if !allowedTags[name] {
+				problems = append(problems, fmt.Sprintf("tag <%s> não é suportada pelo Telegram", name))
+				emitEscaped(raw)
+				continue
+			}
+
+			flush()
+			if m[1] == "/" {
+				tokens, stack, problems = closeTag(tokens, stack, problems, name)
+				continue
+			}
+
+			open, problem := openTagFor(name, parseAttrs(m[3]), stack)
+			if problem != "" {
+				problems = append(problems, problem)
+			}
+			stack = append(stack, open)
+			if !open.dropped {
+				tokens = append(tokens, token{kind: openToken, name: name, html: open.html})
+			}
+			if strings.HasSuffix(strings.TrimSpace(m[3]), "/") {
+				tokens, stack, problems = closeTag(tokens, stack, problems, name)
+			}
+		default:
+			j := i + 1
+			for j < len(s) && s[j] != '&' && s[j] != '<' && s[j] != '>' {
+				j++
+			}
+			text.WriteString(s[i:j])
+			i = j
+		}
+	}
+	flush()
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].dropped {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("tag <%s> não foi fechada", stack[i].name))
+		tokens = append(tokens, closeTokenFor(stack[i].name))
+	}
+	return tokens, problems
+}
+
+// closeTag fecha a tag mais próxima com o mesmo nome. Tags abertas depois dela
+// são fechadas antes e reabertas em seguida, desfazendo o cruzamento.
+func closeTag(tokens []token, stack []openTag, problems []string, name string) ([]token, []openTag, []string) {
+	idx := -1
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].name == name {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return tokens, stack, append(problems, fmt.Sprintf("</%s> não tem tag de abertura", name))
+	}
+
+	inner := append([]openTag(nil), stack[idx+1:]...)
+	if len(inner) > 0 {
+		problems = append(problems, fmt.Sprintf("tags cruzadas: </%s> fecha antes de </%s>", name, inner[len(inner)-1].name))
+	}
+	for i := len(inner) - 1; i >= 0; i-- {
+		if !inner[i].dropped {
+			tokens = append(tokens, closeTokenFor(inner[i].name))
+		}
+	}
+	if !stack[idx].dropped {
+		tokens = append(tokens, closeTokenFor(name))
+	}
+	stack = stack[:idx]
+	for _, t := range inner {
+		stack = append(stack, t)
+		if !t.dropped {
+			tokens = append(tokens, token{kind: openToken, name: t.name, html: t.html})
+		}
+	}
+	return tokens, stack, problems
+}
+
+func closeTokenFor(name string) token {
+	return token{kind: closeToken, name: name, html: "</" + name + ">"}
+}
+
+// openTagFor monta a tag de abertura só com os atributos aceitos. Tags sem os
+// atributos obrigatórios ou em posição proibida são descartadas (o conteúdo é
+// mantido) e o motivo é retornado.
+func openTagFor(name string, attrs map[string]string, stack []openTag) (openTag, string) {
+	dropped := func(problem string) (openTag, string) {
+		return openTag{name: name, dropped: true}, problem
+	}
+
+	var parent string
+	for i := len(stack) - 1; i >= 0; i-- {
+		if !stack[i].dropped {
+			parent = stack[i].name
+			break
+		}
+	}
+	for _, t := range stack {
+		if t.dropped {
+			continue
+		}
+		if (t.name == "pre" || t.name == "code") && !(name == "code" && parent == "pre") {
+			return dropped(fmt.Sprintf("tag <%s> não pode ficar dentro de <%s>", name, t.name))
+		}
+		if t.name == name && (name == "a" || name == "blockquote") {
+			return dropped(fmt.Sprintf("tag <%s> não pode ficar dentro de outra <%s>", name, name))
+		}
+	}
+
+	switch name {
+	case "a":
+		href := strings.TrimSpace(attrs["href"])
+		if href == "" {
+			return dropped("tag <a> sem href")
+		}
+		return openTag{name: name, html: `<a href="` + html.EscapeString(html.UnescapeString(href)) + `">`}, ""
+	case "span":
+		if attrs["class"] != "tg-spoiler" {
+			return dropped(`tag <span> só é aceita com class="tg-spoiler"`)
+		}
+		return openTag{name: name, html: `<span class="tg-spoiler">`}, ""
+	case "tg-emoji":
+		id := strings.TrimSpace(attrs["emoji-id"])
+		if id == "" {
+			return dropped("tag <tg-emoji> sem emoji-id")
+		}
+		return openTag{name: name, html: `<tg-emoji emoji-id="` + html.EscapeString(id) + `">`}, ""
+	case "code":
+		if class := attrs["class"]; parent == "pre" && langRegex.MatchString(class) {
+			return openTag{name: name, html: `<code class="` + class + `">`}, ""
+		}
+	case "blockquote":
+		if _, ok := attrs["expandable"]; ok {
+			return openTag{name: name, html: "<blockquote expandable>"}, ""
+		}
+	}
+	return openTag{name: name, html: "<" + name + ">"}, ""
+}
+
+func parseAttrs(raw string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range attrRegex.FindAllStringSubmatch(raw, -1) {
+		attrs[strings.ToLower(m[1])] = m[2] + m[3] + m[4]
+	}
+	return attrs
+}
+
+func join(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.html)
+	}
+	return b.String()
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
@@ -0,0 +1,83 @@
+package tghtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		name, in, want string
+		problems       int
+	}{
+		{"valid", `<b>a</b> <a href="https://x.y/?a=1&amp;b=2">l</a> &lt;3`, `<b>a</b> <a href="https://x.y/?a=1&amp;b=2">l</a> &lt;3`, 0},
+		{"stray chars", `a < b & c > d`, `a &lt; b &amp; c &gt; d`, 3},
+		{"unknown tag", `<div>x</div>`, `&lt;div&gt;x&lt;/div&gt;`, 2},
+		{"unclosed", `<b>a <i>b`, `<b>a <i>b</i></b>`, 2},
+		{"crossed", `<b>a <i>b</b> c</i>`, `<b>a <i>b</i></b><i> c</i>`, 1},
+		{"orphan close", `a</b>`, `a`, 1},
+		{"attrs", `<B class="x">a</B> <span class="y">s</span> <span class="tg-spoiler">z</span>`, `<b>a</b> s <span class="tg-spoiler">z</span>`, 1},
+		{"link without href", `<a>x</a>`, `x`, 1},
+		{"nested in code", `<code><b>x</b></code>`, `<code>x</code>`, 1},
+		{"code in pre", `<pre><code class="language-go">x</code></pre>`, `<pre><code class="language-go">x</code></pre>`, 0},
+		{"expandable quote", `<blockquote expandable>q</blockquote>`, `<blockquote expandable>q</blockquote>`, 0},
+	}
+	for _, tc := range cases {
+		got, problems := Sanitize(tc.in)
+		if got != tc.want || len(problems) != tc.problems {
+			t.Errorf("%s: got %q with %d problems %v, want %q with %d", tc.name, got, len(problems), problems, tc.want, tc.problems)
+		}
+		if err := Validate(got); err != nil {
+			t.Errorf("%s: sanitized output is still invalid: %v", tc.name, err)
+		}
+	}
+}
+
+func TestTextLen(t *testing.T) {
+	if n := TextLen(`<b>oi</b> &amp; 😀`); n != 7 {
+		t.Fatalf("expected 7 UTF-16 units, got %d", n)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got, truncated := Truncate("<b>curto</b>", 10); truncated || got != "<b>curto</b>" {
+		t.Fatalf("unexpected truncation: %q", got)
+	}
+
+	got, truncated := Truncate("<b>uma frase longa</b> <i>demais</i>", 12)
+	if !truncated || got != "<b>uma frase…</b>" {
+		t.Fatalf("got %q", got)
+	}
+	if n := TextLen(got); n > 12 {
+		t.Fatalf("truncated text has %d units, limit 12", n)
+	}
+
+	// Entidades e emojis não são partidos ao meio.
+	got, _ = Truncate("ab&amp;😀😀😀", 5)
+	if got != "ab&amp;…" {
+		t.Fatalf("got %q", got)
+	}
+
+	long := strings.Repeat("palavra ", 200)
+	got, _ = Truncate(long, MaxCaptionLength)
+	if n := TextLen(got); n > MaxCaptionLength || !strings.HasSuffix(got, "palavra"+Ellipsis) {
+		t.Fatalf("unexpected result (%d units): ...%q", n, got[len(got)-20:])
+	}
+}
+
+func TestSplit(t *testing.T) {
+	if head, tail := Split("<b>curto</b>", 10); head != "<b>curto</b>" || tail != "" {
+		t.Fatalf("unexpected split: %q / %q", head, tail)
+	}
+
+	head, tail := Split("<b>uma frase longa</b> <i>demais</i>", 12)
+	if head != "<b>uma frase</b>" || tail != "<b>longa</b> <i>demais</i>" {
+		t.Fatalf("got %q / %q", head, tail)
+	}
+
+	// A tag aberta sem conteúdo no corte vai inteira para a segunda parte.
+	head, tail = Split("texto um <a href=\"https://t.me/x\">link</a>", 9)
+	if head != "texto um" || tail != `<a href="https://t.me/x">link</a>` {
+		t.Fatalf("got %q / %q", head, tail)
+	}
+}
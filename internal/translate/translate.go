@@ -0,0 +1,137 @@
+// Package translate traduz o texto dos posts de canal. O Translator é
+// plugável: em produção é usado o HTTPTranslator, compatível com a API do
+// LibreTranslate, e os testes podem trocá-lo por um stub local.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Modos de aplicação da tradução ao post.
+const (
+	ModeAppend  = "append"  // mantém o original e acrescenta a tradução
+	ModeReplace = "replace" // troca o original pela tradução
+)
+
+// AppendMarker separa o texto original da tradução no modo append. Também é
+// usado para remover a tradução anterior ao reprocessar edições.
+const AppendMarker = "\n\n🌐 "
+
+var languageRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+func IsValidLanguage(code string) bool {
+	return languageRegex.MatchString(code)
+}
+
+func IsValidMode(mode string) bool {
+	return mode == ModeAppend || mode == ModeReplace
+}
+
+// Translator traduz um texto em HTML do Telegram para o idioma target,
+// detectando o idioma de origem e preservando as tags.
+type Translator interface {
+	Translate(ctx context.Context, text, target string) (string, error)
+}
+
+// Cache guarda traduções já feitas, para que edições e reprocessamentos do
+// mesmo post não chamem o backend de novo.
+type Cache interface {
+	GetTranslation(ctx context.Context, key string) (string, bool)
+	SetTranslation(ctx context.Context, key, value string)
+}
+
+// CacheKey identifica a tradução de text para target.
+func CacheKey(text, target string) string {
+	sum := sha256.Sum256([]byte(text))
+	return "translation:" + target + ":" + hex.EncodeToString(sum[:])
+}
+
+type cachedTranslator struct {
+	next  Translator
+	cache Cache
+}
+
+// WithCache consulta o cache antes de chamar t e guarda as traduções novas.
+func WithCache(t Translator, cache Cache) Translator {
+	return &cachedTranslator{next: t, cache: cache}
+}
+
+func (c *cachedTranslator) Translate(ctx context.Context, text, target string) (string, error) {
+	key := CacheKey(text, target)
+	if translated, ok := c.cache.GetTranslation(ctx, key); ok {
+		return translated, nil
+	}
+	translated, err := c.next.Translate(ctx, text, target)
+	if err != nil {
+		return "", err
+	}
+	c.cache.SetTranslation(ctx, key, translated)
+	return translated, nil
+}
+
+// HTTPTranslator chama o endpoint POST /translate de uma API compatível com o
+// LibreTranslate, com format=html para manter a formatação do post.
+type HTTPTranslator struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+func NewHTTPTranslator(url, apiKey string) *HTTPTranslator {
+	return &HTTPTranslator{
+		URL:    strings.TrimRight(url, "/"),
+		APIKey: apiKey,
+		Client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+type translateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type translateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+func (t *HTTPTranslator) Translate(ctx context.Context, text, target string) (string, error) {
+	payload, err := json.Marshal(translateRequest{Q: text, Source: "auto", Target: target, Format: "html", APIKey: t.APIKey})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL+"/translate", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body translateResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("resposta inválida do tradutor (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("tradutor retornou HTTP %d: %s", resp.StatusCode, body.Error)
+	}
+	return body.TranslatedText, nil
+}
@@ -0,0 +1,65 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPTranslator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req translateRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/translate" || req.Format != "html" || req.Source != "auto" || req.APIKey != "k" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(translateResponse{Error: "bad request"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(translateResponse{TranslatedText: "[" + req.Target + "] " + req.Q})
+	}))
+	defer srv.Close()
+
+	got, err := NewHTTPTranslator(srv.URL+"/", "k").Translate(context.Background(), "<b>olá</b>", "en")
+	if err != nil || got != "[en] <b>olá</b>" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	if _, err := NewHTTPTranslator(srv.URL, "").Translate(context.Background(), "x", "en"); err == nil {
+		t.Fatal("expected error for rejected request")
+	}
+}
+
+type stubTranslator struct{ calls int }
+
+func (s *stubTranslator) Translate(_ context.Context, text, target string) (string, error) {
+	s.calls++
+	return target + ":" + text, nil
+}
+
+type mapCache map[string]string
+
+func (m mapCache) GetTranslation(_ context.Context, key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m mapCache) SetTranslation(_ context.Context, key, value string) { m[key] = value }
+
+func TestWithCache(t *testing.T) {
+	stub := &stubTranslator{}
+	tr := WithCache(stub, mapCache{})
+
+	for range 2 {
+		if got, _ := tr.Translate(context.Background(), "oi", "en"); got != "en:oi" {
+			t.Fatalf("got %q", got)
+		}
+	}
+	if got, _ := tr.Translate(context.Background(), "oi", "es"); got != "es:oi" {
+		t.Fatalf("got %q", got)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected 2 backend calls, got %d", stub.calls)
+	}
+}
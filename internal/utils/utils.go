@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"unicode/utf16"
 )
 
 func GenerateRSAKey() (*rsa.PrivateKey, error) {
@@ -139,6 +140,16 @@ func RestoreProtectedMarkdownLinks(text string, links map[string]string) string
 	return text
 }
 
+// UTF16Len conta o texto em unidades UTF-16, a unidade usada pelo Telegram nos
+// offsets de entidades e nos limites de legenda e mensagem.
+func UTF16Len(text string) int {
+	units := 0
+	for _, r := range text {
+		units += utf16.RuneLen(r)
+	}
+	return units
+}
+
 func NormalizePort(p string) string {
 	if p == "" {
 		return ":7000"
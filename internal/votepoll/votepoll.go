@@ -0,0 +1,149 @@
+// Package votepoll define as regras das votações feitas pelos botões de
+// reação: escolha única ou múltipla, contagens ocultas até o voto, prazo de
+// encerramento e o resumo de resultados.
+package votepoll
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+// Modos de votação.
+const (
+	// ModeSingle permite um voto por usuário; votar em outro emoji troca o voto.
+	ModeSingle = "single"
+	// ModeMultiple permite votar em vários emojis do mesmo post.
+	ModeMultiple = "multiple"
+)
+
+// MaxDurationMinutes é o maior prazo de votação aceito (30 dias).
+const MaxDurationMinutes = 30 * 24 * 60
+
+// ResultsCallback é o callback do botão que mostra os resultados.
+const ResultsCallback = "vote-results"
+
+// MaxAlertLen é o limite de texto do alerta de callback, em unidades UTF-16.
+const MaxAlertLen = 200
+
+const barWidth = 5
+
+// IsValidMode indica se o modo é um dos aceitos.
+func IsValidMode(mode string) bool {
+	return mode == ModeSingle || mode == ModeMultiple
+}
+
+// IsDefault indica se as configurações equivalem ao comportamento padrão
+// (escolha única, contagens visíveis e sem prazo).
+func IsDefault(settings *models.VoteSettings) bool {
+	return settings == nil || ((settings.Mode == "" || settings.Mode == ModeSingle) && !settings.HideCounts && settings.DurationMinutes == 0)
+}
+
+// New cria a votação de um post a partir das configurações do canal. O prazo
+// conta da publicação do post.
+func New(settings *models.VoteSettings, postedAt time.Time) *models.VotePoll {
+	poll := &models.VotePoll{Mode: ModeSingle}
+	if settings == nil {
+		return poll
+	}
+	if IsValidMode(settings.Mode) {
+		poll.Mode = settings.Mode
+	}
+	poll.HideCounts = settings.HideCounts
+	if settings.DurationMinutes > 0 {
+		closesAt := postedAt.Add(time.Duration(settings.DurationMinutes) * time.Minute)
+		poll.ClosesAt = &closesAt
+	}
+	return poll
+}
+
+// IsClosed indica se a votação já passou do prazo.
+func IsClosed(poll *models.VotePoll, now time.Time) bool {
+	return poll != nil && poll.ClosesAt != nil && !now.Before(*poll.ClosesAt)
+}
+
+// HidesCounts indica se os botões devem esconder as contagens. Votações
+// encerradas sempre mostram o resultado.
+func HidesCounts(poll *models.VotePoll, now time.Time) bool {
+	return poll != nil && poll.HideCounts && !IsClosed(poll, now)
+}
+
+// ButtonText monta o texto do botão de voto.
+func ButtonText(emoji string, count int64, hidden bool) string {
+	if hidden || count <= 0 {
+		return emoji
+	}
+	return fmt.Sprintf("%s %d", emoji, count)
+}
+
+// Results resume as contagens em percentuais, na ordem dos emojis do post,
+// depois de prefix. O texto sempre cabe no alerta de callback
+// (MaxAlertLen): com muitos emojis as barras e os números saem e, se ainda
+// não couber, só os mais votados são listados.
+func Results(prefix string, counts map[string]int64, emojis []string) string {
+	var total int64
+	for _, emoji := range emojis {
+		total += counts[emoji]
+	}
+	if total == 0 {
+		return truncateAlert(prefix + "Nenhum voto ainda.")
+	}
+
+	totalLine := fmt.Sprintf("Total: %d", total)
+	full := make([]string, 0, len(emojis)+1)
+	compact := make([]string, 0, len(emojis)+1)
+	for _, emoji := range emojis {
+		count := counts[emoji]
+		percent := float64(count) / float64(total) * 100
+		filled := int(percent/100*barWidth + 0.5)
+		full = append(full, fmt.Sprintf("%s %s %.0f%% (%d)", emoji, strings.Repeat("▰", filled)+strings.Repeat("▱", barWidth-filled), percent, count))
+		compact = append(compact, fmt.Sprintf("%s %.0f%%", emoji, percent))
+	}
+
+	for _, lines := range [][]string{full, compact} {
+		if text := prefix + strings.Join(append(lines, totalLine), "\n"); utils.UTF16Len(text) <= MaxAlertLen {
+			return text
+		}
+	}
+
+	// Nem compacto cabe: lista os mais votados até o limite.
+	order := make([]int, len(emojis))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return counts[emojis[order[a]]] > counts[emojis[order[b]]] })
+
+	footer := "\n…\n" + totalLine
+	text := prefix
+	for n, i := range order {
+		line := compact[i]
+		if n > 0 {
+			line = "\n" + line
+		}
+		if utils.UTF16Len(text+line+footer) > MaxAlertLen {
+			break
+		}
+		text += line
+	}
+	return truncateAlert(text + footer)
+}
+
+// truncateAlert corta o texto no limite do alerta, sem quebrar caracteres.
+func truncateAlert(text string) string {
+	if utils.UTF16Len(text) <= MaxAlertLen {
+		return text
+	}
+	units := 0
+	for i, r := range text {
+		units += utf16.RuneLen(r)
+		if units > MaxAlertLen-1 {
+			return text[:i] + "…"
+		}
+	}
+	return text
+}
@@ -0,0 +1,74 @@
+package votepoll
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leirbagxis/FreddyBot/internal/database/models"
+	"github.com/leirbagxis/FreddyBot/internal/utils"
+)
+
+func TestNewAndClose(t *testing.T) {
+	postedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	poll := New(&models.VoteSettings{Mode: ModeMultiple, HideCounts: true, DurationMinutes: 60}, postedAt)
+	if poll.Mode != ModeMultiple || !poll.HideCounts || poll.ClosesAt == nil {
+		t.Fatalf("unexpected poll: %+v", poll)
+	}
+
+	if IsClosed(poll, postedAt.Add(59*time.Minute)) || !HidesCounts(poll, postedAt.Add(59*time.Minute)) {
+		t.Fatal("expected open poll with hidden counts before the deadline")
+	}
+	if !IsClosed(poll, postedAt.Add(time.Hour)) || HidesCounts(poll, postedAt.Add(time.Hour)) {
+		t.Fatal("expected closed poll showing counts after the deadline")
+	}
+
+	if poll := New(nil, postedAt); poll.Mode != ModeSingle || poll.ClosesAt != nil || IsClosed(poll, postedAt.AddDate(1, 0, 0)) {
+		t.Fatalf("expected default poll, got %+v", poll)
+	}
+	if !IsDefault(&models.VoteSettings{ShowResults: true}) || IsDefault(&models.VoteSettings{HideCounts: true}) {
+		t.Fatal("unexpected default detection")
+	}
+}
+
+func TestResults(t *testing.T) {
+	got := Results("📊 Resultado\n\n", map[string]int64{"👍": 3, "👎": 1}, []string{"👍", "👎", "❤️"})
+	want := "📊 Resultado\n\n👍 ▰▰▰▰▱ 75% (3)\n👎 ▰▱▱▱▱ 25% (1)\n❤️ ▱▱▱▱▱ 0% (0)\nTotal: 4"
+	if got != want {
+		t.Fatalf("unexpected results:\n%s", got)
+	}
+	if !strings.HasPrefix(Results("", nil, []string{"👍"}), "Nenhum") {
+		t.Fatal("unexpected results length or empty message")
+	}
+	if ButtonText("👍", 2, true) != "👍" || ButtonText("👍", 2, false) != "👍 2" || ButtonText("👍", 0, false) != "👍" {
+		t.Fatal("unexpected button text")
+	}
+}
+
+func TestResultsFitAlertWithManyEmojis(t *testing.T) {
+	// Bandeiras ocupam 4 unidades UTF-16 cada.
+	flags := []string{"🇧🇷", "🇵🇹", "🇦🇷", "🇺🇸", "🇫🇷", "🇩🇪", "🇮🇹", "🇪🇸", "🇯🇵", "🇲🇽", "🇨🇦", "🇬🇧", "🇨🇱", "🇺🇾", "🇵🇾", "🇧🇴", "🇵🇪", "🇨🇴", "🇻🇪", "🇪🇨", "🇨🇺", "🇳🇿", "🇦🇺", "🇮🇳", "🇨🇳", "🇰🇷", "🇳🇱", "🇧🇪", "🇸🇪", "🇳🇴"}
+	counts := make(map[string]int64, len(flags))
+	for i, flag := range flags {
+		counts[flag] = int64(i + 1)
+	}
+
+	prefix := "Você votou em 🇧🇷!\n\n"
+	for _, tc := range []struct {
+		emojis []string
+		total  string
+	}{{flags[:6], "Total: 21"}, {flags, "Total: 465"}} {
+		got := Results(prefix, counts, tc.emojis)
+		if n := utils.UTF16Len(got); n > MaxAlertLen {
+			t.Fatalf("%d emojis: results have %d UTF-16 units:\n%s", len(tc.emojis), n, got)
+		}
+		if !strings.HasPrefix(got, prefix) || !strings.HasSuffix(got, tc.total) {
+			t.Fatalf("%d emojis: prefix or total missing:\n%s", len(tc.emojis), got)
+		}
+	}
+
+	// Sem espaço para todos, os mais votados aparecem primeiro.
+	if got := Results(prefix, counts, flags); !strings.HasPrefix(got, prefix+"🇳🇴 6%\n🇸🇪 6%") {
+		t.Fatalf("expected most voted first, got:\n%s", got)
+	}
+}
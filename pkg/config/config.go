@@ -5,6 +5,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata" // fusos disponíveis mesmo em imagens sem zoneinfo
 
 	"github.com/joho/godotenv"
 	"github.com/leirbagxis/FreddyBot/pkg/logger"
@@ -22,6 +24,11 @@ var (
 	AppEnv           string
 	JWTIssuer        string
 	CORSAllowOrigins []string
+	MetricsToken     string
+	TranslateAPIURL  string
+	TranslateAPIKey  string
+	// Location é o fuso das regras por horário e do rodízio por dia da semana.
+	Location *time.Location
 )
 
 func init() {
@@ -31,6 +38,12 @@ func init() {
 		}
 	}
 
+	logger.Configure(logger.Options{
+		Format:       os.Getenv("LOG_FORMAT"),        // text (padrão) ou json
+		Level:        os.Getenv("LOG_LEVEL"),         // debug, info (padrão), warn, error
+		ModuleLevels: os.Getenv("LOG_MODULE_LEVELS"), // ex: BOT=debug,PIPELINE=warn
+	})
+
 	TelegramBotToken = mustGetEnv("TELEGRAM_BOT_TOKEN")
 	RedisAddr = mustGetEnv("REDIS_HOST")
 	DatabaseFile = os.Getenv("DATABASE_FILE") // opcional
@@ -42,6 +55,10 @@ func init() {
 	AppEnv = os.Getenv("APP_ENV")         // dev ou prod
 	JWTIssuer = getEnvDefault("JWT_ISSUER", "t.me/legendasbrbot")
 	CORSAllowOrigins = parseOrigins(os.Getenv("CORS_ALLOW_ORIGINS"), WebAppURL)
+	MetricsToken = os.Getenv("METRICS_TOKEN")        // opcional, protege /metrics
+	TranslateAPIURL = os.Getenv("TRANSLATE_API_URL") // opcional, habilita a tradução automática
+	TranslateAPIKey = os.Getenv("TRANSLATE_API_KEY")
+	Location = mustLoadLocation(getEnvDefault("TIMEZONE", "America/Sao_Paulo"))
 }
 
 func mustGetEnv(key string) string {
@@ -61,6 +78,14 @@ func mustGetEnvInt64(key string) int64 {
 	return n
 }
 
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Fatalf("Environment variable TIMEZONE must be an IANA time zone: %v", err)
+	}
+	return loc
+}
+
 func getEnvDefault(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// Campos de contexto padronizados.
+const (
+	FieldCorrelationID = "correlation_id"
+	FieldRequestID     = "request_id"
+	FieldUpdateID      = "update_id"
+	FieldChannelID     = "channel_id"
+	FieldUserID        = "user_id"
+	FieldPipeline      = "pipeline"
+)
+
+type fieldsKey struct{}
+
+// With retorna um contexto com o campo anexado; logs feitos com as variantes
+// *Ctx passam a incluí-lo. Um campo repetido substitui o valor anterior.
+func With(ctx context.Context, key string, value any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	current := Fields(ctx)
+	fields := make([]slog.Attr, 0, len(current)+1)
+	for _, attr := range current {
+		if attr.Key != key {
+			fields = append(fields, attr)
+		}
+	}
+	fields = append(fields, slog.Any(key, value))
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Fields retorna os campos anexados ao contexto.
+func Fields(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return fields
+}
+
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return With(ctx, FieldCorrelationID, id)
+}
+
+// CorrelationID retorna o correlation ID do contexto, ou vazio.
+func CorrelationID(ctx context.Context) string {
+	for _, attr := range Fields(ctx) {
+		if attr.Key == FieldCorrelationID {
+			return attr.Value.String()
+		}
+	}
+	return ""
+}
+
+// NewCorrelationID gera um identificador curto para acompanhar um post ou requisição.
+func NewCorrelationID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// contextHandler adiciona os campos do contexto a cada registro.
+type contextHandler struct {
+	next slog.Handler
+}
+
+func (h contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fields := Fields(ctx); len(fields) > 0 {
+		record.AddAttrs(fields...)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{next: h.next.WithGroup(name)}
+}
@@ -1,40 +1,188 @@
 package logger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
-var (
-	infoLog  = log.New(os.Stdout, "\033[34m[INFO]\033[0m ", log.LstdFlags|log.Lmsgprefix)
-	warnLog  = log.New(os.Stdout, "\033[33m[WARN]\033[0m ", log.LstdFlags|log.Lmsgprefix)
-	errorLog = log.New(os.Stderr, "\033[31m[ERROR]\033[0m ", log.LstdFlags|log.Lmsgprefix)
-	botLog   = log.New(os.Stdout, "\033[32m[BOT]\033[0m ", log.LstdFlags|log.Lmsgprefix)
-	apiLog   = log.New(os.Stdout, "\033[36m[API]\033[0m ", log.LstdFlags|log.Lmsgprefix)
-	dbLog    = log.New(os.Stdout, "\033[35m[DB]\033[0m ", log.LstdFlags|log.Lmsgprefix)
+// Módulos com atalhos próprios. Os demais módulos são informados nas chamadas
+// de Info/Warn/Error e também aceitam nível configurado.
+const (
+	ModuleBot = "BOT"
+	ModuleAPI = "API"
+	ModuleDB  = "DB"
 )
 
+// Options configura a saída do logger.
+type Options struct {
+	// Format é "json" para uma linha JSON por evento ou "text" (padrão) para o formato colorido.
+	Format string
+	// Level é o nível padrão: debug, info, warn ou error.
+	Level string
+	// ModuleLevels sobrescreve o nível por módulo, ex: "BOT=debug,PIPELINE=warn".
+	ModuleLevels string
+	// Output e ErrOutput permitem redirecionar a saída (padrão os.Stdout e os.Stderr).
+	Output    io.Writer
+	ErrOutput io.Writer
+}
+
+type loggerState struct {
+	handler slog.Handler
+	level   slog.Level
+	modules map[string]slog.Level
+}
+
+var current atomic.Pointer[loggerState]
+
+func init() {
+	Configure(Options{})
+}
+
+// Configure troca a configuração do logger. É chamado por pkg/config depois
+// de carregar as variáveis de ambiente.
+func Configure(opts Options) {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	errOut := opts.ErrOutput
+	if errOut == nil {
+		errOut = os.Stderr
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(strings.TrimSpace(opts.Format), "json") {
+		handler = slog.NewJSONHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})
+	} else {
+		handler = newTextHandler(out, errOut)
+	}
+
+	state := &loggerState{
+		handler: contextHandler{next: handler},
+		level:   parseLevel(opts.Level, slog.LevelInfo),
+		modules: parseModuleLevels(opts.ModuleLevels),
+	}
+	current.Store(state)
+
+	// Bibliotecas que usam slog ou o pacote log padrão passam pelo mesmo handler.
+	slog.SetDefault(slog.New(state.handler))
+}
+
+// Enabled informa se o módulo registra mensagens no nível indicado.
+func Enabled(module string, level slog.Level) bool {
+	return current.Load().enabled(module, level)
+}
+
+func (s *loggerState) enabled(module string, level slog.Level) bool {
+	min, ok := s.modules[strings.ToUpper(module)]
+	if !ok {
+		min = s.level
+	}
+	return level >= min
+}
+
+func write(ctx context.Context, level slog.Level, module string, format string, v ...interface{}) {
+	state := current.Load()
+	if !state.enabled(module, level) {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	record := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, v...), 0)
+	record.AddAttrs(slog.String("module", module))
+	_ = state.handler.Handle(ctx, record)
+}
+
+func Debug(module string, format string, v ...interface{}) {
+	write(context.Background(), slog.LevelDebug, module, format, v...)
+}
+
 func Info(module string, format string, v ...interface{}) {
-	infoLog.Printf("[%s] %s", module, fmt.Sprintf(format, v...))
+	write(context.Background(), slog.LevelInfo, module, format, v...)
 }
 
 func Warn(module string, format string, v ...interface{}) {
-	warnLog.Printf("[%s] %s", module, fmt.Sprintf(format, v...))
+	write(context.Background(), slog.LevelWarn, module, format, v...)
 }
 
 func Error(module string, format string, v ...interface{}) {
-	errorLog.Printf("[%s] %s", module, fmt.Sprintf(format, v...))
+	write(context.Background(), slog.LevelError, module, format, v...)
 }
 
 func Bot(format string, v ...interface{}) {
-	botLog.Printf(format, v...)
+	write(context.Background(), slog.LevelInfo, ModuleBot, format, v...)
 }
 
 func API(format string, v ...interface{}) {
-	apiLog.Printf(format, v...)
+	write(context.Background(), slog.LevelInfo, ModuleAPI, format, v...)
 }
 
 func DB(format string, v ...interface{}) {
-	dbLog.Printf(format, v...)
+	write(context.Background(), slog.LevelInfo, ModuleDB, format, v...)
+}
+
+// Variantes com contexto: anexam os campos salvos no ctx (correlation_id,
+// update_id, channel_id, user_id, pipeline...).
+
+func DebugCtx(ctx context.Context, module string, format string, v ...interface{}) {
+	write(ctx, slog.LevelDebug, module, format, v...)
+}
+
+func InfoCtx(ctx context.Context, module string, format string, v ...interface{}) {
+	write(ctx, slog.LevelInfo, module, format, v...)
+}
+
+func WarnCtx(ctx context.Context, module string, format string, v ...interface{}) {
+	write(ctx, slog.LevelWarn, module, format, v...)
+}
+
+func ErrorCtx(ctx context.Context, module string, format string, v ...interface{}) {
+	write(ctx, slog.LevelError, module, format, v...)
+}
+
+func BotCtx(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelInfo, ModuleBot, format, v...)
+}
+
+func APICtx(ctx context.Context, format string, v ...interface{}) {
+	write(ctx, slog.LevelInfo, ModuleAPI, format, v...)
+}
+
+func parseLevel(value string, fallback slog.Level) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return fallback
+	}
+}
+
+func parseModuleLevels(value string) map[string]slog.Level {
+	levels := make(map[string]slog.Level)
+	for _, pair := range strings.Split(value, ",") {
+		module, level, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		module = strings.ToUpper(strings.TrimSpace(module))
+		if module == "" {
+			continue
+		}
+		levels[module] = parseLevel(level, slog.LevelInfo)
+	}
+	return levels
 }
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONOutputWithContextFields(t *testing.T) {
+	var out bytes.Buffer
+	Configure(Options{Format: "json", Level: "info", ModuleLevels: "PIPELINE=warn", Output: &out, ErrOutput: &out})
+	defer Configure(Options{})
+
+	ctx := WithCorrelationID(context.Background(), "abc123")
+	ctx = With(ctx, FieldChannelID, int64(-100123))
+	ctx = With(ctx, FieldChannelID, int64(-100456))
+
+	BotCtx(ctx, "post %d recebido", 7)
+	InfoCtx(ctx, "PIPELINE", "ignorado pelo nível do módulo")
+	Debug("BOT", "ignorado pelo nível padrão")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d: %q", len(lines), out.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("invalid JSON log line: %v", err)
+	}
+	if entry["msg"] != "post 7 recebido" || entry["module"] != "BOT" || entry["level"] != "INFO" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if entry[FieldCorrelationID] != "abc123" {
+		t.Errorf("expected correlation id in entry, got %v", entry[FieldCorrelationID])
+	}
+	if entry[FieldChannelID] != float64(-100456) {
+		t.Errorf("expected latest channel id to win, got %v", entry[FieldChannelID])
+	}
+	if CorrelationID(ctx) != "abc123" {
+		t.Errorf("expected CorrelationID to read the context value")
+	}
+}
+
+func TestTextOutputSplitsErrors(t *testing.T) {
+	var out, errOut bytes.Buffer
+	Configure(Options{Output: &out, ErrOutput: &errOut})
+	defer Configure(Options{})
+
+	API("servidor iniciado")
+	Error("DB", "falha: %v", "timeout")
+
+	if !strings.Contains(out.String(), "[API]") || !strings.Contains(out.String(), "servidor iniciado") {
+		t.Errorf("unexpected stdout: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "[DB]") || !strings.Contains(errOut.String(), "falha: timeout") {
+		t.Errorf("unexpected stderr: %q", errOut.String())
+	}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+var levelColors = map[slog.Level]string{
+	slog.LevelDebug: "\033[90m[DEBUG]\033[0m",
+	slog.LevelInfo:  "\033[34m[INFO]\033[0m",
+	slog.LevelWarn:  "\033[33m[WARN]\033[0m",
+	slog.LevelError: "\033[31m[ERROR]\033[0m",
+}
+
+var moduleColors = map[string]string{
+	ModuleBot: "\033[32m",
+	ModuleAPI: "\033[36m",
+	ModuleDB:  "\033[35m",
+}
+
+// textHandler mantém o formato legível de desenvolvimento:
+// "2006/01/02 15:04:05 [INFO] [BOT] mensagem chave=valor".
+type textHandler struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+	attrs  []slog.Attr
+}
+
+func newTextHandler(out, errOut io.Writer) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, out: out, errOut: errOut}
+}
+
+func (h *textHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(record.Time.Format("2006/01/02 15:04:05"))
+	b.WriteByte(' ')
+
+	label, ok := levelColors[record.Level]
+	if !ok {
+		label = "[" + record.Level.String() + "]"
+	}
+	b.WriteString(label)
+
+	var extra []slog.Attr
+	extra = append(extra, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "module" {
+			module := attr.Value.String()
+			b.WriteByte(' ')
+			if color, ok := moduleColors[module]; ok {
+				b.WriteString(color + "[" + module + "]\033[0m")
+			} else {
+				b.WriteString("[" + module + "]")
+			}
+			return true
+		}
+		extra = append(extra, attr)
+		return true
+	})
+
+	b.WriteByte(' ')
+	b.WriteString(record.Message)
+	for _, attr := range extra {
+		fmt.Fprintf(&b, " %s=%v", attr.Key, attr.Value.Any())
+	}
+	b.WriteByte('\n')
+
+	out := h.out
+	if record.Level >= slog.LevelError {
+		out = h.errOut
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *textHandler) WithGroup(string) slog.Handler {
+	return h
+}